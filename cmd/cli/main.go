@@ -112,6 +112,11 @@ func main() {
 					fmt.Println("\nHost migration begun")
 				case transport.Payload_RESPONSE_FINISH_HOST_MIGRATE:
 					fmt.Println("\nHost migration finished")
+				case transport.Payload_RESPONSE_REDIRECT:
+					redirectResponse := &roomspec.RedirectResponse{}
+
+					proto.Unmarshal(payload.Data, redirectResponse)
+					fmt.Printf("\nRoom with ID %d is on another node, reconnect to %s\n", redirectResponse.RoomID, redirectResponse.Address)
 				}
 			case websocket.CloseMessage:
 				fmt.Println("Connection closed")
@@ -29,17 +29,32 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
 	"github.com/golang/glog"
+	"github.com/gomodule/redigo/redis"
 	v1 "github.com/jamjarlabs/jamjar-relay-server/internal/api/v1"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/admin"
+	clusterapi "github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/cluster"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/lobby"
 	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/rooms"
+	templatesapi "github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/templates"
 	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/websockets"
-	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/cluster"
+	protocolv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
 	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	apispecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+	bolt "go.etcd.io/bbolt"
 )
 
 const (
-	portEnv        = "PORT"
-	addressEnv     = "ADDRESS"
-	corsOriginsEnv = "CORS_ORIGINS"
+	portEnv              = "PORT"
+	addressEnv           = "ADDRESS"
+	corsOriginsEnv       = "CORS_ORIGINS"
+	roomDatabasePathEnv  = "ROOM_DATABASE_PATH"
+	redisAddressEnv      = "REDIS_ADDRESS"
+	nodeAddressEnv       = "NODE_ADDRESS"
+	clusterAddressEnv    = "CLUSTER_ADDRESS"
+	clusterPeersEnv      = "CLUSTER_PEERS"
+	clusterSecretEnv     = "CLUSTER_SECRET"
+	roomTemplatesPathEnv = "ROOM_TEMPLATES_PATH"
 )
 
 const (
@@ -47,6 +62,31 @@ const (
 	ceilToNearest = 5
 )
 
+// reaperInterval is how often rooms and disconnected clients are checked to see if they have expired
+const reaperInterval = 5 * time.Second
+
+// quickJoinTemplate is the name of the template used to create a public room for a client quick joining when no
+// public room matching the client's filter has a free slot
+const quickJoinTemplate = "quick-join"
+
+// defaultQuickJoinTemplate is registered as the quick join template if the room templates config does not provide one
+var defaultQuickJoinTemplate = apispecv1.RoomTemplate{
+	Name: quickJoinTemplate,
+	RoomSettings: apispecv1.RoomSettings{
+		MaxClients:         8,
+		IdleTimeoutSeconds: 60,
+	},
+}
+
+// matchmakingInterval is how often matchmaking queues are checked for clients that have timed out, and for clients
+// that can now be matched
+const matchmakingInterval = time.Second
+
+const (
+	redisRoomTTL         = 30 * time.Second
+	redisRefreshInterval = 10 * time.Second
+)
+
 func main() {
 	flag.Parse()
 
@@ -70,16 +110,151 @@ func main() {
 		glog.Fatalf("Invalid %s variable provided, must be integer, %v", portEnv, err)
 	}
 
-	roomFactory := func(id, secret, maxClients int32) (roomv1.Room, error) {
-		return roomv1.NewMemoryRoom(id, secret, maxClients)
+	rand.Seed(time.Now().UTC().UnixNano())
+
+	var roomManager roomv1.Manager
+	var templateStore roomv1.TemplateStore = roomv1.NewMemoryTemplateStore()
+
+	roomDatabasePath, exists := os.LookupEnv(roomDatabasePathEnv)
+	if exists {
+		db, err := bolt.Open(roomDatabasePath, 0600, &bolt.Options{Timeout: time.Second})
+		if err != nil {
+			glog.Fatalf("Failed to open room database at %s, %v", roomDatabasePath, err)
+		}
+		defer db.Close()
+
+		roomManager, err = roomv1.NewBoltManager(db, maxClients, ceilToNearest)
+		if err != nil {
+			glog.Fatalf("Failed to load rooms from room database, %v", err)
+		}
+
+		templateStore, err = roomv1.NewBoltTemplateStore(db)
+		if err != nil {
+			glog.Fatalf("Failed to open room templates in room database, %v", err)
+		}
+	} else {
+		roomFactory := func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}
+
+		roomManager = roomv1.NewMemoryManager(maxClients, roomFactory, ceilToNearest)
+	}
+
+	redisAddress, exists := os.LookupEnv(redisAddressEnv)
+	if exists {
+		nodeAddress, exists := os.LookupEnv(nodeAddressEnv)
+		if !exists {
+			glog.Fatalf("Missing %s environment variable, required when %s is set", nodeAddressEnv, redisAddressEnv)
+		}
+
+		pool := &redis.Pool{
+			MaxIdle:     10,
+			IdleTimeout: 240 * time.Second,
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", redisAddress)
+			},
+		}
+		defer pool.Close()
+
+		redisManager := roomv1.NewRedisManager(roomManager, pool, nodeAddress, redisRoomTTL)
+
+		go func() {
+			for range time.Tick(redisRefreshInterval) {
+				err := redisManager.Refresh()
+				if err != nil {
+					glog.Errorf("Failed to refresh rooms in Redis, %v", err)
+				}
+			}
+		}()
+
+		roomManager = redisManager
+		templateStore = roomv1.NewRedisTemplateStore(pool)
 	}
 
-	rand.Seed(time.Now().UTC().UnixNano())
+	templates := roomv1.NewTemplates(templateStore)
 
-	roomManager := roomv1.NewMemoryManager(maxClients, roomFactory, ceilToNearest)
+	roomTemplatesPath, exists := os.LookupEnv(roomTemplatesPathEnv)
+	if exists {
+		file, err := os.Open(roomTemplatesPath)
+		if err != nil {
+			glog.Fatalf("Failed to open room templates at %s, %v", roomTemplatesPath, err)
+		}
 
-	protocol := &protocol.StandardProtocol{
+		err = templates.Load(file)
+		file.Close()
+		if err != nil {
+			glog.Fatalf("Failed to load room templates from %s, %v", roomTemplatesPath, err)
+		}
+	}
+
+	_, err = templates.Get(quickJoinTemplate)
+	if err != nil {
+		if _, notFound := err.(roomv1.ErrNoTemplateFound); !notFound {
+			glog.Fatalf("Failed to read quick join template, %v", err)
+		}
+		err = templates.Set(&defaultQuickJoinTemplate)
+		if err != nil {
+			glog.Fatalf("Failed to register default quick join template, %v", err)
+		}
+	}
+
+	protocol := &protocolv1.StandardProtocol{
 		RoomManager: roomManager,
+		Reliable:    protocolv1.NewReliable(protocolv1.DefaultReliableWindow, protocolv1.DefaultReliableQueueLimit),
+		Matchmaker:  protocolv1.NewMatchmaker(),
+
+		Templates:         templates,
+		QuickJoinTemplate: quickJoinTemplate,
+	}
+
+	go func() {
+		for now := range time.Tick(matchmakingInterval) {
+			protocol.ProcessMatchmaking(now)
+		}
+	}()
+
+	go func() {
+		for now := range time.Tick(reaperInterval) {
+			closed, err := protocolv1.CloseExpiredRooms(protocol, now)
+			if err != nil {
+				glog.Errorf("Failed to close expired rooms, %v", err)
+			}
+			for _, id := range closed {
+				glog.V(1).Infof("Closed expired room with ID %d", id)
+			}
+
+			err = protocolv1.ExpireDisconnectedClients(protocol, now)
+			if err != nil {
+				glog.Errorf("Failed to expire disconnected clients, %v", err)
+			}
+		}
+	}()
+
+	var clientProtocol protocolv1.Protocol = protocol
+	var clusterHandle *clusterapi.Handle
+
+	clusterAddress, exists := os.LookupEnv(clusterAddressEnv)
+	if exists {
+		clusterPeers, exists := os.LookupEnv(clusterPeersEnv)
+		if !exists {
+			glog.Fatalf("Missing %s environment variable, required when %s is set", clusterPeersEnv, clusterAddressEnv)
+		}
+
+		clusterSecret := os.Getenv(clusterSecretEnv)
+		if clusterSecret == "" {
+			glog.Fatalf("Missing %s environment variable, required when %s is set", clusterSecretEnv, clusterAddressEnv)
+		}
+
+		node := cluster.NewNode(clusterAddress, strings.Split(clusterPeers, ";"), clusterSecret, protocol)
+
+		clientProtocol = &cluster.Protocol{
+			Protocol: protocol,
+			Node:     node,
+		}
+
+		clusterHandle = &clusterapi.Handle{
+			Node: node,
+		}
 	}
 
 	router := chi.NewRouter()
@@ -96,11 +271,27 @@ func main() {
 	api := &v1.API{
 		Router: router,
 		Websocket: &websockets.Handle{
-			Protocol: protocol,
+			Protocol:  clientProtocol,
+			Protocols: []protocolv1.Protocol{clientProtocol},
 		},
 		Rooms: &rooms.Handle{
+			Protocol:  protocol,
+			Templates: templates,
+		},
+		Lobby: &lobby.Handle{
 			Protocol: protocol,
 		},
+		Templates: &templatesapi.Handle{
+			Templates: templates,
+		},
+	}
+	if snapshotter, ok := roomManager.(roomv1.Snapshotter); ok {
+		api.Admin = &admin.Handle{
+			Snapshotter: snapshotter,
+		}
+	}
+	if clusterHandle != nil {
+		api.Cluster = clusterHandle
 	}
 	api.Routes()
 
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	snapshotspec "github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	actionExport = "export"
+	actionImport = "import"
+)
+
+const (
+	snapshotPath        = "/v1/api/admin/snapshot"
+	snapshotContentType = "application/x-protobuf"
+)
+
+func main() {
+	if len(os.Args) != 4 {
+		log.Fatalf("usage: %s [%s|%s] <relay server address> <snapshot file>", os.Args[0], actionExport, actionImport)
+	}
+
+	// Get the action, the address of the relay server (e.g. http://localhost:5000), and the snapshot file
+	action := os.Args[1]
+	relayAddress := os.Args[2]
+	snapshotFile := os.Args[3]
+
+	switch action {
+	case actionExport:
+		resp, err := http.Get(relayAddress + snapshotPath)
+		if err != nil {
+			log.Fatalf("export: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			log.Fatalf("export: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("export: relay server responded with status %d, %s", resp.StatusCode, string(body))
+		}
+
+		snapshot := &snapshotspec.Snapshot{}
+		err = proto.Unmarshal(body, snapshot)
+		if err != nil {
+			log.Fatalf("export: invalid snapshot, %v", err)
+		}
+
+		err = os.WriteFile(snapshotFile, body, 0600)
+		if err != nil {
+			log.Fatalf("export: %v", err)
+		}
+
+		fmt.Printf("Exported %d rooms to %s\n", len(snapshot.Rooms), snapshotFile)
+	case actionImport:
+		body, err := os.ReadFile(snapshotFile)
+		if err != nil {
+			log.Fatalf("import: %v", err)
+		}
+
+		snapshot := &snapshotspec.Snapshot{}
+		err = proto.Unmarshal(body, snapshot)
+		if err != nil {
+			log.Fatalf("import: invalid snapshot, %v", err)
+		}
+
+		resp, err := http.Post(relayAddress+snapshotPath, snapshotContentType, bytes.NewReader(body))
+		if err != nil {
+			log.Fatalf("import: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			log.Fatalf("import: relay server responded with status %d, %s", resp.StatusCode, string(respBody))
+		}
+
+		fmt.Printf("Imported %d rooms from %s\n", len(snapshot.Rooms), snapshotFile)
+	default:
+		log.Fatalf("Unknown action, '%s'", action)
+	}
+}
@@ -6,6 +6,9 @@ require (
 	github.com/go-chi/chi v1.5.4
 	github.com/go-chi/cors v1.2.0
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
+	github.com/gomodule/redigo v1.8.4
 	github.com/gorilla/websocket v1.4.2
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/crypto v0.10.0
 	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/api"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	relayhttp "github.com/jamjarlabs/jamjar-relay-server/specs/v1/http"
+	snapshotv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
+)
+
+// Handle serves HTTP requests for maintaining the relay server
+type Handle struct {
+	Snapshotter room.Snapshotter
+}
+
+// ExportSnapshot handles a request to export the full state of all rooms as a protobuf snapshot
+func (h *Handle) ExportSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.Snapshotter.Export()
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+		})
+		return
+	}
+
+	api.HTTPSucceedProtobuf(w, snapshot)
+}
+
+// ImportSnapshot handles a request to restore rooms from a protobuf snapshot
+func (h *Handle) ImportSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot := &snapshotv1.Snapshot{}
+	if !api.HTTPReadProtobuf(w, r, snapshot) {
+		return
+	}
+
+	err := h.Snapshotter.Import(snapshot)
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrUnsupportedSnapshot:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrRequestTooManyClients:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrMaxClientTooSmall:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidTimeout:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidDisconnectedLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReservationPolicy:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReplayBufferSize:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidPermissions:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidSpectatorLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidListing:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidRateLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidProperty:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrRoomAlreadyExists:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusConflict,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+	})
+}
@@ -19,12 +19,17 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/golang/glog"
 	relayhttp "github.com/jamjarlabs/jamjar-relay-server/specs/v1/http"
+	"google.golang.org/protobuf/proto"
 )
 
+// ProtobufContentType is the content type used for API requests and responses with protobuf bodies.
+const ProtobufContentType = "application/x-protobuf"
+
 // HTTPFail writes a failed API api to the api writer provided.
 func HTTPFail(w http.ResponseWriter, failure *relayhttp.Failure) {
 	if failure.Code == http.StatusInternalServerError {
@@ -63,6 +68,54 @@ func HTTPSucceed(w http.ResponseWriter, success *relayhttp.Success) {
 	}
 }
 
+// HTTPReadProtobuf reads a protobuf message from the body of the request provided, writing a failed API response and
+// returning false if the body is missing or invalid.
+func HTTPReadProtobuf(w http.ResponseWriter, r *http.Request, message proto.Message) bool {
+	if r.Body == nil {
+		HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: "Missing body in request",
+		})
+		return false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Failed to read request body; %s", err.Error()),
+		})
+		return false
+	}
+
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid request provided, does not conform to spec; %s", err.Error()),
+		})
+		return false
+	}
+
+	return true
+}
+
+// HTTPSucceedProtobuf writes a successful API response with a protobuf message body to the api writer provided.
+func HTTPSucceedProtobuf(w http.ResponseWriter, message proto.Message) {
+	output, err := proto.Marshal(message)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	w.Header().Set("Content-Type", ProtobufContentType)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(output)
+	if err != nil {
+		glog.Error(err)
+	}
+}
+
 // NotFound provides a handler for HTTP not found events to the API.
 func NotFound() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/api"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/cluster"
+	clusterv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/cluster"
+	relayhttp "github.com/jamjarlabs/jamjar-relay-server/specs/v1/http"
+)
+
+// Handle serves HTTP requests sent between nodes in a cluster
+type Handle struct {
+	Node *cluster.Node
+}
+
+// Authenticate rejects requests that do not include the cluster's shared secret, so only other nodes in the cluster
+// can forward requests and deliver messages to this node
+func (h *Handle) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := r.Header.Get(cluster.SecretHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(h.Node.Secret)) != 1 {
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusUnauthorized,
+				Message: "Missing or invalid cluster secret",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Forward handles a request from a client connected to another node, for a room owned by this node
+func (h *Handle) Forward(w http.ResponseWriter, r *http.Request) {
+	forward := &clusterv1.Forward{}
+	if !api.HTTPReadProtobuf(w, r, forward) {
+		return
+	}
+
+	response, err := h.Node.HandleForward(forward)
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid forwarded request provided; %s", err.Error()),
+		})
+		return
+	}
+
+	api.HTTPSucceedProtobuf(w, response)
+}
+
+// Deliver handles a message sent from another node, for a client connected to this node
+func (h *Handle) Deliver(w http.ResponseWriter, r *http.Request) {
+	deliver := &clusterv1.Deliver{}
+	if !api.HTTPReadProtobuf(w, r, deliver) {
+		return
+	}
+
+	h.Node.HandleDeliver(deliver)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetRoom handles a request to check if this node owns a room with an ID
+func (h *Handle) GetRoom(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "room_id")
+	id64, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid room ID provided, must be a 32-bit integer",
+		})
+		return
+	}
+
+	if !h.Node.OwnsRoom(int32(id64)) {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("No room found with the ID %d", id64),
+		})
+		return
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+	})
+}
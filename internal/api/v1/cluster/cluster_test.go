@@ -0,0 +1,296 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/cluster"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	relayv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+const testSecret = "test-secret"
+
+type testNode struct {
+	node     *cluster.Node
+	protocol *cluster.Protocol
+	server   *httptest.Server
+}
+
+// newTestCluster starts a node for each of the secrets provided, each node is a peer of every other node
+func newTestCluster(t *testing.T, secrets ...string) []*testNode {
+	nodes := make([]*testNode, 0, len(secrets))
+	for _, secret := range secrets {
+		roomFactory := func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}
+		local := &protocol.StandardProtocol{
+			RoomManager: roomv1.NewMemoryManager(100, roomFactory, 5),
+			Reliable:    protocol.NewReliable(protocol.DefaultReliableWindow, protocol.DefaultReliableQueueLimit),
+			Matchmaker:  protocol.NewMatchmaker(),
+		}
+
+		router := chi.NewRouter()
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+
+		node := cluster.NewNode(server.URL, nil, secret, local)
+		handle := &Handle{Node: node}
+		router.Route("/v1/cluster", func(r chi.Router) {
+			r.Use(handle.Authenticate)
+			r.Post("/forward", handle.Forward)
+			r.Post("/deliver", handle.Deliver)
+			r.Get("/rooms/{room_id}", handle.GetRoom)
+		})
+
+		nodes = append(nodes, &testNode{
+			node:     node,
+			protocol: &cluster.Protocol{Protocol: local, Node: node},
+			server:   server,
+		})
+	}
+
+	for _, node := range nodes {
+		for _, peer := range nodes {
+			if peer != node {
+				node.node.Peers = append(node.node.Peers, peer.server.URL)
+			}
+		}
+	}
+
+	return nodes
+}
+
+func newTestSession() *sessionv1.Session {
+	return &sessionv1.Session{
+		Write:           make(chan []byte, 64),
+		CloseSignal:     make(chan struct{}),
+		ProtocolVersion: protocol.Version1,
+	}
+}
+
+func request(t *testing.T, flag transportv1.Payload_FlagType, message proto.Message) *transportv1.Payload {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("failed to marshal request, %v", err)
+	}
+	return &transportv1.Payload{
+		Flag: flag,
+		Data: data,
+	}
+}
+
+// expect reads messages written to a session until one with the flag provided is found
+func expect(t *testing.T, connected *sessionv1.Session, flag transportv1.Payload_FlagType) *transportv1.Payload {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-connected.Write:
+			payload := &transportv1.Payload{}
+			err := proto.Unmarshal(msg, payload)
+			if err != nil {
+				t.Fatalf("failed to unmarshal message, %v", err)
+			}
+			if payload.Flag == flag {
+				return payload
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", flag)
+		}
+	}
+}
+
+func TestClusterRelaysBetweenNodes(t *testing.T) {
+	nodes := newTestCluster(t, testSecret, testSecret)
+	owner, other := nodes[0], nodes[1]
+
+	room, err := owner.protocol.CreateRoom(roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room, %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatalf("failed to get room info, %v", err)
+	}
+
+	join := &roomspecv1.JoinRoomRequest{RoomID: info.ID, RoomSecret: info.Secret}
+
+	host := newTestSession()
+	host, hostRoom := protocol.Route(owner.protocol, request(t, transportv1.Payload_REQUEST_CONNECT, join), host, nil)
+	if hostRoom == nil {
+		t.Fatalf("host failed to join room on owning node")
+	}
+	expect(t, host, transportv1.Payload_RESPONSE_CONNECT)
+
+	guest := newTestSession()
+	guest, guestRoom := protocol.Route(other.protocol, request(t, transportv1.Payload_REQUEST_CONNECT, join), guest, nil)
+	if _, remote := guestRoom.(*roomv1.RemoteRoom); !remote {
+		t.Fatalf("expected guest to be proxied to a remote room, got %T", guestRoom)
+	}
+	expect(t, guest, transportv1.Payload_RESPONSE_CONNECT)
+	expect(t, host, transportv1.Payload_RESPONSE_CLIENT_CONNECT)
+
+	protocol.Route(other.protocol, request(t, transportv1.Payload_REQUEST_RELAY_MESSAGE, &relayv1.Relay{
+		Type: relayv1.Relay_HOST,
+		Data: []byte("to host"),
+	}), guest, guestRoom)
+	relayed := &relayv1.Relay{}
+	err = proto.Unmarshal(expect(t, host, transportv1.Payload_RESPONSE_RELAY_MESSAGE).Data, relayed)
+	if err != nil || !bytes.Equal(relayed.Data, []byte("to host")) {
+		t.Fatalf("expected host to receive message relayed from other node, got %v, %v", relayed, err)
+	}
+
+	protocol.Route(owner.protocol, request(t, transportv1.Payload_REQUEST_RELAY_MESSAGE, &relayv1.Relay{
+		Type: relayv1.Relay_BROADCAST,
+		Data: []byte("to everyone"),
+	}), host, hostRoom)
+	relayed = &relayv1.Relay{}
+	err = proto.Unmarshal(expect(t, guest, transportv1.Payload_RESPONSE_RELAY_MESSAGE).Data, relayed)
+	if err != nil || !bytes.Equal(relayed.Data, []byte("to everyone")) {
+		t.Fatalf("expected guest to receive message delivered from owning node, got %v, %v", relayed, err)
+	}
+
+	other.protocol.Disconnect(guest, guestRoom)
+	expect(t, host, transportv1.Payload_RESPONSE_CLIENT_DISCONNECT)
+}
+
+func TestClusterRejectsMismatchedSecret(t *testing.T) {
+	nodes := newTestCluster(t, testSecret, "other-secret")
+	owner, other := nodes[0], nodes[1]
+
+	room, err := owner.protocol.CreateRoom(roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room, %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatalf("failed to get room info, %v", err)
+	}
+
+	if owner := other.node.FindOwner(info.ID); owner != "" {
+		t.Errorf("expected room owner to not be found with a mismatched secret, got %s", owner)
+	}
+
+	guest := newTestSession()
+	_, guestRoom := protocol.Route(other.protocol, request(t, transportv1.Payload_REQUEST_CONNECT,
+		&roomspecv1.JoinRoomRequest{RoomID: info.ID, RoomSecret: info.Secret}), guest, nil)
+	if guestRoom != nil {
+		t.Errorf("expected guest not to join a room with a mismatched secret, got %T", guestRoom)
+	}
+}
+
+func TestFindOwnerWithUnresponsivePeer(t *testing.T) {
+	nodes := newTestCluster(t, testSecret, testSecret)
+	owner, other := nodes[0], nodes[1]
+
+	// A peer that never responds, listed before the peer that owns the room
+	release := make(chan struct{})
+	unresponsive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(unresponsive.Close)
+	t.Cleanup(func() { close(release) })
+	other.node.Peers = append([]string{unresponsive.URL}, other.node.Peers...)
+
+	room, err := owner.protocol.CreateRoom(roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room, %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatalf("failed to get room info, %v", err)
+	}
+
+	start := time.Now()
+	if found := other.node.FindOwner(info.ID); found != owner.server.URL {
+		t.Errorf("expected owner %s, got %q", owner.server.URL, found)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the owner to be found without waiting for the unresponsive peer, took %v", elapsed)
+	}
+
+	start = time.Now()
+	if found := other.node.FindOwner(info.ID + 1); found != "" {
+		t.Errorf("expected no owner for an unknown room, got %s", found)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the unresponsive peer to time out, took %v", elapsed)
+	}
+
+	// The owner is remembered, so is found without asking the peers again
+	owner.server.Close()
+	if found := other.node.FindOwner(info.ID); found != owner.server.URL {
+		t.Errorf("expected remembered owner %s, got %q", owner.server.URL, found)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	nodes := newTestCluster(t, testSecret)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		secret *string
+		want   int
+	}{
+		{name: "forward without secret", method: http.MethodPost, path: cluster.ForwardPath, want: http.StatusUnauthorized},
+		{name: "deliver without secret", method: http.MethodPost, path: cluster.DeliverPath, want: http.StatusUnauthorized},
+		{name: "room without secret", method: http.MethodGet, path: "/v1/cluster/rooms/1", want: http.StatusUnauthorized},
+		{name: "wrong secret", method: http.MethodGet, path: "/v1/cluster/rooms/1", secret: proto.String("wrong"),
+			want: http.StatusUnauthorized},
+		{name: "empty secret", method: http.MethodGet, path: "/v1/cluster/rooms/1", secret: proto.String(""),
+			want: http.StatusUnauthorized},
+		{name: "correct secret", method: http.MethodGet, path: "/v1/cluster/rooms/1", secret: proto.String(testSecret),
+			want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, nodes[0].server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("failed to create request, %v", err)
+			}
+			if tt.secret != nil {
+				req.Header.Set(cluster.SecretHeader, *tt.secret)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed, %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
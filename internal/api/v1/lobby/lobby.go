@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lobby
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/api"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	relayhttp "github.com/jamjarlabs/jamjar-relay-server/specs/v1/http"
+)
+
+// Handle serves HTTP requests from players browsing for a room to join, these requests only expose public information
+// about public rooms so are safe to serve without authentication
+type Handle struct {
+	Protocol protocol.Protocol
+}
+
+// List handles a request to list the public rooms that can be joined, filtered by the name, tag and property query
+// parameters, with properties provided as key:value, and paginated by the offset and limit query parameters
+func (h *Handle) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := room.PublicRoomFilter{
+		Name:       query.Get("name"),
+		Tags:       query["tag"],
+		Properties: make(map[string]string),
+	}
+
+	for _, property := range query["property"] {
+		parts := strings.SplitN(property, ":", 2)
+		if len(parts) != 2 {
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid property filter '%s' provided, must be in the form key:value", property),
+			})
+			return
+		}
+		filter.Properties[parts[0]] = parts[1]
+	}
+
+	offset, err := queryInt32(query, "offset")
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid offset provided, must be a 32-bit integer",
+		})
+		return
+	}
+	filter.Offset = offset
+
+	limit, err := queryInt32(query, "limit")
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid limit provided, must be a 32-bit integer",
+		})
+		return
+	}
+	filter.Limit = limit
+
+	rooms, err := h.Protocol.ListRooms()
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+		})
+		return
+	}
+
+	publicRooms, err := room.ListPublicRooms(rooms, filter)
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrInvalidFilter:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+		Data: publicRooms,
+	})
+}
+
+// queryInt32 parses an optional 32-bit integer query parameter, zero if it is not provided
+func queryInt32(query url.Values, name string) (int32, error) {
+	str := query.Get(name)
+	if str == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseInt(str, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(parsed), nil
+}
@@ -19,6 +19,7 @@ package rooms
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -32,7 +33,8 @@ import (
 
 // Handle serves HTTP requests that manage the relay server's rooms
 type Handle struct {
-	Protocol protocol.Protocol
+	Protocol  protocol.Protocol
+	Templates *room.Templates
 }
 
 // Get handles a request to get a room with an ID
@@ -105,6 +107,56 @@ func (h *Handle) Delete(w http.ResponseWriter, r *http.Request) {
 				Message: v.Message,
 			})
 			return
+		case room.ErrRoomOnOtherNode:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusMisdirectedRequest,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+	})
+}
+
+// RotateInviteCode handles a request to give a room with an ID a new invite code, after which the room's previous
+// invite code can no longer be used to join it
+func (h *Handle) RotateInviteCode(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "room_id")
+	id64, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid room ID provided, must be a 32-bit integer",
+		})
+		return
+	}
+
+	id := int32(id64)
+
+	_, err = h.Protocol.RotateInviteCode(id)
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrNoRoomFound:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusNotFound,
+				Message: v.Message,
+			})
+			return
+		case room.ErrRoomOnOtherNode:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusMisdirectedRequest,
+				Message: v.Message,
+			})
+			return
 		default:
 			api.HTTPFail(w, &relayhttp.Failure{
 				Code:    http.StatusInternalServerError,
@@ -114,8 +166,27 @@ func (h *Handle) Delete(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	rotatedRoom, err := h.Protocol.GetRoom(id)
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+		})
+		return
+	}
+
+	info, err := rotatedRoom.GetInfo()
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+		})
+		return
+	}
+
 	api.HTTPSucceed(w, &relayhttp.Success{
 		Code: http.StatusOK,
+		Data: info,
 	})
 }
 
@@ -146,8 +217,17 @@ func (h *Handle) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Failed to read room creation request; %s", err.Error()),
+		})
+		return
+	}
+
 	var createRoom apispecv1.RoomCreationRequest
-	err := json.NewDecoder(r.Body).Decode(&createRoom)
+	err = json.Unmarshal(body, &createRoom)
 	if err != nil {
 		api.HTTPFail(w, &relayhttp.Failure{
 			Code:    http.StatusBadRequest,
@@ -156,7 +236,45 @@ func (h *Handle) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newRoom, err := h.Protocol.CreateRoom(createRoom.MaxClients)
+	options, err := h.options(createRoom, body)
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrNoTemplateFound:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReservationPolicy:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidPermissions:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidRelayPolicy:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	options.Password = createRoom.Password
+
+	newRoom, err := h.Protocol.CreateRoom(options)
 	if err != nil {
 		switch v := err.(type) {
 		case room.ErrRequestTooManyClients:
@@ -171,6 +289,66 @@ func (h *Handle) Create(w http.ResponseWriter, r *http.Request) {
 				Message: v.Message,
 			})
 			return
+		case room.ErrInvalidTimeout:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidDisconnectedLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReservationPolicy:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReplayBufferSize:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidPermissions:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidSpectatorLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidListing:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidRateLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidProperty:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidPassword:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
 		default:
 			api.HTTPFail(w, &relayhttp.Failure{
 				Code:    http.StatusInternalServerError,
@@ -225,3 +403,19 @@ func (h *Handle) List(w http.ResponseWriter, r *http.Request) {
 		Data: infos,
 	})
 }
+
+// options builds the options for a room creation request, if the request names a template the room is created from
+// the template with any settings present in the request overriding the template's settings
+func (h *Handle) options(createRoom apispecv1.RoomCreationRequest, body []byte) (room.Options, error) {
+	if createRoom.Template == "" {
+		return room.OptionsFromSettings(createRoom.RoomSettings)
+	}
+
+	if h.Templates == nil {
+		return room.Options{}, room.ErrNoTemplateFound{
+			Message: "Room templates are not enabled on this server",
+		}
+	}
+
+	return h.Templates.Options(createRoom.Template, body)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/api"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	apispecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+	relayhttp "github.com/jamjarlabs/jamjar-relay-server/specs/v1/http"
+)
+
+// Handle serves HTTP requests that manage the room templates that rooms can be created from
+type Handle struct {
+	Templates *room.Templates
+}
+
+// List handles a request to list every room template
+func (h *Handle) List(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.Templates.List()
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+		})
+		return
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+		Data: templates,
+	})
+}
+
+// Get handles a request to get a room template with a name
+func (h *Handle) Get(w http.ResponseWriter, r *http.Request) {
+	template, err := h.Templates.Get(chi.URLParam(r, "template_name"))
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrNoTemplateFound:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusNotFound,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+		Data: template,
+	})
+}
+
+// Put handles a request to create or replace a room template with a name, the body is the template's room settings
+func (h *Handle) Put(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprint("Missing body in request"),
+		})
+		return
+	}
+
+	template := &apispecv1.RoomTemplate{}
+	err := json.NewDecoder(r.Body).Decode(&template.RoomSettings)
+	if err != nil {
+		api.HTTPFail(w, &relayhttp.Failure{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid room template provided; %s", err.Error()),
+		})
+		return
+	}
+	template.Name = chi.URLParam(r, "template_name")
+
+	err = h.Templates.Set(template)
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrInvalidTemplate:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrMaxClientTooSmall:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidTimeout:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidDisconnectedLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReservationPolicy:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidReplayBufferSize:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidPermissions:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidRelayPolicy:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidSpectatorLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidListing:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidRateLimit:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		case room.ErrInvalidProperty:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+		Data: template,
+	})
+}
+
+// Delete handles a request to delete a room template with a name, rooms already created from the template are not
+// affected
+func (h *Handle) Delete(w http.ResponseWriter, r *http.Request) {
+	err := h.Templates.Delete(chi.URLParam(r, "template_name"))
+	if err != nil {
+		switch v := err.(type) {
+		case room.ErrNoTemplateFound:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusNotFound,
+				Message: v.Message,
+			})
+			return
+		default:
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Internal Server Error: %s", err.Error()),
+			})
+			return
+		}
+	}
+
+	api.HTTPSucceed(w, &relayhttp.Success{
+		Code: http.StatusOK,
+	})
+}
@@ -35,13 +35,47 @@ type RoomsHandler interface {
 	Summary(w http.ResponseWriter, r *http.Request)
 	Create(w http.ResponseWriter, r *http.Request)
 	List(w http.ResponseWriter, r *http.Request)
+	RotateInviteCode(w http.ResponseWriter, r *http.Request)
 }
 
-// API ties together the API with the router and all of the API handlers.
+// LobbyHandler defines the contract for serving requests from players browsing for a room to join
+type LobbyHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+}
+
+// AdminHandler defines the contract for serving maintenance requests
+type AdminHandler interface {
+	ExportSnapshot(w http.ResponseWriter, r *http.Request)
+	ImportSnapshot(w http.ResponseWriter, r *http.Request)
+}
+
+// TemplatesHandler defines the contract for serving requests that manage room templates
+type TemplatesHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Put(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+// ClusterHandler defines the contract for serving requests sent between nodes in a cluster
+type ClusterHandler interface {
+	Authenticate(next http.Handler) http.Handler
+	Forward(w http.ResponseWriter, r *http.Request)
+	Deliver(w http.ResponseWriter, r *http.Request)
+	GetRoom(w http.ResponseWriter, r *http.Request)
+}
+
+// API ties together the API with the router and all of the API handlers, the admin, templates and cluster handlers are
+// optional and are only routed if provided. The lobby routes are separate from the API routes, as they are intended to
+// be exposed to players rather than only to trusted services.
 type API struct {
 	Router    chi.Router
 	Websocket WebsocketHandler
 	Rooms     RoomsHandler
+	Lobby     LobbyHandler
+	Admin     AdminHandler
+	Templates TemplatesHandler
+	Cluster   ClusterHandler
 }
 
 // Routes creates the endpoint routes for v1 of the API.
@@ -49,6 +83,9 @@ func (a *API) Routes() {
 	a.Router.Route("/v1", func(r chi.Router) {
 		r.NotFound(api.NotFound())
 		r.HandleFunc("/websocket", a.Websocket.Websocket)
+		r.Route("/lobby", func(r chi.Router) {
+			r.Get("/rooms", a.Lobby.List)
+		})
 		r.Route("/api", func(r chi.Router) {
 			r.Get("/summary", a.Rooms.Summary)
 			r.Route("/rooms", func(r chi.Router) {
@@ -57,8 +94,35 @@ func (a *API) Routes() {
 				r.Route("/{room_id}", func(r chi.Router) {
 					r.Get("/", a.Rooms.Get)
 					r.Delete("/", a.Rooms.Delete)
+					r.Post("/invite-code", a.Rooms.RotateInviteCode)
 				})
 			})
+			if a.Admin != nil || a.Templates != nil {
+				r.Route("/admin", func(r chi.Router) {
+					if a.Admin != nil {
+						r.Get("/snapshot", a.Admin.ExportSnapshot)
+						r.Post("/snapshot", a.Admin.ImportSnapshot)
+					}
+					if a.Templates != nil {
+						r.Route("/templates", func(r chi.Router) {
+							r.Get("/", a.Templates.List)
+							r.Route("/{template_name}", func(r chi.Router) {
+								r.Get("/", a.Templates.Get)
+								r.Put("/", a.Templates.Put)
+								r.Delete("/", a.Templates.Delete)
+							})
+						})
+					}
+				})
+			}
 		})
+		if a.Cluster != nil {
+			r.Route("/cluster", func(r chi.Router) {
+				r.Use(a.Cluster.Authenticate)
+				r.Post("/forward", a.Cluster.Forward)
+				r.Post("/deliver", a.Cluster.Deliver)
+				r.Get("/rooms/{room_id}", a.Cluster.GetRoom)
+			})
+		}
 	})
 }
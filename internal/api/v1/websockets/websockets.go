@@ -22,28 +22,51 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/gorilla/websocket"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/api/v1/api"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/encoding"
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	relayhttp "github.com/jamjarlabs/jamjar-relay-server/specs/v1/http"
 	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
 	"google.golang.org/protobuf/proto"
 )
 
+const encodingQueryParam = "encoding"
+
 // Handle is used to serve websocket requests, with goroutines maintained for reading and writing to the websocket
-// in a safe way
+// in a safe way. Protocol is the default protocol used for clients that do not perform a handshake, Protocols are
+// the protocols that a client can select between when performing a handshake
 type Handle struct {
-	Protocol protocol.Protocol
+	Protocol  protocol.Protocol
+	Protocols []protocol.Protocol
 }
 
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
+	Subprotocols: []string{encoding.ProtobufSubprotocol, encoding.JSONSubprotocol},
 }
 
 // Websocket serves the main websocket connection, upgrading the request to a websocket connection, setting up message
-// listening and writing goroutines and routing messages through the protocol
+// listening and writing goroutines and routing messages through the protocol.
+// The encoding used to write messages to the client is selected using the websocket subprotocol, or if no subprotocol
+// is negotiated the 'encoding' query parameter, defaulting to protobuf
 func (h *Handle) Websocket(w http.ResponseWriter, r *http.Request) {
+	var queryEncoding *encoding.Encoding
+	if encodingName := r.URL.Query().Get(encodingQueryParam); encodingName != "" {
+		parsed, err := encoding.Parse(encodingName)
+		if err != nil {
+			api.HTTPFail(w, &relayhttp.Failure{
+				Code:    http.StatusBadRequest,
+				Message: err.(encoding.ErrUnknownEncoding).Message,
+			})
+			return
+		}
+		queryEncoding = &parsed
+	}
+
 	c, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		glog.Errorf("upgrade: %v", err)
@@ -51,12 +74,21 @@ func (h *Handle) Websocket(w http.ResponseWriter, r *http.Request) {
 	}
 	defer c.Close()
 
+	clientEncoding := encoding.FromSubprotocol(c.Subprotocol())
+	if c.Subprotocol() == "" && queryEncoding != nil {
+		clientEncoding = *queryEncoding
+	}
+
 	connectedClient := &session.Session{
-		Write:       make(chan []byte),
-		CloseSignal: make(chan struct{}),
-		Closed:      false,
+		Write:           make(chan []byte),
+		CloseSignal:     make(chan struct{}),
+		Closed:          false,
+		ProtocolVersion: protocol.Version1,
+		Matched:         make(chan struct{}, 1),
 	}
 
+	clientProtocol := h.Protocol
+
 	go func() {
 		<-connectedClient.CloseSignal
 		c.Close()
@@ -70,7 +102,16 @@ func (h *Handle) Websocket(w http.ResponseWriter, r *http.Request) {
 				if connectedClient.Closed {
 					return
 				}
-				err := c.WriteMessage(websocket.BinaryMessage, msg)
+				messageType := websocket.BinaryMessage
+				if clientEncoding == encoding.JSON {
+					messageType = websocket.TextMessage
+				}
+				encoded, err := encoding.Transcode(clientEncoding, msg)
+				if err != nil {
+					glog.Errorf("failed to encode message to %s, %v", clientEncoding, err)
+					continue
+				}
+				err = c.WriteMessage(messageType, encoded)
 				if err != nil {
 					if connectedClient.Client == nil {
 						glog.Errorf("failed to write message to client, %v", err)
@@ -82,18 +123,38 @@ func (h *Handle) Websocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
+	// Set up read loop, messages are handed to the listen loop so that it can also be woken by the server
+	reads := make(chan readResult)
+	go func() {
+		for {
+			mt, messageData, err := c.ReadMessage()
+			select {
+			case reads <- readResult{messageType: mt, data: messageData, err: err}:
+			case <-connectedClient.CloseSignal:
+				return
+			}
+		}
+	}()
+
 	var room room.Room
 
 	// Set up listen loop
 	for {
-		if connectedClient.Closed {
+		var read readResult
+		select {
+		case <-connectedClient.CloseSignal:
 			return
+		case <-connectedClient.Matched:
+			// The server matched the client into a room, join it here so only this loop changes the client's room
+			connectedClient, room = clientProtocol.JoinMatch(connectedClient, room)
+			continue
+		case read = <-reads:
 		}
 
-		mt, messageData, err := c.ReadMessage()
+		mt, messageData, err := read.messageType, read.data, read.err
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err) {
-				h.Protocol.Disconnect(connectedClient, room)
+				clientProtocol.Disconnect(connectedClient, room)
 				return
 			}
 			if connectedClient.Client == nil {
@@ -105,38 +166,36 @@ func (h *Handle) Websocket(w http.ResponseWriter, r *http.Request) {
 		}
 
 		switch mt {
-		case websocket.BinaryMessage:
+		case websocket.BinaryMessage, websocket.TextMessage:
+			messageEncoding := encoding.Protobuf
+			if mt == websocket.TextMessage {
+				messageEncoding = encoding.JSON
+			}
+
 			payload := &transport.Payload{}
 
-			err = proto.Unmarshal(messageData, payload)
+			err = encoding.Unmarshal(messageEncoding, messageData, payload)
 			if err != nil {
 				glog.Error(err)
-				connectedClient.Write <- protocol.Fail(&transport.Error{
+				connectedClient.Write <- protocol.FailRequest(protocol.RequestID(connectedClient, payload), &transport.Error{
 					Code:    http.StatusBadRequest,
 					Message: fmt.Sprintf("Invalid message provided, does not conform to spec, %v", err),
+					Reason:  transport.Error_INVALID_REQUEST,
 				})
 				break
 			}
-			switch payload.Flag {
-			case transport.Payload_REQUEST_CONNECT:
-				connectedClient, room = h.Protocol.Connect(payload, connectedClient, room)
-			case transport.Payload_REQUEST_RECONNECT:
-				connectedClient, room = h.Protocol.Reconnect(payload, connectedClient, room)
-			case transport.Payload_REQUEST_LIST:
-				h.Protocol.List(payload, connectedClient, room)
-			case transport.Payload_REQUEST_RELAY_MESSAGE:
-				h.Protocol.RelayMessage(payload, connectedClient, room)
-			case transport.Payload_REQUEST_GRANT_HOST:
-				h.Protocol.GrantHost(payload, connectedClient, room)
-			case transport.Payload_REQUEST_KICK:
-				h.Protocol.Kick(payload, connectedClient, room)
+			if payload.Flag == transport.Payload_REQUEST_HANDSHAKE {
+				clientProtocol = h.handshake(payload, connectedClient, room, clientProtocol)
+				break
 			}
+			connectedClient, room = protocol.Route(clientProtocol, payload, connectedClient, room)
 		case websocket.CloseMessage:
-			h.Protocol.Disconnect(connectedClient, room)
+			clientProtocol.Disconnect(connectedClient, room)
 		default:
 			connectedClient.Write <- protocol.Fail(&transport.Error{
 				Code:    http.StatusBadRequest,
-				Message: "Invalid message provided, must be in binary format",
+				Message: "Invalid message provided, must be in binary (protobuf) or text (JSON) format",
+				Reason:  transport.Error_INVALID_REQUEST,
 			})
 		}
 
@@ -145,3 +204,73 @@ func (h *Handle) Websocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// readResult is a message read from a websocket connection
+type readResult struct {
+	messageType int
+	data        []byte
+	err         error
+}
+
+// handshake negotiates the protocol version and capabilities to use for the rest of the connection, returning the
+// protocol that should be used to handle the client's requests
+func (h *Handle) handshake(payload *transport.Payload, connected *session.Session, room room.Room, current protocol.Protocol) protocol.Protocol {
+	if room != nil {
+		connected.Write <- protocol.FailRequest(protocol.RequestID(connected, payload), &transport.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot perform a handshake while connected to a room",
+			Reason:  transport.Error_ALREADY_IN_ROOM,
+		})
+		return current
+	}
+
+	handshakeRequest := &transport.HandshakeRequest{}
+	err := proto.Unmarshal(payload.Data, handshakeRequest)
+	if err != nil {
+		connected.Write <- protocol.FailRequest(protocol.RequestID(connected, payload), &transport.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid handshake request provided, does not conform to spec, %v", err),
+			Reason:  transport.Error_INVALID_REQUEST,
+		})
+		return current
+	}
+
+	selected, version, capabilities, err := protocol.Negotiate(h.Protocols, handshakeRequest)
+	if err != nil {
+		switch v := err.(type) {
+		case protocol.ErrUnsupportedVersion:
+			connected.Write <- protocol.FailRequest(protocol.RequestID(connected, payload), &transport.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  protocol.Reason(err),
+			})
+			return current
+		default:
+			connected.Write <- protocol.FailRequest(protocol.RequestID(connected, payload), &transport.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to negotiate protocol, %v", err),
+				Reason:  transport.Error_INTERNAL,
+			})
+			return current
+		}
+	}
+
+	connected.ProtocolVersion = version
+	connected.Capabilities = capabilities
+
+	responseData, err := proto.Marshal(&transport.HandshakeResponse{
+		ProtocolVersion: version,
+		Capabilities:    capabilities,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- protocol.SucceedRequest(protocol.RequestID(connected, payload), &transport.Payload{
+		Flag: transport.Payload_RESPONSE_HANDSHAKE,
+		Data: responseData,
+	})
+
+	return selected
+}
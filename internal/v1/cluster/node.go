@@ -0,0 +1,448 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	clusterv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/cluster"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ForwardPath is the path that nodes send requests from clients to, for rooms owned by the receiving node
+	ForwardPath = "/v1/cluster/forward"
+	// DeliverPath is the path that nodes send messages to, for clients connected to the receiving node
+	DeliverPath = "/v1/cluster/deliver"
+	// RoomPathFormat is the path used to check if the receiving node owns a room
+	RoomPathFormat = "/v1/cluster/rooms/%d"
+	// ContentType is the content type of messages sent between nodes
+	ContentType = "application/x-protobuf"
+	// SecretHeader is the header nodes send the cluster's shared secret in, requests between nodes without the secret
+	// are rejected
+	SecretHeader = "X-Cluster-Secret"
+)
+
+const requestTimeout = 10 * time.Second
+
+// probeTimeout is how long a peer is given to respond when asked if it owns a room, every peer is asked at once so a
+// peer that is down delays finding a room's owner by at most this long
+const probeTimeout = 2 * time.Second
+
+// ownerCacheTTL is how long the peer found to own a room is remembered before peers are asked again
+const ownerCacheTTL = time.Minute
+
+// deliverTimeout is how long a message delivered from a peer waits for the client's session to accept it before the
+// message is dropped
+const deliverTimeout = 5 * time.Second
+
+// NewNode creates a new cluster node, with the address other nodes can reach it at, the addresses of its peers, the
+// secret shared by every node in the cluster, and the local protocol used to handle requests for rooms owned by this
+// node
+func NewNode(address string, peers []string, secret string, local protocol.Protocol) *Node {
+	return &Node{
+		Address:    address,
+		Peers:      peers,
+		Secret:     secret,
+		Local:      local,
+		Client:     &http.Client{Timeout: requestTimeout},
+		sessions:   make(map[uint64]*sessionv1.Session),
+		sessionIDs: make(map[*sessionv1.Session]uint64),
+		proxies:    make(map[proxyKey]*proxy),
+		owners:     make(map[int32]cachedOwner),
+	}
+}
+
+// Node is a relay server node in a cluster of nodes, connected to its peers over an inter-node bus. Clients can join
+// rooms owned by any node in the cluster; if the room is owned by a peer the client's session is proxied to that peer,
+// with the client's requests forwarded to the peer and any messages for the client delivered back to this node
+type Node struct {
+	Address string
+	Peers   []string
+	// Secret is shared by every node in the cluster, authenticating requests sent between nodes
+	Secret string
+	Local  protocol.Protocol
+	Client *http.Client
+
+	mutex         sync.Mutex
+	nextSessionID uint64
+	// sessions are the sessions connected to this node that are proxied to rooms owned by peers
+	sessions   map[uint64]*sessionv1.Session
+	sessionIDs map[*sessionv1.Session]uint64
+	// proxies are the sessions connected to peers that are proxied to rooms owned by this node
+	proxies map[proxyKey]*proxy
+	// owners are the peers recently found to own rooms
+	owners map[int32]cachedOwner
+}
+
+type cachedOwner struct {
+	peer    string
+	expires time.Time
+}
+
+type proxyKey struct {
+	node      string
+	sessionID uint64
+}
+
+type proxy struct {
+	session *sessionv1.Session
+	room    roomv1.Room
+	stop    chan struct{}
+}
+
+// OwnsRoom determines if a room is owned by this node
+func (n *Node) OwnsRoom(roomID int32) bool {
+	room, err := n.Local.GetRoom(roomID)
+	if err != nil {
+		return false
+	}
+	_, remote := room.(*roomv1.RemoteRoom)
+	return !remote
+}
+
+// FindOwner finds the peer that owns a room, returning its address, if no peer owns the room an empty address is
+// returned. Peers recently found to own the room are remembered, otherwise every peer is asked at once, waiting at
+// most the probe timeout for each to respond
+func (n *Node) FindOwner(roomID int32) string {
+	now := time.Now()
+
+	n.mutex.Lock()
+	cached, exists := n.owners[roomID]
+	n.mutex.Unlock()
+
+	if exists && now.Before(cached.expires) {
+		return cached.peer
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	found := make(chan string, len(n.Peers))
+	for _, peer := range n.Peers {
+		go func(peer string) {
+			found <- n.probe(ctx, peer, roomID)
+		}(peer)
+	}
+
+	owner := ""
+	for range n.Peers {
+		owner = <-found
+		if owner != "" {
+			break
+		}
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if owner == "" {
+		delete(n.owners, roomID)
+		return ""
+	}
+	n.owners[roomID] = cachedOwner{
+		peer:    owner,
+		expires: now.Add(ownerCacheTTL),
+	}
+	return owner
+}
+
+// ForgetOwner removes the remembered owner of a room, so that peers are asked again the next time its owner is needed
+func (n *Node) ForgetOwner(roomID int32) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.owners, roomID)
+}
+
+// probe asks a peer if it owns a room, returning the peer's address if it does
+func (n *Node) probe(ctx context.Context, peer string, roomID int32) string {
+	resp, err := n.send(ctx, http.MethodGet, peer+fmt.Sprintf(RoomPathFormat, roomID), nil)
+	if err != nil {
+		if ctx.Err() != context.Canceled {
+			glog.Errorf("Failed to check if node %s owns room with ID %d, %v", peer, roomID, err)
+		}
+		return ""
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	return peer
+}
+
+// Forward sends a request from a session connected to this node to the peer that owns the session's room, returning
+// if the session is in a room on the peer after the request has been handled
+func (n *Node) Forward(peer string, payload *transportv1.Payload, connected *sessionv1.Session) (bool, error) {
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+
+	forward := &clusterv1.Forward{
+		Node:            n.Address,
+		SessionID:       n.register(connected),
+		Payload:         payloadBytes,
+		ProtocolVersion: connected.ProtocolVersion,
+		Capabilities:    connected.Capabilities,
+	}
+
+	response := &clusterv1.ForwardResponse{}
+	err = n.post(peer, ForwardPath, forward, response)
+	if err != nil {
+		return false, err
+	}
+
+	if !response.InRoom {
+		n.unregister(connected)
+	}
+
+	return response.InRoom, nil
+}
+
+// ForwardDisconnect tells the peer that owns a session's room that the session has disconnected from this node
+func (n *Node) ForwardDisconnect(peer string, connected *sessionv1.Session) error {
+	sessionID, exists := n.unregister(connected)
+	if !exists {
+		// Session already removed, e.g. the peer closed the session
+		return nil
+	}
+
+	return n.post(peer, ForwardPath, &clusterv1.Forward{
+		Node:       n.Address,
+		SessionID:  sessionID,
+		Disconnect: true,
+	}, &clusterv1.ForwardResponse{})
+}
+
+// HandleForward handles a request forwarded from a peer, for a session connected to the peer that is in (or is
+// joining) a room owned by this node
+func (n *Node) HandleForward(forward *clusterv1.Forward) (*clusterv1.ForwardResponse, error) {
+	key := proxyKey{
+		node:      forward.Node,
+		sessionID: forward.SessionID,
+	}
+
+	n.mutex.Lock()
+	proxied, exists := n.proxies[key]
+	n.mutex.Unlock()
+
+	if forward.Disconnect {
+		if exists {
+			n.removeProxy(key)
+			n.Local.Disconnect(proxied.session, proxied.room)
+		}
+		return &clusterv1.ForwardResponse{}, nil
+	}
+
+	payload := &transportv1.Payload{}
+	err := proto.Unmarshal(forward.Payload, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		proxied = n.newProxy(key)
+	}
+
+	proxied.session.ProtocolVersion = forward.ProtocolVersion
+	proxied.session.Capabilities = forward.Capabilities
+
+	proxied.session, proxied.room = protocol.Route(n.Local, payload, proxied.session, proxied.room)
+
+	if proxied.room == nil {
+		// Session has not joined a room on this node, stop proxying it
+		n.removeProxy(key)
+		close(proxied.stop)
+	}
+
+	return &clusterv1.ForwardResponse{
+		InRoom: proxied.room != nil,
+	}, nil
+}
+
+// HandleDeliver handles a message sent from a peer to a session connected to this node
+func (n *Node) HandleDeliver(deliver *clusterv1.Deliver) {
+	n.mutex.Lock()
+	connected := n.sessions[deliver.SessionID]
+	n.mutex.Unlock()
+
+	if connected == nil {
+		return
+	}
+
+	select {
+	case <-connected.CloseSignal:
+		// Session already closed
+		return
+	default:
+	}
+
+	if deliver.Close {
+		n.unregister(connected)
+		connected.Close()
+		return
+	}
+
+	// The session's writer may have stopped, so give up if the session closes or does not accept the message in time
+	select {
+	case connected.Write <- deliver.Payload:
+	case <-connected.CloseSignal:
+	case <-time.After(deliverTimeout):
+		glog.Errorf("Timed out delivering message to session %d, dropping message", deliver.SessionID)
+	}
+}
+
+func (n *Node) newProxy(key proxyKey) *proxy {
+	proxied := &proxy{
+		session: &sessionv1.Session{
+			Write:       make(chan []byte),
+			CloseSignal: make(chan struct{}),
+			Closed:      false,
+		},
+		stop: make(chan struct{}),
+	}
+
+	n.mutex.Lock()
+	n.proxies[key] = proxied
+	n.mutex.Unlock()
+
+	// Deliver any messages written to the proxied session to the node the client is connected to, the session is
+	// captured as requests routed for it replace the proxy's session field
+	connected := proxied.session
+	go func() {
+		for {
+			select {
+			case msg := <-connected.Write:
+				err := n.post(key.node, DeliverPath, &clusterv1.Deliver{
+					SessionID: key.sessionID,
+					Payload:   msg,
+				}, nil)
+				if err != nil {
+					glog.Errorf("Failed to deliver message to node %s, %v", key.node, err)
+				}
+			case <-connected.CloseSignal:
+				n.removeProxy(key)
+				err := n.post(key.node, DeliverPath, &clusterv1.Deliver{
+					SessionID: key.sessionID,
+					Close:     true,
+				}, nil)
+				if err != nil {
+					glog.Errorf("Failed to deliver close to node %s, %v", key.node, err)
+				}
+				return
+			case <-proxied.stop:
+				return
+			}
+		}
+	}()
+
+	return proxied
+}
+
+func (n *Node) removeProxy(key proxyKey) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.proxies, key)
+}
+
+func (n *Node) register(connected *sessionv1.Session) uint64 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	sessionID, exists := n.sessionIDs[connected]
+	if exists {
+		return sessionID
+	}
+
+	n.nextSessionID++
+	sessionID = n.nextSessionID
+	n.sessions[sessionID] = connected
+	n.sessionIDs[connected] = sessionID
+	return sessionID
+}
+
+func (n *Node) unregister(connected *sessionv1.Session) (uint64, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	sessionID, exists := n.sessionIDs[connected]
+	if !exists {
+		return 0, false
+	}
+
+	delete(n.sessions, sessionID)
+	delete(n.sessionIDs, connected)
+	return sessionID, true
+}
+
+func (n *Node) proxying(connected *sessionv1.Session) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	_, exists := n.sessionIDs[connected]
+	return exists
+}
+
+func (n *Node) post(address string, path string, request proto.Message, response proto.Message) error {
+	body, err := proto.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.send(context.Background(), http.MethodPost, address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("node %s responded with status %d", address, resp.StatusCode)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data, response)
+}
+
+// send makes a request to a peer, authenticated using the cluster's shared secret
+func (n *Node) send(ctx context.Context, method string, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(SecretHeader, n.Secret)
+	if body != nil {
+		req.Header.Set("Content-Type", ContentType)
+	}
+	return n.Client.Do(req)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	clusterv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/cluster"
+)
+
+func TestHandleDeliverReturnsWhenSessionCloses(t *testing.T) {
+	node := NewNode("http://localhost", nil, "secret", nil)
+
+	// Nothing reads from the session's unbuffered write channel, as if the session's writer has exited
+	connected := &sessionv1.Session{
+		Write:       make(chan []byte),
+		CloseSignal: make(chan struct{}),
+	}
+	sessionID := node.register(connected)
+
+	done := make(chan struct{})
+	go func() {
+		node.HandleDeliver(&clusterv1.Deliver{
+			SessionID: sessionID,
+			Payload:   []byte("message"),
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("expected delivery to wait for the session to accept the message")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	connected.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected delivery to return once the session closed")
+	}
+}
+
+func TestHandleDeliverWritesToSession(t *testing.T) {
+	node := NewNode("http://localhost", nil, "secret", nil)
+
+	connected := &sessionv1.Session{
+		Write:       make(chan []byte, 1),
+		CloseSignal: make(chan struct{}),
+	}
+	sessionID := node.register(connected)
+
+	node.HandleDeliver(&clusterv1.Deliver{
+		SessionID: sessionID,
+		Payload:   []byte("message"),
+	})
+
+	select {
+	case msg := <-connected.Write:
+		if string(msg) != "message" {
+			t.Errorf("got message %q, want %q", msg, "message")
+		}
+	default:
+		t.Fatalf("expected message to be written to the session")
+	}
+}
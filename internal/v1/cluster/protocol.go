@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/protocol"
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+// Protocol wraps a protocol to allow clients to join rooms owned by other nodes in the cluster, requests for rooms
+// owned by this node are handled by the wrapped protocol, while requests for rooms owned by peers are forwarded to the
+// owning peer. Browsing public rooms, quick joining and matchmaking are not forwarded, so they only find and create
+// rooms owned by the node the client is connected to
+type Protocol struct {
+	protocol.Protocol
+	Node *Node
+}
+
+// Connect handles a new client connecting to a room, forwarding the request if the room is owned by a peer
+func (p *Protocol) Connect(payload *transportv1.Payload, connected *sessionv1.Session, currentRoom roomv1.Room) (*sessionv1.Session, roomv1.Room) {
+	if currentRoom != nil {
+		return p.Protocol.Connect(payload, connected, currentRoom)
+	}
+
+	joinRequest := &roomspecv1.JoinRoomRequest{}
+	err := proto.Unmarshal(payload.Data, joinRequest)
+	if err != nil {
+		return p.Protocol.Connect(payload, connected, currentRoom)
+	}
+
+	if joinRequest.InviteCode != "" {
+		// Invite codes are only looked up in rooms known to this node
+		return p.Protocol.Connect(payload, connected, currentRoom)
+	}
+
+	owner := p.owner(joinRequest.RoomID)
+	if owner == "" {
+		return p.Protocol.Connect(payload, connected, currentRoom)
+	}
+
+	return p.join(owner, joinRequest.RoomID, payload, connected)
+}
+
+// Reconnect handles an existing client reconnecting to a room, forwarding the request if the room is owned by a peer
+func (p *Protocol) Reconnect(payload *transportv1.Payload, connected *sessionv1.Session, currentRoom roomv1.Room) (*sessionv1.Session, roomv1.Room) {
+	if currentRoom != nil {
+		return p.Protocol.Reconnect(payload, connected, currentRoom)
+	}
+
+	rejoinRequest := &roomspecv1.RejoinRoomRequest{}
+	err := proto.Unmarshal(payload.Data, rejoinRequest)
+	if err != nil {
+		return p.Protocol.Reconnect(payload, connected, currentRoom)
+	}
+
+	owner := p.owner(rejoinRequest.RoomID)
+	if owner == "" {
+		return p.Protocol.Reconnect(payload, connected, currentRoom)
+	}
+
+	return p.join(owner, rejoinRequest.RoomID, payload, connected)
+}
+
+// Disconnect handles a client disconnecting from a room and closing the connection, telling the owning peer if the
+// client's session is proxied
+func (p *Protocol) Disconnect(connected *sessionv1.Session, room roomv1.Room) {
+	remote, proxied := p.proxiedRoom(connected, room)
+	if !proxied {
+		p.Protocol.Disconnect(connected, room)
+		return
+	}
+
+	connected.Close()
+
+	err := p.Node.ForwardDisconnect(remote.Address, connected)
+	if err != nil {
+		glog.Errorf("Failed to forward disconnect to node %s, %v", remote.Address, err)
+	}
+}
+
+// List handles a client requesting a list of all clients connected to a room
+func (p *Protocol) List(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.List(payload, connected, room)
+	}
+}
+
+// RelayMessage handles a client sending a message to the room
+func (p *Protocol) RelayMessage(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.RelayMessage(payload, connected, room)
+	}
+}
+
+// GrantHost handles a client transferring the room's host powers to another client
+func (p *Protocol) GrantHost(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.GrantHost(payload, connected, room)
+	}
+}
+
+// Kick handles a client removing another client from the room
+func (p *Protocol) Kick(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.Kick(payload, connected, room)
+	}
+}
+
+// Ack handles a client acknowledging relayed messages it has received
+func (p *Protocol) Ack(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.Ack(payload, connected, room)
+	}
+}
+
+// GrantRole handles a client changing the role of another client in the room
+func (p *Protocol) GrantRole(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.GrantRole(payload, connected, room)
+	}
+}
+
+// UpdateMetadata handles a client replacing its own metadata
+func (p *Protocol) UpdateMetadata(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.UpdateMetadata(payload, connected, room)
+	}
+}
+
+// GetProperties handles a client requesting the room's properties
+func (p *Protocol) GetProperties(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.GetProperties(payload, connected, room)
+	}
+}
+
+// SetProperty handles a client setting one of the room's properties
+func (p *Protocol) SetProperty(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.SetProperty(payload, connected, room)
+	}
+}
+
+// DeleteProperty handles a client deleting one of the room's properties
+func (p *Protocol) DeleteProperty(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if !p.forward(payload, connected, room) {
+		p.Protocol.DeleteProperty(payload, connected, room)
+	}
+}
+
+// owner returns the address of the peer that owns a room, if the room is owned by this node or cannot be found an
+// empty address is returned
+func (p *Protocol) owner(roomID int32) string {
+	if p.Node.OwnsRoom(roomID) {
+		return ""
+	}
+	return p.Node.FindOwner(roomID)
+}
+
+func (p *Protocol) join(owner string, roomID int32, payload *transportv1.Payload, connected *sessionv1.Session) (*sessionv1.Session, roomv1.Room) {
+	inRoom, err := p.Node.Forward(owner, payload, connected)
+	if err != nil {
+		p.Node.ForgetOwner(roomID)
+		p.Node.unregister(connected)
+		connected.Write <- protocol.FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to forward request to node %s, %v", owner, err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return connected, nil
+	}
+
+	if !inRoom {
+		// The join may have failed because the peer no longer owns the room, so ask the peers again next time
+		p.Node.ForgetOwner(roomID)
+		return connected, nil
+	}
+
+	return connected, &roomv1.RemoteRoom{
+		ID:      roomID,
+		Address: owner,
+	}
+}
+
+// forward sends a request to the peer that owns the client's room if the client's session is proxied, returning if
+// the request was forwarded
+func (p *Protocol) forward(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) bool {
+	remote, proxied := p.proxiedRoom(connected, room)
+	if !proxied {
+		return false
+	}
+
+	_, err := p.Node.Forward(remote.Address, payload, connected)
+	if err != nil {
+		connected.Write <- protocol.FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to forward request to node %s, %v", remote.Address, err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+	}
+
+	return true
+}
+
+func (p *Protocol) proxiedRoom(connected *sessionv1.Session, room roomv1.Room) (*roomv1.RemoteRoom, bool) {
+	remote, isRemote := room.(*roomv1.RemoteRoom)
+	if !isRemote || connected == nil || !p.Node.proxying(connected) {
+		return nil, false
+	}
+	return remote, true
+}
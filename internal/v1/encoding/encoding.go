@@ -0,0 +1,277 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+
+	clientv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	relayv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProtobufSubprotocol is the websocket subprotocol used to select the protobuf encoding
+	ProtobufSubprotocol = "jamjar-relay.v1.protobuf"
+	// JSONSubprotocol is the websocket subprotocol used to select the JSON encoding
+	JSONSubprotocol = "jamjar-relay.v1.json"
+)
+
+const dataField = "Data"
+
+// Encoding defines the format that payloads are serialised to when sent over a connection
+type Encoding int32
+
+func (e Encoding) String() string {
+	return [...]string{"protobuf", "json"}[e]
+}
+
+const (
+	// Protobuf encodes payloads in the protobuf binary format, this is the default encoding
+	Protobuf Encoding = iota
+	// JSON encodes payloads in the protobuf JSON format, with payload data expanded into a nested JSON object
+	JSON
+)
+
+// ErrUnknownEncoding occurs when an encoding is requested that is not supported
+type ErrUnknownEncoding struct {
+	Message string
+}
+
+func (e ErrUnknownEncoding) Error() string {
+	return "unknown encoding"
+}
+
+// Parse converts an encoding name (e.g. 'json') into an encoding
+func Parse(name string) (Encoding, error) {
+	switch name {
+	case Protobuf.String():
+		return Protobuf, nil
+	case JSON.String():
+		return JSON, nil
+	}
+	return Protobuf, ErrUnknownEncoding{
+		Message: fmt.Sprintf("Unknown encoding '%s', must be either '%s' or '%s'", name, Protobuf, JSON),
+	}
+}
+
+// FromSubprotocol converts a negotiated websocket subprotocol into an encoding, falling back to protobuf if the
+// subprotocol is not recognised
+func FromSubprotocol(subprotocol string) Encoding {
+	if subprotocol == JSONSubprotocol {
+		return JSON
+	}
+	return Protobuf
+}
+
+// Marshal serialises a payload using the encoding provided
+func Marshal(encoding Encoding, payload *transportv1.Payload) ([]byte, error) {
+	if encoding == Protobuf {
+		return proto.Marshal(payload)
+	}
+
+	envelope := proto.Clone(payload).(*transportv1.Payload)
+	envelope.Data = nil
+
+	envelopeJSON, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(envelopeJSON, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(fields, dataField)
+
+	if len(payload.Data) > 0 {
+		data, err := marshalDataJSON(payload.Flag, payload.Data)
+		if err != nil {
+			return nil, err
+		}
+		fields[dataField] = data
+	}
+
+	return json.Marshal(fields)
+}
+
+// Unmarshal deserialises a payload using the encoding provided, any nested JSON data is converted into its protobuf
+// representation, so the payload can be processed the same regardless of the encoding
+func Unmarshal(encoding Encoding, data []byte, payload *transportv1.Payload) error {
+	if encoding == Protobuf {
+		return proto.Unmarshal(data, payload)
+	}
+
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	payloadData, hasData := fields[dataField]
+	delete(fields, dataField)
+
+	envelopeJSON, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	err = protojson.Unmarshal(envelopeJSON, payload)
+	if err != nil {
+		return err
+	}
+
+	if !hasData || string(payloadData) == "null" {
+		return nil
+	}
+
+	payload.Data, err = unmarshalDataJSON(payload.Flag, payloadData)
+	return err
+}
+
+// Transcode converts a protobuf serialised payload into the encoding provided
+func Transcode(encoding Encoding, data []byte) ([]byte, error) {
+	if encoding == Protobuf {
+		return data, nil
+	}
+
+	payload := &transportv1.Payload{}
+	err := proto.Unmarshal(data, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return Marshal(encoding, payload)
+}
+
+func marshalDataJSON(flag transportv1.Payload_FlagType, data []byte) (json.RawMessage, error) {
+	message := dataMessage(flag)
+	if message == nil {
+		// No known message for this flag, fall back to encoding the raw bytes
+		return json.Marshal(data)
+	}
+
+	err := proto.Unmarshal(data, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(message)
+}
+
+func unmarshalDataJSON(flag transportv1.Payload_FlagType, data json.RawMessage) ([]byte, error) {
+	message := dataMessage(flag)
+	if message == nil {
+		// No known message for this flag, the data should be the raw bytes
+		var raw []byte
+		err := json.Unmarshal(data, &raw)
+		return raw, err
+	}
+
+	err := protojson.Unmarshal(data, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(message)
+}
+
+// flagsWithoutData are the flags of payloads that never carry any data, every other flag must have a message in
+// dataMessage so that its data is expanded into a nested JSON object
+var flagsWithoutData = map[transportv1.Payload_FlagType]bool{
+	transportv1.Payload_REQUEST_LIST:                true,
+	transportv1.Payload_RESPONSE_BEGIN_HOST_MIGRATE: true,
+	transportv1.Payload_REQUEST_CANCEL_MATCHMAKE:    true,
+}
+
+// dataMessage returns an empty message of the type that is stored in the data of a payload with the flag provided,
+// if there is no data expected for the flag (see flagsWithoutData) nil is returned
+func dataMessage(flag transportv1.Payload_FlagType) proto.Message {
+	switch flag {
+	case transportv1.Payload_REQUEST_RELAY_MESSAGE, transportv1.Payload_RESPONSE_RELAY_MESSAGE:
+		return &relayv1.Relay{}
+	case transportv1.Payload_REQUEST_CONNECT:
+		return &roomspecv1.JoinRoomRequest{}
+	case transportv1.Payload_REQUEST_RECONNECT:
+		return &roomspecv1.RejoinRoomRequest{}
+	case transportv1.Payload_REQUEST_KICK:
+		return &roomspecv1.KickRequest{}
+	case transportv1.Payload_REQUEST_GRANT_HOST:
+		return &roomspecv1.GrantHostRequest{}
+	case transportv1.Payload_REQUEST_GRANT_ROLE:
+		return &roomspecv1.GrantRoleRequest{}
+	case transportv1.Payload_REQUEST_UPDATE_METADATA:
+		return &roomspecv1.UpdateMetadataRequest{}
+	case transportv1.Payload_REQUEST_GET_PROPERTIES:
+		return &roomspecv1.GetPropertiesRequest{}
+	case transportv1.Payload_REQUEST_SET_PROPERTY:
+		return &roomspecv1.SetPropertyRequest{}
+	case transportv1.Payload_REQUEST_DELETE_PROPERTY:
+		return &roomspecv1.DeletePropertyRequest{}
+	case transportv1.Payload_RESPONSE_PROPERTIES:
+		return &roomspecv1.PropertyList{}
+	case transportv1.Payload_RESPONSE_PROPERTY_CHANGE:
+		return &roomspecv1.PropertyChange{}
+	case transportv1.Payload_REQUEST_BROWSE_ROOMS:
+		return &roomspecv1.BrowseRoomsRequest{}
+	case transportv1.Payload_RESPONSE_PUBLIC_ROOMS:
+		return &roomspecv1.PublicRoomList{}
+	case transportv1.Payload_REQUEST_QUICK_JOIN:
+		return &roomspecv1.QuickJoinRequest{}
+	case transportv1.Payload_RESPONSE_QUICK_JOIN:
+		return &roomspecv1.QuickJoinResponse{}
+	case transportv1.Payload_REQUEST_MATCHMAKE:
+		return &roomspecv1.MatchmakeRequest{}
+	case transportv1.Payload_RESPONSE_MATCHMAKE_STATUS:
+		return &roomspecv1.MatchmakeStatus{}
+	case transportv1.Payload_RESPONSE_CONNECT:
+		return &clientv1.Client{}
+	case transportv1.Payload_RESPONSE_ASSIGN_HOST, transportv1.Payload_RESPONSE_FINISH_HOST_MIGRATE:
+		return &roomspecv1.FinishHostMigrationResponse{}
+	case transportv1.Payload_RESPONSE_LIST:
+		return &clientv1.ClientList{}
+	case transportv1.Payload_RESPONSE_KICK:
+		return &roomspecv1.KickResponse{}
+	case transportv1.Payload_RESPONSE_ERROR:
+		return &transportv1.Error{}
+	case transportv1.Payload_RESPONSE_CLIENT_CONNECT, transportv1.Payload_RESPONSE_CLIENT_DISCONNECT,
+		transportv1.Payload_RESPONSE_CLIENT_LEAVE, transportv1.Payload_RESPONSE_ROLE_CHANGE,
+		transportv1.Payload_RESPONSE_METADATA_CHANGE:
+		return &clientv1.SanitisedClient{}
+	case transportv1.Payload_REQUEST_HANDSHAKE:
+		return &transportv1.HandshakeRequest{}
+	case transportv1.Payload_RESPONSE_HANDSHAKE:
+		return &transportv1.HandshakeResponse{}
+	case transportv1.Payload_RESPONSE_REDIRECT:
+		return &roomspecv1.RedirectResponse{}
+	case transportv1.Payload_RESPONSE_REPLAY_GAP:
+		return &relayv1.ReplayGap{}
+	case transportv1.Payload_REQUEST_ACK, transportv1.Payload_RESPONSE_ACK:
+		return &relayv1.Ack{}
+	case transportv1.Payload_RESPONSE_RELAY_FAILURES:
+		return &relayv1.RelayFailures{}
+	case transportv1.Payload_RESPONSE_RELAYED_MESSAGE:
+		return &relayv1.RelayedMessage{}
+	}
+	return nil
+}
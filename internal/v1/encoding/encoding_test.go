@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"encoding/json"
+	"testing"
+
+	relayv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEveryFlagHasDataMapping(t *testing.T) {
+	for value, name := range transportv1.Payload_FlagType_name {
+		flag := transportv1.Payload_FlagType(value)
+		message := dataMessage(flag)
+		if message == nil && !flagsWithoutData[flag] {
+			t.Errorf("flag %s has no data message and is not listed as carrying no data", name)
+		}
+		if message != nil && flagsWithoutData[flag] {
+			t.Errorf("flag %s has a data message but is listed as carrying no data", name)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var tests = []struct {
+		name    string
+		flag    transportv1.Payload_FlagType
+		message proto.Message
+		decoded proto.Message
+	}{
+		{
+			name:    "Matchmake request",
+			flag:    transportv1.Payload_REQUEST_MATCHMAKE,
+			message: &roomspecv1.MatchmakeRequest{Queue: "ranked", RoomSize: 4, Filters: map[string]string{"region": "eu"}},
+			decoded: &roomspecv1.MatchmakeRequest{},
+		},
+		{
+			name:    "Set property request",
+			flag:    transportv1.Payload_REQUEST_SET_PROPERTY,
+			message: &roomspecv1.SetPropertyRequest{Key: "map", Value: "desert"},
+			decoded: &roomspecv1.SetPropertyRequest{},
+		},
+		{
+			name:    "Relay failures response",
+			flag:    transportv1.Payload_RESPONSE_RELAY_FAILURES,
+			message: &relayv1.RelayFailures{Failures: []*relayv1.RelayFailure{{Target: 2}}},
+			decoded: &relayv1.RelayFailures{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := proto.Marshal(test.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded, err := Marshal(JSON, &transportv1.Payload{Flag: test.flag, Data: data})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fields := map[string]json.RawMessage{}
+			err = json.Unmarshal(encoded, &fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fields[dataField]) == 0 || fields[dataField][0] != '{' {
+				t.Fatalf("expected data to be encoded as a JSON object, got %s", fields[dataField])
+			}
+
+			payload := &transportv1.Payload{}
+			err = Unmarshal(JSON, encoded, payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if payload.Flag != test.flag {
+				t.Errorf("expected flag %s, got %s", test.flag, payload.Flag)
+			}
+
+			err = proto.Unmarshal(payload.Data, test.decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(test.message, test.decoded) {
+				t.Errorf("expected %v, got %v", test.message, test.decoded)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding Encoding
+		err      bool
+	}{
+		{name: "protobuf", encoding: Protobuf},
+		{name: "json", encoding: JSON},
+		{name: "xml", err: true},
+		{name: "", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoding, err := Parse(test.name)
+			if test.err {
+				if _, ok := err.(ErrUnknownEncoding); !ok {
+					t.Fatalf("expected an unknown encoding error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encoding != test.encoding {
+				t.Errorf("expected %s, got %s", test.encoding, encoding)
+			}
+		})
+	}
+}
+
+func TestFromSubprotocol(t *testing.T) {
+	if encoding := FromSubprotocol(JSONSubprotocol); encoding != JSON {
+		t.Errorf("expected %s, got %s", JSON, encoding)
+	}
+	for _, subprotocol := range []string{ProtobufSubprotocol, "", "unknown"} {
+		if encoding := FromSubprotocol(subprotocol); encoding != Protobuf {
+			t.Errorf("expected subprotocol '%s' to fall back to %s, got %s", subprotocol, Protobuf, encoding)
+		}
+	}
+}
+
+func TestTranscode(t *testing.T) {
+	data, err := proto.Marshal(&transportv1.Error{Code: 400, Message: "Room is full", Reason: transportv1.Error_ROOM_FULL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestID := uint32(3)
+	message, err := proto.Marshal(&transportv1.Payload{
+		Flag:      transportv1.Payload_RESPONSE_ERROR,
+		Data:      data,
+		RequestID: &requestID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unchanged, err := Transcode(Protobuf, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(unchanged) != string(message) {
+		t.Errorf("expected protobuf messages to be left unchanged")
+	}
+
+	transcoded, err := Transcode(JSON, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Flag      string
+		RequestID uint32
+		Data      struct {
+			Code    int32
+			Message string
+			Reason  string
+		}
+	}
+	err = json.Unmarshal(transcoded, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Flag != "RESPONSE_ERROR" || decoded.RequestID != requestID || decoded.Data.Code != 400 ||
+		decoded.Data.Message != "Room is full" || decoded.Data.Reason != "ROOM_FULL" {
+		t.Errorf("got unexpected JSON payload %s", transcoded)
+	}
+}
+
+func TestUnmarshalPayloadWithoutData(t *testing.T) {
+	payload := &transportv1.Payload{}
+	err := Unmarshal(JSON, []byte(`{"Flag": "REQUEST_LIST", "RequestID": 9}`), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Flag != transportv1.Payload_REQUEST_LIST || payload.GetRequestID() != 9 || len(payload.Data) != 0 {
+		t.Errorf("got unexpected payload %v", payload)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConnectByRoomID(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	createRoom := func(public bool, passwordHash string) (int32, int32) {
+		room, err := p.CreateRoom(roomv1.Options{MaxClients: 4, Public: public})
+		if err != nil {
+			t.Fatalf("failed to create room: %v", err)
+		}
+		room.(*roomv1.MemoryRoom).PasswordHash = passwordHash
+		info, err := room.GetInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.ID, info.Secret
+	}
+
+	// "hunter2" hashed with a low iteration count
+	passwordHash := "pbkdf2-sha256$1000$MDEyMzQ1Njc4OWFiY2RlZg$pj4T35D2v4tYmC1sTJ1y5tcMADOdtnQGvuHmyYDQh2g"
+	publicID, _ := createRoom(true, "")
+	privateID, privateSecret := createRoom(false, "")
+	protectedID, protectedSecret := createRoom(true, passwordHash)
+
+	tests := []struct {
+		name     string
+		roomID   int32
+		secret   int32
+		password string
+		joins    bool
+		reason   transportv1.Error_ReasonType
+	}{
+		{name: "public room by ID alone", roomID: publicID, joins: true},
+		{name: "private room by ID alone", roomID: privateID, reason: transportv1.Error_ROOM_NOT_FOUND},
+		{name: "private room with secret", roomID: privateID, secret: privateSecret, joins: true},
+		{name: "protected public room without password", roomID: protectedID, reason: transportv1.Error_WRONG_PASSWORD},
+		{name: "protected public room with password", roomID: protectedID, password: "hunter2", joins: true},
+		{name: "protected public room with secret", roomID: protectedID, secret: protectedSecret, joins: true},
+		{name: "missing room", roomID: -1, reason: transportv1.Error_ROOM_NOT_FOUND},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connected := newTestSession()
+			data, err := proto.Marshal(&roomspecv1.JoinRoomRequest{
+				RoomID:     tt.roomID,
+				RoomSecret: tt.secret,
+				Password:   tt.password,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, joined := p.Connect(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_CONNECT,
+				Data: data,
+			}, connected, nil)
+			reason := lastErrorReason(t, connected)
+
+			if tt.joins && joined == nil {
+				t.Fatalf("expected to join the room, got %s", reason)
+			}
+			if !tt.joins && (joined != nil || reason != tt.reason) {
+				t.Fatalf("expected join to fail with %s, got %s", tt.reason, reason)
+			}
+		})
+	}
+}
+
+func TestReconnectByRoomID(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	room, err := p.CreateRoom(roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		roomID  int32
+		secret  int32
+		rejoins bool
+		reason  transportv1.Error_ReasonType
+	}{
+		{name: "wrong secret", roomID: info.ID, secret: info.Secret + 1, reason: transportv1.Error_ROOM_NOT_FOUND},
+		{name: "missing room", roomID: -1, reason: transportv1.Error_ROOM_NOT_FOUND},
+		{name: "room ID and secret", roomID: info.ID, secret: info.Secret, rejoins: true},
+	}
+
+	err = room.RemoveClient(client.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connected := newTestSession()
+			data, err := proto.Marshal(&roomspecv1.RejoinRoomRequest{
+				RoomID:       tt.roomID,
+				RoomSecret:   tt.secret,
+				ClientID:     client.Client.ID,
+				ClientSecret: client.Client.Secret,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, rejoined := p.Reconnect(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_RECONNECT,
+				Data: data,
+			}, connected, nil)
+			reason := lastErrorReason(t, connected)
+
+			if tt.rejoins && rejoined == nil {
+				t.Fatalf("expected to rejoin the room, got %s", reason)
+			}
+			if !tt.rejoins && (rejoined != nil || reason != tt.reason) {
+				t.Fatalf("expected rejoin to fail with %s, got %s", tt.reason, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+)
+
+// ErrInvalidMatchmake occurs when a client's matchmaking request is not valid
+type ErrInvalidMatchmake struct {
+	Message string
+}
+
+func (e ErrInvalidMatchmake) Error() string {
+	return "invalid matchmake request"
+}
+
+// Reason maps an error to the machine readable reason that should be sent to clients, any errors that are not
+// recognised are treated as internal errors
+func Reason(err error) transportv1.Error_ReasonType {
+	switch err.(type) {
+	case roomv1.ErrNoRoomFound:
+		return transportv1.Error_ROOM_NOT_FOUND
+	case roomv1.ErrRoomFull:
+		return transportv1.Error_ROOM_FULL
+	case roomv1.ErrInvalidSecret:
+		return transportv1.Error_INVALID_SECRET
+	case roomv1.ErrNoMatchingClient:
+		return transportv1.Error_CLIENT_NOT_FOUND
+	case roomv1.ErrInvalidProperty:
+		return transportv1.Error_INVALID_REQUEST
+	case roomv1.ErrPropertyConflict:
+		return transportv1.Error_VERSION_CONFLICT
+	case roomv1.ErrNoMatchingProperty:
+		return transportv1.Error_PROPERTY_NOT_FOUND
+	case roomv1.ErrRequestTooManyClients:
+		return transportv1.Error_SERVER_FULL
+	case roomv1.ErrTooManyPasswordFailures:
+		return transportv1.Error_RATE_LIMITED
+	case ErrInvalidMatchmake:
+		return transportv1.Error_INVALID_REQUEST
+	case ErrUnsupportedVersion:
+		return transportv1.Error_UNSUPPORTED_VERSION
+	}
+	return transportv1.Error_INTERNAL
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"errors"
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason transportv1.Error_ReasonType
+	}{
+		{name: "no room found", err: roomv1.ErrNoRoomFound{}, reason: transportv1.Error_ROOM_NOT_FOUND},
+		{name: "room full", err: roomv1.ErrRoomFull{}, reason: transportv1.Error_ROOM_FULL},
+		{name: "invalid secret", err: roomv1.ErrInvalidSecret{}, reason: transportv1.Error_INVALID_SECRET},
+		{name: "no matching client", err: roomv1.ErrNoMatchingClient{}, reason: transportv1.Error_CLIENT_NOT_FOUND},
+		{name: "invalid property", err: roomv1.ErrInvalidProperty{}, reason: transportv1.Error_INVALID_REQUEST},
+		{name: "property conflict", err: roomv1.ErrPropertyConflict{}, reason: transportv1.Error_VERSION_CONFLICT},
+		{
+			name:   "no matching property",
+			err:    roomv1.ErrNoMatchingProperty{},
+			reason: transportv1.Error_PROPERTY_NOT_FOUND,
+		},
+		{name: "too many rooms", err: roomv1.ErrRequestTooManyClients{}, reason: transportv1.Error_SERVER_FULL},
+		{
+			name:   "too many password failures",
+			err:    roomv1.ErrTooManyPasswordFailures{},
+			reason: transportv1.Error_RATE_LIMITED,
+		},
+		{name: "invalid matchmake", err: ErrInvalidMatchmake{}, reason: transportv1.Error_INVALID_REQUEST},
+		{name: "unsupported version", err: ErrUnsupportedVersion{}, reason: transportv1.Error_UNSUPPORTED_VERSION},
+		{name: "unknown error", err: errors.New("unknown"), reason: transportv1.Error_INTERNAL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := Reason(tt.err); reason != tt.reason {
+				t.Errorf("got reason %s, want %s", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestConnectToFullRoomReportsRoomFull(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	room, err := p.CreateRoom(roomv1.Options{MaxClients: 1, Public: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := proto.Marshal(&roomspecv1.JoinRoomRequest{RoomID: info.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []transportv1.Error_ReasonType{transportv1.Error_UNKNOWN, transportv1.Error_ROOM_FULL} {
+		connected := newTestSession()
+		p.Connect(&transportv1.Payload{
+			Flag: transportv1.Payload_REQUEST_CONNECT,
+			Data: data,
+		}, connected, nil)
+
+		if reason := lastErrorReason(t, connected); reason != want {
+			t.Fatalf("client %d got failure %s, want %s", i, reason, want)
+		}
+	}
+}
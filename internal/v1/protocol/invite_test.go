@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+// lastErrorReason drains the messages written to a session, returning the reason of the last error, or UNKNOWN if no
+// error was written
+func lastErrorReason(t *testing.T, connected *sessionv1.Session) transportv1.Error_ReasonType {
+	reason := transportv1.Error_UNKNOWN
+	for {
+		select {
+		case message := <-connected.Write:
+			payload := &transportv1.Payload{}
+			if err := proto.Unmarshal(message, payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload.Flag != transportv1.Payload_RESPONSE_ERROR {
+				continue
+			}
+			failure := &transportv1.Error{}
+			if err := proto.Unmarshal(payload.Data, failure); err != nil {
+				t.Fatal(err)
+			}
+			reason = failure.Reason
+		default:
+			return reason
+		}
+	}
+}
+
+// connectByInviteCode asks to join a room by invite code, returning the room joined, if any, and the reason for any
+// error sent to the client
+func connectByInviteCode(t *testing.T, p *StandardProtocol, connected *sessionv1.Session, inviteCode string, password string) (roomv1.Room, transportv1.Error_ReasonType) {
+	data, err := proto.Marshal(&roomspecv1.JoinRoomRequest{InviteCode: inviteCode, Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, joined := p.Connect(&transportv1.Payload{
+		Flag: transportv1.Payload_REQUEST_CONNECT,
+		Data: data,
+	}, connected, nil)
+	return joined, lastErrorReason(t, connected)
+}
+
+func TestJoinByInviteCodeFailureLimit(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	room, err := p.CreateRoom(roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	// "hunter2" hashed with a low iteration count, so that checking many passwords is quick
+	room.(*roomv1.MemoryRoom).PasswordHash = "pbkdf2-sha256$1000$MDEyMzQ1Njc4OWFiY2RlZg$pj4T35D2v4tYmC1sTJ1y5tcMADOdtnQGvuHmyYDQh2g"
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		inviteCode string
+		password   string
+		reason     transportv1.Error_ReasonType
+	}{
+		{name: "wrong invite code", inviteCode: "ZZZZZZ", password: "hunter2", reason: transportv1.Error_ROOM_NOT_FOUND},
+		{name: "wrong password", inviteCode: info.InviteCode, password: "wrong", reason: transportv1.Error_WRONG_PASSWORD},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connected := newTestSession()
+			for i := 0; i < roomv1.MaxSessionPasswordFailures; i++ {
+				_, reason := connectByInviteCode(t, p, connected, tt.inviteCode, tt.password)
+				if reason != tt.reason {
+					t.Fatalf("expected attempt %d to fail with %s, got %s", i, tt.reason, reason)
+				}
+			}
+
+			joined, reason := connectByInviteCode(t, p, connected, info.InviteCode, "hunter2")
+			if joined != nil || reason != transportv1.Error_RATE_LIMITED {
+				t.Fatalf("expected the connection to be rate limited, got %s", reason)
+			}
+
+			joined, reason = connectByInviteCode(t, p, newTestSession(), info.InviteCode, "hunter2")
+			if joined == nil {
+				t.Errorf("expected a new connection to still join the room, got %s", reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestKickRespectsRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		kickerRole roomv1.Role
+		targetRole roomv1.Role
+		// unknownTarget kicks a client ID that is not in the room
+		unknownTarget bool
+		reason        transportv1.Error_ReasonType
+	}{
+		{name: "host kicks player", kickerRole: roomv1.RoleHost, targetRole: roomv1.RolePlayer},
+		{name: "host kicks moderator", kickerRole: roomv1.RoleHost, targetRole: roomv1.RoleModerator},
+		{name: "moderator kicks player", kickerRole: roomv1.RoleModerator, targetRole: roomv1.RolePlayer},
+		{name: "moderator kicks spectator", kickerRole: roomv1.RoleModerator, targetRole: roomv1.RoleSpectator},
+		{
+			name:       "moderator kicks moderator",
+			kickerRole: roomv1.RoleModerator,
+			targetRole: roomv1.RoleModerator,
+			reason:     transportv1.Error_NOT_PERMITTED,
+		},
+		{
+			name:       "moderator kicks host",
+			kickerRole: roomv1.RoleModerator,
+			targetRole: roomv1.RoleHost,
+			reason:     transportv1.Error_INVALID_TARGET,
+		},
+		{
+			name:       "player kicks player",
+			kickerRole: roomv1.RolePlayer,
+			targetRole: roomv1.RolePlayer,
+			reason:     transportv1.Error_NOT_PERMITTED,
+		},
+		{
+			name:          "moderator kicks unknown client",
+			kickerRole:    roomv1.RoleModerator,
+			unknownTarget: true,
+			reason:        transportv1.Error_TARGET_NOT_FOUND,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4, MaxSpectators: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kicker, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			join := room.NewClient
+			if tt.targetRole == roomv1.RoleSpectator {
+				join = room.NewSpectator
+			}
+			target, err := join(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, client := range []struct {
+				id   int32
+				role roomv1.Role
+			}{{kicker.Client.ID, tt.kickerRole}, {target.Client.ID, tt.targetRole}} {
+				switch client.role {
+				case roomv1.RoleHost:
+					_, err = room.SetHost(&client.id)
+				case roomv1.RoleModerator:
+					err = room.SetRole(client.id, roomv1.RoleModerator)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			targetID := target.Client.ID
+			if tt.unknownTarget {
+				targetID = -1
+			}
+			data, err := proto.Marshal(&roomspecv1.KickRequest{ClientID: targetID})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p.Kick(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_KICK,
+				Data: data,
+			}, kicker, room)
+
+			if reason := lastErrorReason(t, kicker); reason != tt.reason {
+				t.Fatalf("got failure %s, want %s", reason, tt.reason)
+			}
+
+			_, err = room.GetClient(target.Client.ID)
+			kicked := err != nil
+			if kicked != (tt.reason == transportv1.Error_UNKNOWN) {
+				t.Errorf("got target kicked %t, want %t", kicked, tt.reason == transportv1.Error_UNKNOWN)
+			}
+		})
+	}
+}
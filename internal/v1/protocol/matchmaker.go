@@ -0,0 +1,368 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+)
+
+const (
+	// DefaultMatchmakeTimeout is how long a client waits in a matchmaking queue if it does not request a timeout
+	DefaultMatchmakeTimeout = 60 * time.Second
+	// MaxMatchmakeTimeout is the longest a client can wait in a matchmaking queue
+	MaxMatchmakeTimeout = 10 * time.Minute
+	// MatchedRoomIdleTimeout is how long a room created by matchmaking can be idle before it is closed
+	MatchedRoomIdleTimeout = time.Minute
+	// MinMatchmakeRoomSize is the smallest room that can be requested when matchmaking
+	MinMatchmakeRoomSize = 2
+	// MaxMatchmakeRoomSize is the largest room that can be requested when matchmaking
+	MaxMatchmakeRoomSize = 64
+	// MaxQueueNameLength is the longest name a matchmaking queue can have
+	MaxQueueNameLength = 64
+	// MaxMatchmakeAttributes is the most attributes or filters a client can provide when matchmaking
+	MaxMatchmakeAttributes = 16
+)
+
+// matchmakeStatusTimeout is how long a matchmaking status waits for a client's session to accept it before it is
+// dropped, so that a client that is not reading cannot hold up matchmaking for every other client
+const matchmakeStatusTimeout = time.Second
+
+// ValidateMatchmake checks that a matchmaking request names a queue, asks for a supported room size and is within
+// the attribute and filter limits
+func ValidateMatchmake(request *roomspecv1.MatchmakeRequest) error {
+	if request.Queue == "" {
+		return ErrInvalidMatchmake{
+			Message: "Matchmaking queue name must not be empty",
+		}
+	}
+	if len(request.Queue) > MaxQueueNameLength {
+		return ErrInvalidMatchmake{
+			Message: fmt.Sprintf("Matchmaking queue name must be at most %d characters long", MaxQueueNameLength),
+		}
+	}
+	if request.RoomSize < MinMatchmakeRoomSize || request.RoomSize > MaxMatchmakeRoomSize {
+		return ErrInvalidMatchmake{
+			Message: fmt.Sprintf("Room size must be between %d and %d, got %d", MinMatchmakeRoomSize,
+				MaxMatchmakeRoomSize, request.RoomSize),
+		}
+	}
+	if len(request.Attributes) > MaxMatchmakeAttributes {
+		return ErrInvalidMatchmake{
+			Message: fmt.Sprintf("At most %d attributes can be provided, got %d", MaxMatchmakeAttributes,
+				len(request.Attributes)),
+		}
+	}
+	if len(request.Filters) > MaxMatchmakeAttributes {
+		return ErrInvalidMatchmake{
+			Message: fmt.Sprintf("At most %d filters can be provided, got %d", MaxMatchmakeAttributes,
+				len(request.Filters)),
+		}
+	}
+	if request.TimeoutSeconds < 0 {
+		return ErrInvalidMatchmake{
+			Message: fmt.Sprintf("Timeout must not be negative, got %d", request.TimeoutSeconds),
+		}
+	}
+	return nil
+}
+
+// matchmakeTimeout returns how long a client should wait in a queue for the timeout it requested, clients that do not
+// request a timeout use the default and requested timeouts are capped at the max
+func matchmakeTimeout(seconds int32) time.Duration {
+	if seconds == 0 {
+		return DefaultMatchmakeTimeout
+	}
+	timeout := time.Duration(seconds) * time.Second
+	if timeout > MaxMatchmakeTimeout {
+		return MaxMatchmakeTimeout
+	}
+	return timeout
+}
+
+// NewMatchmaker creates a new matchmaker with no clients waiting
+func NewMatchmaker() *Matchmaker {
+	return &Matchmaker{
+		queues:  make(map[string][]*MatchmakeTicket),
+		tickets: make(map[*sessionv1.Session]*MatchmakeTicket),
+		matched: make(map[*sessionv1.Session]*MatchmakeTicket),
+	}
+}
+
+// Matchmaker tracks clients waiting in named matchmaking queues, grouping compatible clients together so that they
+// can be placed into a room. Two clients are compatible if they want the same room size and each client's filters
+// are all matched by the other client's attributes. Clients are grouped in the order they joined the queue, so the
+// longest waiting clients are matched first. Matched clients are assigned a room, which each client's own session
+// claims and joins, so a client that cancels before its session claims the room is never placed into it
+type Matchmaker struct {
+	mutex   sync.Mutex
+	queues  map[string][]*MatchmakeTicket
+	tickets map[*sessionv1.Session]*MatchmakeTicket
+	matched map[*sessionv1.Session]*MatchmakeTicket
+}
+
+// MatchmakeTicket is a client waiting in a matchmaking queue
+type MatchmakeTicket struct {
+	Session    *sessionv1.Session
+	RequestID  *uint32
+	Queue      string
+	RoomSize   int32
+	Attributes map[string]string
+	Filters    map[string]string
+	Expires    time.Time
+	// RoomID is the room the client has been matched into, set once the client's group has been given a room
+	RoomID int32
+}
+
+// Enqueue adds a client to the back of a matchmaking queue, returning false if the client is already waiting in a
+// queue or waiting to join a matched room
+func (m *Matchmaker) Enqueue(ticket *MatchmakeTicket) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.tickets[ticket.Session]; exists {
+		return false
+	}
+	if _, exists := m.matched[ticket.Session]; exists {
+		return false
+	}
+
+	m.tickets[ticket.Session] = ticket
+	m.queues[ticket.Queue] = append(m.queues[ticket.Queue], ticket)
+	return true
+}
+
+// Cancel removes a client from the matchmaking queue it is waiting in, or withdraws it from a matched room it has not
+// yet joined, returning the client's ticket, or nil if the client is not matchmaking
+func (m *Matchmaker) Cancel(session *sessionv1.Session) *MatchmakeTicket {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if ticket, matched := m.matched[session]; matched {
+		delete(m.matched, session)
+		return ticket
+	}
+
+	ticket, exists := m.tickets[session]
+	if !exists {
+		return nil
+	}
+
+	m.remove(ticket)
+	return ticket
+}
+
+// Expire removes every client that has waited in a queue past its timeout as of the time provided, returning their
+// tickets
+func (m *Matchmaker) Expire(now time.Time) []*MatchmakeTicket {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	expired := []*MatchmakeTicket{}
+	for _, ticket := range m.tickets {
+		if !now.Before(ticket.Expires) {
+			expired = append(expired, ticket)
+		}
+	}
+
+	for _, ticket := range expired {
+		m.remove(ticket)
+	}
+
+	return expired
+}
+
+// Match removes each group of compatible clients that can fill a room from a queue, returning the groups with the
+// longest waiting client first in each group. Clients whose sessions have closed are dropped from the queue
+func (m *Matchmaker) Match(queue string) [][]*MatchmakeTicket {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	waiting := []*MatchmakeTicket{}
+	for _, ticket := range m.queues[queue] {
+		if ticket.Session.Closed {
+			delete(m.tickets, ticket.Session)
+			continue
+		}
+		waiting = append(waiting, ticket)
+	}
+
+	groups := [][]*MatchmakeTicket{}
+	matched := make(map[*MatchmakeTicket]bool)
+	for i, seed := range waiting {
+		if matched[seed] {
+			continue
+		}
+
+		group := []*MatchmakeTicket{seed}
+		for _, candidate := range waiting[i+1:] {
+			if int32(len(group)) >= seed.RoomSize {
+				break
+			}
+			if matched[candidate] || !compatibleWithGroup(candidate, group) {
+				continue
+			}
+			group = append(group, candidate)
+		}
+
+		if int32(len(group)) < seed.RoomSize {
+			continue
+		}
+
+		for _, ticket := range group {
+			matched[ticket] = true
+			delete(m.tickets, ticket.Session)
+		}
+		groups = append(groups, group)
+	}
+
+	remaining := make([]*MatchmakeTicket, 0, len(waiting))
+	for _, ticket := range waiting {
+		if !matched[ticket] {
+			remaining = append(remaining, ticket)
+		}
+	}
+	m.setQueue(queue, remaining)
+
+	return groups
+}
+
+// Requeue puts a group of clients that could not be placed into a room back at the front of their queue, keeping
+// their place ahead of clients that joined after them. Clients that have since closed their sessions are dropped
+func (m *Matchmaker) Requeue(group []*MatchmakeTicket) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := len(group) - 1; i >= 0; i-- {
+		ticket := group[i]
+		if ticket.Session.Closed {
+			continue
+		}
+		if _, exists := m.tickets[ticket.Session]; exists {
+			continue
+		}
+		m.tickets[ticket.Session] = ticket
+		m.queues[ticket.Queue] = append([]*MatchmakeTicket{ticket}, m.queues[ticket.Queue]...)
+	}
+}
+
+// Assign records that a matched client has been given a room, the client joins the room once its session claims the
+// ticket
+func (m *Matchmaker) Assign(ticket *MatchmakeTicket, roomID int32) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ticket.RoomID = roomID
+	m.matched[ticket.Session] = ticket
+}
+
+// Claim takes the ticket of a client that has been assigned a room, returning nil if the client has not been assigned
+// a room or has cancelled since
+func (m *Matchmaker) Claim(session *sessionv1.Session) *MatchmakeTicket {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ticket, matched := m.matched[session]
+	if !matched {
+		return nil
+	}
+	delete(m.matched, session)
+	return ticket
+}
+
+// Waiting returns each client waiting in a queue, with the number of waiting clients it is compatible with,
+// including itself
+func (m *Matchmaker) Waiting(queue string) map[*MatchmakeTicket]int32 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	waiting := make(map[*MatchmakeTicket]int32, len(m.queues[queue]))
+	for _, ticket := range m.queues[queue] {
+		count := int32(0)
+		for _, other := range m.queues[queue] {
+			if ticket == other || compatible(ticket, other) {
+				count++
+			}
+		}
+		waiting[ticket] = count
+	}
+	return waiting
+}
+
+// Queues returns the names of every queue that has clients waiting in it, sorted by name
+func (m *Matchmaker) Queues() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	queues := make([]string, 0, len(m.queues))
+	for queue := range m.queues {
+		queues = append(queues, queue)
+	}
+	sort.Strings(queues)
+	return queues
+}
+
+func (m *Matchmaker) remove(ticket *MatchmakeTicket) {
+	delete(m.tickets, ticket.Session)
+
+	queue := m.queues[ticket.Queue]
+	remaining := make([]*MatchmakeTicket, 0, len(queue))
+	for _, waiting := range queue {
+		if waiting != ticket {
+			remaining = append(remaining, waiting)
+		}
+	}
+	m.setQueue(ticket.Queue, remaining)
+}
+
+func (m *Matchmaker) setQueue(queue string, tickets []*MatchmakeTicket) {
+	if len(tickets) == 0 {
+		delete(m.queues, queue)
+		return
+	}
+	m.queues[queue] = tickets
+}
+
+// compatibleWithGroup determines if a client is compatible with every client in a group
+func compatibleWithGroup(ticket *MatchmakeTicket, group []*MatchmakeTicket) bool {
+	for _, member := range group {
+		if !compatible(ticket, member) {
+			return false
+		}
+	}
+	return true
+}
+
+// compatible determines if two clients can be placed in the same room, they must want the same room size and each
+// client's filters must all be matched by the other client's attributes
+func compatible(a *MatchmakeTicket, b *MatchmakeTicket) bool {
+	return a.RoomSize == b.RoomSize && satisfies(b.Attributes, a.Filters) && satisfies(a.Attributes, b.Filters)
+}
+
+func satisfies(attributes map[string]string, filters map[string]string) bool {
+	for key, value := range filters {
+		if attributes[key] != value {
+			return false
+		}
+	}
+	return true
+}
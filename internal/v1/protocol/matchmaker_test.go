@@ -0,0 +1,381 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestSession() *sessionv1.Session {
+	return &sessionv1.Session{
+		Write:       make(chan []byte, 64),
+		CloseSignal: make(chan struct{}),
+		Matched:     make(chan struct{}, 1),
+	}
+}
+
+func TestMatchmakerMatch(t *testing.T) {
+	type ticket struct {
+		queue      string
+		roomSize   int32
+		attributes map[string]string
+		filters    map[string]string
+	}
+
+	tests := []struct {
+		name    string
+		tickets []ticket
+		queue   string
+		groups  [][]int
+		waiting int
+	}{
+		{
+			name: "not enough clients",
+			tickets: []ticket{
+				{queue: "q", roomSize: 3},
+				{queue: "q", roomSize: 3},
+			},
+			queue:   "q",
+			groups:  [][]int{},
+			waiting: 2,
+		},
+		{
+			name: "groups in queue order",
+			tickets: []ticket{
+				{queue: "q", roomSize: 2},
+				{queue: "q", roomSize: 2},
+				{queue: "q", roomSize: 2},
+				{queue: "q", roomSize: 2},
+				{queue: "q", roomSize: 2},
+			},
+			queue:   "q",
+			groups:  [][]int{{0, 1}, {2, 3}},
+			waiting: 1,
+		},
+		{
+			name: "different room sizes are not grouped",
+			tickets: []ticket{
+				{queue: "q", roomSize: 2},
+				{queue: "q", roomSize: 3},
+				{queue: "q", roomSize: 2},
+			},
+			queue:   "q",
+			groups:  [][]int{{0, 2}},
+			waiting: 1,
+		},
+		{
+			name: "filters must match attributes both ways",
+			tickets: []ticket{
+				{queue: "q", roomSize: 2, attributes: map[string]string{"region": "eu"},
+					filters: map[string]string{"mode": "ranked"}},
+				{queue: "q", roomSize: 2, attributes: map[string]string{"mode": "casual"}},
+				{queue: "q", roomSize: 2, attributes: map[string]string{"mode": "casual"},
+					filters: map[string]string{"mode": "casual"}},
+				{queue: "q", roomSize: 2, attributes: map[string]string{"mode": "ranked"},
+					filters: map[string]string{"region": "eu"}},
+			},
+			queue:   "q",
+			groups:  [][]int{{0, 3}, {1, 2}},
+			waiting: 0,
+		},
+		{
+			name: "other queues are not matched",
+			tickets: []ticket{
+				{queue: "a", roomSize: 2},
+				{queue: "b", roomSize: 2},
+				{queue: "a", roomSize: 2},
+			},
+			queue:   "b",
+			groups:  [][]int{},
+			waiting: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchmaker := NewMatchmaker()
+			tickets := make([]*MatchmakeTicket, len(tt.tickets))
+			for i, spec := range tt.tickets {
+				tickets[i] = &MatchmakeTicket{
+					Session:    newTestSession(),
+					Queue:      spec.queue,
+					RoomSize:   spec.roomSize,
+					Attributes: spec.attributes,
+					Filters:    spec.filters,
+					Expires:    time.Now().Add(time.Minute),
+				}
+				if !matchmaker.Enqueue(tickets[i]) {
+					t.Fatalf("failed to enqueue ticket %d", i)
+				}
+			}
+
+			groups := matchmaker.Match(tt.queue)
+			if len(groups) != len(tt.groups) {
+				t.Fatalf("expected %d groups, got %d", len(tt.groups), len(groups))
+			}
+			for i, expected := range tt.groups {
+				if len(groups[i]) != len(expected) {
+					t.Fatalf("expected group %d to have %d clients, got %d", i, len(expected), len(groups[i]))
+				}
+				for j, index := range expected {
+					if groups[i][j] != tickets[index] {
+						t.Errorf("expected group %d client %d to be ticket %d", i, j, index)
+					}
+				}
+			}
+
+			if waiting := len(matchmaker.Waiting(tt.queue)); waiting != tt.waiting {
+				t.Errorf("expected %d clients left waiting, got %d", tt.waiting, waiting)
+			}
+		})
+	}
+}
+
+func TestMatchmakerAssignAndClaim(t *testing.T) {
+	tests := []struct {
+		name    string
+		cancel  bool
+		claimed bool
+	}{
+		{name: "claimed by the session", claimed: true},
+		{name: "cancelled before the session claims", cancel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchmaker := NewMatchmaker()
+			ticket := &MatchmakeTicket{
+				Session:  newTestSession(),
+				Queue:    "q",
+				RoomSize: 2,
+				Expires:  time.Now().Add(time.Minute),
+			}
+			matchmaker.Enqueue(ticket)
+			matchmaker.Enqueue(&MatchmakeTicket{
+				Session:  newTestSession(),
+				Queue:    "q",
+				RoomSize: 2,
+				Expires:  time.Now().Add(time.Minute),
+			})
+			if len(matchmaker.Match("q")) != 1 {
+				t.Fatalf("expected clients to be matched")
+			}
+
+			matchmaker.Assign(ticket, 7)
+			if matchmaker.Enqueue(ticket) {
+				t.Errorf("expected enqueue to be rejected while waiting to join a matched room")
+			}
+
+			if tt.cancel && matchmaker.Cancel(ticket.Session) != ticket {
+				t.Errorf("expected cancel to withdraw the matched ticket")
+			}
+
+			claimed := matchmaker.Claim(ticket.Session)
+			if tt.claimed {
+				if claimed == nil || claimed.RoomID != 7 {
+					t.Fatalf("expected ticket for room 7 to be claimed, got %v", claimed)
+				}
+			} else if claimed != nil {
+				t.Fatalf("expected no ticket to claim, got %v", claimed)
+			}
+
+			if matchmaker.Claim(ticket.Session) != nil {
+				t.Errorf("expected a ticket to only be claimed once")
+			}
+		})
+	}
+}
+
+func TestMatchmakerExpire(t *testing.T) {
+	now := time.Now()
+	matchmaker := NewMatchmaker()
+	expired := &MatchmakeTicket{Session: newTestSession(), Queue: "q", RoomSize: 2, Expires: now}
+	waiting := &MatchmakeTicket{Session: newTestSession(), Queue: "q", RoomSize: 2, Expires: now.Add(time.Second)}
+	matchmaker.Enqueue(expired)
+	matchmaker.Enqueue(waiting)
+
+	tickets := matchmaker.Expire(now)
+	if len(tickets) != 1 || tickets[0] != expired {
+		t.Fatalf("expected only the expired ticket to be removed, got %v", tickets)
+	}
+	if _, exists := matchmaker.Waiting("q")[waiting]; !exists {
+		t.Errorf("expected unexpired ticket to still be waiting")
+	}
+}
+
+func TestValidateMatchmake(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *roomspecv1.MatchmakeRequest
+		valid   bool
+	}{
+		{name: "valid", request: &roomspecv1.MatchmakeRequest{Queue: "q", RoomSize: 2}, valid: true},
+		{name: "empty queue", request: &roomspecv1.MatchmakeRequest{RoomSize: 2}},
+		{name: "room too small", request: &roomspecv1.MatchmakeRequest{Queue: "q", RoomSize: MinMatchmakeRoomSize - 1}},
+		{name: "room too large", request: &roomspecv1.MatchmakeRequest{Queue: "q", RoomSize: MaxMatchmakeRoomSize + 1}},
+		{name: "negative timeout", request: &roomspecv1.MatchmakeRequest{Queue: "q", RoomSize: 2, TimeoutSeconds: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMatchmake(tt.request)
+			if tt.valid && err != nil {
+				t.Errorf("expected request to be valid, got %v", err)
+			}
+			if !tt.valid {
+				if _, ok := err.(ErrInvalidMatchmake); !ok {
+					t.Errorf("expected ErrInvalidMatchmake, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchmakeTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		seconds  int32
+		expected time.Duration
+	}{
+		{name: "default", seconds: 0, expected: DefaultMatchmakeTimeout},
+		{name: "requested", seconds: 30, expected: 30 * time.Second},
+		{name: "capped", seconds: 1 << 30, expected: MaxMatchmakeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if timeout := matchmakeTimeout(tt.seconds); timeout != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, timeout)
+			}
+		})
+	}
+}
+
+func TestJoinMatchOnlyJoinsSessionsStillMatchmaking(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+		Matchmaker: NewMatchmaker(),
+	}
+
+	data, err := proto.Marshal(&roomspecv1.MatchmakeRequest{Queue: "q", RoomSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	joining := newTestSession()
+	cancelling := newTestSession()
+	for _, connected := range []*sessionv1.Session{joining, cancelling} {
+		p.Matchmake(&transportv1.Payload{
+			Flag: transportv1.Payload_REQUEST_MATCHMAKE,
+			Data: data,
+		}, connected, nil)
+	}
+
+	for _, connected := range []*sessionv1.Session{joining, cancelling} {
+		select {
+		case <-connected.Matched:
+		default:
+			t.Fatalf("expected matched sessions to be woken")
+		}
+	}
+
+	// The match must not place either client in the room until its own session joins
+	rooms, err := p.RoomManager.ListRooms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 1 {
+		t.Fatalf("expected a room to be created for the match, got %d", len(rooms))
+	}
+	info, err := rooms[0].GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CurrentClients != 0 {
+		t.Fatalf("expected no clients to be connected before joining, got %d", info.CurrentClients)
+	}
+
+	p.CancelMatchmake(&transportv1.Payload{Flag: transportv1.Payload_REQUEST_CANCEL_MATCHMAKE}, cancelling, nil)
+
+	_, room := p.JoinMatch(joining, nil)
+	if room == nil {
+		t.Fatalf("expected matched session to join the room")
+	}
+	_, room = p.JoinMatch(cancelling, nil)
+	if room != nil {
+		t.Fatalf("expected cancelled session not to join the room")
+	}
+
+	info, err = rooms[0].GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CurrentClients != 1 {
+		t.Errorf("expected 1 client in the matched room, got %d", info.CurrentClients)
+	}
+}
+
+func TestMatchmakeStatusDoesNotBlockOnStalledSession(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+		Matchmaker: NewMatchmaker(),
+	}
+
+	// A client waiting in the queue that is not reading from its session
+	stalled := &sessionv1.Session{
+		Write:       make(chan []byte),
+		CloseSignal: make(chan struct{}),
+		Matched:     make(chan struct{}, 1),
+	}
+	p.Matchmaker.Enqueue(&MatchmakeTicket{
+		Session:  stalled,
+		Queue:    "q",
+		RoomSize: 3,
+		Expires:  time.Now().Add(time.Minute),
+	})
+
+	data, err := proto.Marshal(&roomspecv1.MatchmakeRequest{Queue: "q", RoomSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.Matchmake(&transportv1.Payload{
+			Flag: transportv1.Payload_REQUEST_MATCHMAKE,
+			Data: data,
+		}, newTestSession(), nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected matchmaking not to be blocked by a stalled session")
+	}
+}
@@ -10,8 +10,15 @@ import (
 
 const internalServerErrMessage = "An internal server error occurred"
 
-// Fail converts an error to a payload in bytes, while logging any internal server errors
+// Fail converts an error to a payload in bytes, while logging any internal server errors. Every error should include
+// a machine readable reason alongside the HTTP style code, allowing clients to handle errors without matching messages
 func Fail(failure *transport.Error) []byte {
+	return FailRequest(nil, failure)
+}
+
+// FailRequest converts an error caused by a request to a payload in bytes, including the request's ID so the error
+// can be matched to the request that caused it
+func FailRequest(requestID *uint32, failure *transport.Error) []byte {
 	if failure.Code == http.StatusInternalServerError {
 		glog.Error(failure.Message)
 		failure.Message = internalServerErrMessage
@@ -24,8 +31,9 @@ func Fail(failure *transport.Error) []byte {
 	}
 
 	networkMessage := transport.Payload{
-		Flag: transport.Payload_RESPONSE_ERROR,
-		Data: failureBytes,
+		Flag:      transport.Payload_RESPONSE_ERROR,
+		Data:      failureBytes,
+		RequestID: requestID,
 	}
 
 	response, err := proto.Marshal(&networkMessage)
@@ -47,3 +55,10 @@ func Succeed(networkMessage *transport.Payload) []byte {
 
 	return response
 }
+
+// SucceedRequest converts a successful network message in response to a request to bytes, including the request's ID
+// so the response can be matched to the request
+func SucceedRequest(requestID *uint32, networkMessage *transport.Payload) []byte {
+	networkMessage.RequestID = requestID
+	return Succeed(networkMessage)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChangeProperty(t *testing.T) {
+	version := func(version uint64) *uint64 {
+		return &version
+	}
+
+	tests := []struct {
+		name string
+		flag transportv1.Payload_FlagType
+		data proto.Message
+		// fromPlayer sends the request from a player rather than the room's host
+		fromPlayer bool
+		// playersSet grants players permission to set properties
+		playersSet bool
+		reason     transportv1.Error_ReasonType
+		change     *roomspecv1.PropertyChange
+	}{
+		{
+			name:   "host sets property",
+			flag:   transportv1.Payload_REQUEST_SET_PROPERTY,
+			data:   &roomspecv1.SetPropertyRequest{Key: "map", Value: "forest"},
+			change: &roomspecv1.PropertyChange{Property: &roomspecv1.Property{Key: "map", Value: "forest", Version: 2}},
+		},
+		{
+			name:       "player sets property",
+			flag:       transportv1.Payload_REQUEST_SET_PROPERTY,
+			data:       &roomspecv1.SetPropertyRequest{Key: "map", Value: "forest"},
+			fromPlayer: true,
+			reason:     transportv1.Error_NOT_PERMITTED,
+		},
+		{
+			name:       "permitted player sets property",
+			flag:       transportv1.Payload_REQUEST_SET_PROPERTY,
+			data:       &roomspecv1.SetPropertyRequest{Key: "ready", Value: "true"},
+			fromPlayer: true,
+			playersSet: true,
+			change:     &roomspecv1.PropertyChange{Property: &roomspecv1.Property{Key: "ready", Value: "true", Version: 2}},
+		},
+		{
+			name:   "host sets property at stale version",
+			flag:   transportv1.Payload_REQUEST_SET_PROPERTY,
+			data:   &roomspecv1.SetPropertyRequest{Key: "map", Value: "forest", ExpectedVersion: version(3)},
+			reason: transportv1.Error_VERSION_CONFLICT,
+		},
+		{
+			name:   "host sets property too large",
+			flag:   transportv1.Payload_REQUEST_SET_PROPERTY,
+			data:   &roomspecv1.SetPropertyRequest{Key: "map", Value: string(make([]byte, roomv1.MaxPropertyValueLength+1))},
+			reason: transportv1.Error_INVALID_REQUEST,
+		},
+		{
+			name:   "host deletes property",
+			flag:   transportv1.Payload_REQUEST_DELETE_PROPERTY,
+			data:   &roomspecv1.DeletePropertyRequest{Key: "map", ExpectedVersion: version(1)},
+			change: &roomspecv1.PropertyChange{Property: &roomspecv1.Property{Key: "map", Version: 2}, Deleted: true},
+		},
+		{
+			name:   "host deletes missing property",
+			flag:   transportv1.Payload_REQUEST_DELETE_PROPERTY,
+			data:   &roomspecv1.DeletePropertyRequest{Key: "mode"},
+			reason: transportv1.Error_PROPERTY_NOT_FOUND,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			options := roomv1.Options{MaxClients: 4, Properties: map[string]string{"map": "desert"}}
+			if tt.playersSet {
+				options.Permissions = roomv1.DefaultPermissions()
+				options.Permissions[roomv1.RolePlayer] = []roomv1.Permission{roomv1.PermissionSetProperties}
+			}
+			room, err := roomv1.NewMemoryRoom(1, 1, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = room.SetHost(&host.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			player, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requester := host
+			if tt.fromPlayer {
+				requester = player
+			}
+
+			data, err := proto.Marshal(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			Route(p, &transportv1.Payload{Flag: tt.flag, Data: data}, requester, room)
+
+			if tt.change == nil {
+				if reason := lastErrorReason(t, requester); reason != tt.reason {
+					t.Fatalf("got failure %s, want %s", reason, tt.reason)
+				}
+				return
+			}
+
+			// Every client in the room is told about the change, including who made it
+			tt.change.ClientID = requester.Client.ID
+			for name, notified := range map[string]*sessionv1.Session{"host": host, "player": player} {
+				payload := &transportv1.Payload{}
+				err = proto.Unmarshal(<-notified.Write, payload)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if payload.Flag != transportv1.Payload_RESPONSE_PROPERTY_CHANGE {
+					t.Fatalf("%s got flag %s, want %s", name, payload.Flag,
+						transportv1.Payload_RESPONSE_PROPERTY_CHANGE)
+				}
+				change := &roomspecv1.PropertyChange{}
+				err = proto.Unmarshal(payload.Data, change)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !proto.Equal(change, tt.change) {
+					t.Fatalf("%s got change %v, want %v", name, change, tt.change)
+				}
+			}
+		})
+	}
+}
+
+func TestPropertiesSurviveHostMigration(t *testing.T) {
+	p := &StandardProtocol{}
+	room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = room.SetHost(&host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := proto.Marshal(&roomspecv1.SetPropertyRequest{Key: "mode", Value: "ranked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetProperty(&transportv1.Payload{Flag: transportv1.Payload_REQUEST_SET_PROPERTY, Data: data}, host, room)
+
+	err = room.RemoveClient(host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.migrateHost(room)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drain the property change and host migration messages
+	for len(player.Write) > 0 {
+		<-player.Write
+	}
+
+	data, err = proto.Marshal(&roomspecv1.GetPropertiesRequest{Keys: []string{"mode", "missing"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.GetProperties(&transportv1.Payload{Flag: transportv1.Payload_REQUEST_GET_PROPERTIES, Data: data}, player, room)
+
+	payload := &transportv1.Payload{}
+	err = proto.Unmarshal(<-player.Write, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := &roomspecv1.PropertyList{}
+	err = proto.Unmarshal(payload.Data, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &roomspecv1.PropertyList{Properties: []*roomspecv1.Property{{Key: "mode", Value: "ranked", Version: 1}}}
+	if !proto.Equal(list, want) {
+		t.Errorf("got properties %v, want %v", list, want)
+	}
+}
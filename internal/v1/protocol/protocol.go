@@ -17,6 +17,8 @@ limitations under the License.
 package protocol
 
 import (
+	"time"
+
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
 	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
@@ -25,6 +27,11 @@ import (
 
 // Protocol defines the contract that a v1 protocol should fufil, and the actions possible
 type Protocol interface {
+	// Versions defines the protocol versions that this protocol supports
+	Versions() []int32
+	// Capabilities defines the optional features that this protocol supports, which clients can opt into
+	Capabilities() []string
+
 	// Connect defines a client connecting to a room
 	Connect(payload *transport.Payload, connected *session.Session, currentRoom room.Room) (*session.Session, room.Room)
 	// Reconnect defines a client reconnecting to a room
@@ -39,11 +46,39 @@ type Protocol interface {
 	GrantHost(payload *transport.Payload, connected *session.Session, room room.Room)
 	// Kick defines a client removing another client from the room
 	Kick(payload *transport.Payload, connected *session.Session, room room.Room)
+	// Ack defines a client acknowledging relayed messages it has received
+	Ack(payload *transport.Payload, connected *session.Session, room room.Room)
+	// GrantRole defines a client changing the role of another client in the room
+	GrantRole(payload *transport.Payload, connected *session.Session, room room.Room)
+	// UpdateMetadata defines a client replacing its own metadata
+	UpdateMetadata(payload *transport.Payload, connected *session.Session, room room.Room)
+	// GetProperties defines a client requesting the room's properties
+	GetProperties(payload *transport.Payload, connected *session.Session, room room.Room)
+	// SetProperty defines a client setting one of the room's properties
+	SetProperty(payload *transport.Payload, connected *session.Session, room room.Room)
+	// DeleteProperty defines a client deleting one of the room's properties
+	DeleteProperty(payload *transport.Payload, connected *session.Session, room room.Room)
+	// BrowseRooms defines a client requesting a list of the public rooms that can be joined
+	BrowseRooms(payload *transport.Payload, connected *session.Session, room room.Room)
+	// QuickJoin defines a client joining the best fitting public room with a free slot, without knowing the room's ID
+	QuickJoin(payload *transport.Payload, connected *session.Session, currentRoom room.Room) (*session.Session, room.Room)
+	// Matchmake defines a client joining a matchmaking queue to be placed into a room with compatible clients
+	Matchmake(payload *transport.Payload, connected *session.Session, room room.Room)
+	// CancelMatchmake defines a client leaving the matchmaking queue it is waiting in
+	CancelMatchmake(payload *transport.Payload, connected *session.Session, room room.Room)
+	// JoinMatch defines a client joining the room the server has matched it into, called on the client's own session
+	// when it is woken by the match
+	JoinMatch(connected *session.Session, currentRoom room.Room) (*session.Session, room.Room)
 
 	// CloseRoom is a server based control for closing a room and disconnecting all clients
 	CloseRoom(roomID int32) error
-	CreateRoom(maxClients int32) (room.Room, error)
+	// ExpireClients is a server based control for forgetting disconnected clients that can no longer reconnect
+	ExpireClients(roomID int32, now time.Time) error
+	CreateRoom(options room.Options) (room.Room, error)
 	GetRoom(roomID int32) (room.Room, error)
 	Summary() (*api.RoomsSummary, error)
 	ListRooms() ([]room.Room, error)
+	// RotateInviteCode is a server based control for giving a room a new invite code, so the previous code can no
+	// longer be used to join it
+	RotateInviteCode(roomID int32) (string, error)
 }
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// CloseExpiredRooms closes every room that has expired as of the time provided, disconnecting any clients still
+// connected, returning the IDs of the rooms that were closed. A room that fails to close is logged and skipped, so that
+// it does not stop other rooms from being closed
+func CloseExpiredRooms(p Protocol, now time.Time) ([]int32, error) {
+	rooms, err := p.ListRooms()
+	if err != nil {
+		return nil, err
+	}
+
+	closed := []int32{}
+	for _, room := range rooms {
+		expiry := room.Expiry()
+		if expiry == nil || now.Before(*expiry) {
+			continue
+		}
+
+		info, err := room.GetInfo()
+		if err != nil {
+			glog.Errorf("Failed to retrieve expired room's info, %v", err)
+			continue
+		}
+
+		err = p.CloseRoom(info.ID)
+		if err != nil {
+			glog.Errorf("Failed to close expired room with ID %d, %v", info.ID, err)
+			continue
+		}
+
+		closed = append(closed, info.ID)
+	}
+
+	return closed, nil
+}
+
+// ExpireDisconnectedClients forgets every disconnected client whose reconnect grace period has passed as of the time
+// provided, notifying each room's host. A room that fails is logged and skipped, so that it does not stop clients in
+// other rooms from expiring
+func ExpireDisconnectedClients(p Protocol, now time.Time) error {
+	rooms, err := p.ListRooms()
+	if err != nil {
+		return err
+	}
+
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			glog.Errorf("Failed to retrieve room's info, %v", err)
+			continue
+		}
+
+		err = p.ExpireClients(info.ID, now)
+		if err != nil {
+			glog.Errorf("Failed to expire disconnected clients in room with ID %d, %v", info.ID, err)
+			continue
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+)
+
+func TestCloseExpiredRoomsWhileClientsJoin(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(1000, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	room, err := p.CreateRoom(roomv1.Options{MaxClients: 64, MaxLifetime: time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	sessions := make([]*sessionv1.Session, 32)
+	joined := make([]bool, len(sessions))
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range sessions {
+		sessions[i] = newTestSession()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			connected, err := room.NewClient(sessions[i])
+			if err != nil {
+				return
+			}
+			joined[i] = true
+			// The client disconnecting itself races the reaper disconnecting it
+			p.Disconnect(connected, room)
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-start
+		_, err := CloseExpiredRooms(p, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Errorf("failed to close expired rooms: %v", err)
+		}
+	}()
+
+	close(start)
+	wg.Wait()
+
+	if _, err := p.GetRoom(room.(*roomv1.MemoryRoom).ID); err == nil {
+		t.Errorf("expected expired room to be deleted")
+	}
+
+	for i, connected := range sessions {
+		if !joined[i] {
+			continue
+		}
+		select {
+		case <-connected.CloseSignal:
+		default:
+			t.Errorf("expected session %d that joined the room to be closed", i)
+		}
+	}
+}
+
+func TestExpireDisconnectedHostMigratesHost(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(1000, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	room, err := p.CreateRoom(roomv1.Options{MaxClients: 4, ReconnectGracePeriod: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	host, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	player, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = room.SetHost(&host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = room.RemoveClient(host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ExpireDisconnectedClients(p, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to expire disconnected clients: %v", err)
+	}
+
+	newHost, err := room.GetHost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newHost != player {
+		t.Fatalf("expected the connected player to become host once the disconnected host was forgotten")
+	}
+}
+
+// brokenRoom is a room that has expired but fails to provide its info
+type brokenRoom struct {
+	roomv1.Room
+}
+
+func (r *brokenRoom) Expiry() *time.Time {
+	expiry := time.Now().Add(-time.Hour)
+	return &expiry
+}
+
+func (r *brokenRoom) GetInfo() (*api.RoomInfo, error) {
+	return nil, fmt.Errorf("broken room")
+}
+
+// brokenRoomManager lists a broken room before the rooms of the manager it wraps
+type brokenRoomManager struct {
+	roomv1.Manager
+}
+
+func (m *brokenRoomManager) ListRooms() ([]roomv1.Room, error) {
+	rooms, err := m.Manager.ListRooms()
+	if err != nil {
+		return nil, err
+	}
+	return append([]roomv1.Room{&brokenRoom{}}, rooms...), nil
+}
+
+func TestReaperSkipsFailingRooms(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: &brokenRoomManager{
+			Manager: roomv1.NewMemoryManager(1000, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+				return roomv1.NewMemoryRoom(id, secret, options)
+			}, 1),
+		},
+	}
+
+	expiring, err := p.CreateRoom(roomv1.Options{MaxClients: 4, ReconnectGracePeriod: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	connected, err := expiring.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = expiring.RemoveClient(connected.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ExpireDisconnectedClients(p, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to expire disconnected clients: %v", err)
+	}
+	disconnected, err := expiring.GetDisconnected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disconnected) != 0 {
+		t.Errorf("expected disconnected clients after the broken room to expire, got %d", len(disconnected))
+	}
+
+	closing, err := p.CreateRoom(roomv1.Options{MaxClients: 4, MaxLifetime: time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	info, err := closing.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed, err := CloseExpiredRooms(p, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to close expired rooms: %v", err)
+	}
+	if len(closed) != 1 || closed[0] != info.ID {
+		t.Errorf("expected the expired room after the broken room to be closed, got %v", closed)
+	}
+}
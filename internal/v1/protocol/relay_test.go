@@ -0,0 +1,447 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+	relayv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+// relayFailures drains the messages sent to a session, returning the targets reported in relay failures and the reason
+// of the last error
+func relayFailures(t *testing.T, connected *sessionv1.Session) ([]int32, transportv1.Error_ReasonType) {
+	var failed []int32
+	reason := transportv1.Error_UNKNOWN
+	for {
+		select {
+		case message := <-connected.Write:
+			payload := &transportv1.Payload{}
+			if err := proto.Unmarshal(message, payload); err != nil {
+				t.Fatal(err)
+			}
+			switch payload.Flag {
+			case transportv1.Payload_RESPONSE_RELAY_FAILURES:
+				failures := &relayv1.RelayFailures{}
+				if err := proto.Unmarshal(payload.Data, failures); err != nil {
+					t.Fatal(err)
+				}
+				for _, failure := range failures.Failures {
+					failed = append(failed, failure.Target)
+				}
+			case transportv1.Payload_RESPONSE_ERROR:
+				failure := &transportv1.Error{}
+				if err := proto.Unmarshal(payload.Data, failure); err != nil {
+					t.Fatal(err)
+				}
+				reason = failure.Reason
+			}
+		default:
+			return failed, reason
+		}
+	}
+}
+
+func TestRelayRecordsForDisconnectedClients(t *testing.T) {
+	unknown := int32(-1)
+
+	tests := []struct {
+		name     string
+		relay    func(targetID int32) *relayv1.Relay
+		recorded int
+		failed   []int32
+		// targetIsHost makes the disconnected target the room's host, rather than the sender
+		targetIsHost bool
+		reason       transportv1.Error_ReasonType
+	}{
+		{
+			name: "broadcast",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_BROADCAST}
+			},
+			recorded: 1,
+		},
+		{
+			name: "target",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_TARGET, Target: &targetID}
+			},
+			recorded: 1,
+		},
+		{
+			name: "multicast",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_MULTICAST, Targets: []int32{targetID, targetID}}
+			},
+			recorded: 1,
+		},
+		{
+			name: "multicast with unknown target",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_MULTICAST, Targets: []int32{targetID, unknown}}
+			},
+			recorded: 1,
+			failed:   []int32{unknown},
+		},
+		{
+			name: "except disconnected target",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_EXCEPT, Targets: []int32{targetID}}
+			},
+		},
+		{
+			name: "disconnected host",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_HOST}
+			},
+			recorded:     1,
+			targetIsHost: true,
+		},
+		{
+			name: "unknown target",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_TARGET, Target: &unknown}
+			},
+			reason: transportv1.Error_TARGET_NOT_FOUND,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4, ReplayBufferSize: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sender, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			target, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			host := sender
+			if tt.targetIsHost {
+				host = target
+			}
+			_, err = room.SetHost(&host.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = room.RemoveClient(target.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := proto.Marshal(tt.relay(target.Client.ID))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p.RelayMessage(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_RELAY_MESSAGE,
+				Data: data,
+			}, sender, room)
+
+			failed, reason := relayFailures(t, sender)
+			if reason != tt.reason {
+				t.Fatalf("got failure %s, want %s", reason, tt.reason)
+			}
+			if !reflect.DeepEqual(failed, tt.failed) {
+				t.Fatalf("got failed targets %v, want %v", failed, tt.failed)
+			}
+
+			messages, _, err := room.ReplayMessages(target.Client.ID, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(messages) != tt.recorded {
+				t.Fatalf("got %d recorded messages, want %d", len(messages), tt.recorded)
+			}
+		})
+	}
+}
+
+func TestRelayRejectedMessagesAreNotAcknowledged(t *testing.T) {
+	p := &StandardProtocol{
+		Reliable: NewReliable(DefaultReliableWindow, DefaultReliableQueueLimit),
+	}
+	room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Reliable.Join(1, sender.Client.ID)
+
+	data, err := proto.Marshal(&relayv1.Relay{Type: relayv1.Relay_BROADCAST})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acked := func() bool {
+		acked := false
+		for {
+			select {
+			case message := <-sender.Write:
+				payload := &transportv1.Payload{}
+				if err := proto.Unmarshal(message, payload); err != nil {
+					t.Fatal(err)
+				}
+				if payload.Flag == transportv1.Payload_RESPONSE_ACK {
+					acked = true
+				}
+			default:
+				return acked
+			}
+		}
+	}
+
+	sequence := uint64(1)
+	relay := &transportv1.Payload{
+		Flag:     transportv1.Payload_REQUEST_RELAY_MESSAGE,
+		Data:     data,
+		Sequence: &sequence,
+	}
+
+	// Players are not permitted to broadcast, so the message is rejected without using up its sequence number
+	p.RelayMessage(relay, sender, room)
+	if acked() {
+		t.Fatalf("expected a rejected message not to be acknowledged")
+	}
+
+	_, err = room.SetHost(&sender.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.RelayMessage(relay, sender, room)
+	if !acked() {
+		t.Fatalf("expected the message to be acknowledged once the sender is permitted to send it")
+	}
+}
+
+func TestRelayPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		relayTypes []string
+		relay      func(targetID int32) *relayv1.Relay
+		reason     transportv1.Error_ReasonType
+	}{
+		{
+			name: "host with default policy",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_HOST}
+			},
+		},
+		{
+			name: "broadcast with default policy",
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_BROADCAST}
+			},
+			reason: transportv1.Error_NOT_PERMITTED,
+		},
+		{
+			name:       "broadcast permitted",
+			relayTypes: []string{"BROADCAST"},
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_BROADCAST}
+			},
+		},
+		{
+			name:       "target when only broadcast permitted",
+			relayTypes: []string{"BROADCAST"},
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_TARGET, Target: &targetID}
+			},
+			reason: transportv1.Error_NOT_PERMITTED,
+		},
+		{
+			name:       "target permitted",
+			relayTypes: []string{"TARGET"},
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_TARGET, Target: &targetID}
+			},
+		},
+		{
+			name:       "multicast permitted",
+			relayTypes: []string{"MULTICAST"},
+			relay: func(targetID int32) *relayv1.Relay {
+				return &relayv1.Relay{Type: relayv1.Relay_MULTICAST, Targets: []int32{targetID}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			options, err := roomv1.OptionsFromSettings(api.RoomSettings{
+				MaxClients:       4,
+				ClientRelayTypes: tt.relayTypes,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			room, err := roomv1.NewMemoryRoom(1, 1, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = room.SetHost(&host.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sender, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			target, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := proto.Marshal(tt.relay(target.Client.ID))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p.RelayMessage(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_RELAY_MESSAGE,
+				Data: data,
+			}, sender, room)
+
+			_, reason := relayFailures(t, sender)
+			if reason != tt.reason {
+				t.Fatalf("got failure %s, want %s", reason, tt.reason)
+			}
+
+			recipient := target
+			if tt.relay(target.Client.ID).Type == relayv1.Relay_HOST {
+				recipient = host
+			}
+			delivered := len(recipient.Write) > 0
+			if delivered != (tt.reason == transportv1.Error_UNKNOWN) {
+				t.Errorf("got message delivered %t, want %t", delivered, tt.reason == transportv1.Error_UNKNOWN)
+			}
+		})
+	}
+}
+
+func TestRelayStampsSenderIdentity(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int32
+		stamped bool
+	}{
+		{name: "version 2 host opts out", version: Version2},
+		{name: "version 3 host", version: Version3, stamped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			host.ProtocolVersion = tt.version
+			_, err = room.SetHost(&host.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sender, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			relay := &relayv1.Relay{Type: relayv1.Relay_HOST, Data: []byte("ready")}
+			data, err := proto.Marshal(relay)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			before := time.Now().UnixNano() / int64(time.Millisecond)
+			p.RelayMessage(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_RELAY_MESSAGE,
+				Data: data,
+			}, sender, room)
+
+			payload := &transportv1.Payload{}
+			err = proto.Unmarshal(<-host.Write, payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !tt.stamped {
+				if payload.Flag != transportv1.Payload_RESPONSE_RELAY_MESSAGE {
+					t.Fatalf("got flag %s, want %s", payload.Flag, transportv1.Payload_RESPONSE_RELAY_MESSAGE)
+				}
+				received := &relayv1.Relay{}
+				err = proto.Unmarshal(payload.Data, received)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !proto.Equal(received, relay) {
+					t.Fatalf("got relay %v, want %v", received, relay)
+				}
+				return
+			}
+
+			if payload.Flag != transportv1.Payload_RESPONSE_RELAYED_MESSAGE {
+				t.Fatalf("got flag %s, want %s", payload.Flag, transportv1.Payload_RESPONSE_RELAYED_MESSAGE)
+			}
+			relayed := &relayv1.RelayedMessage{}
+			err = proto.Unmarshal(payload.Data, relayed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if relayed.Sender != sender.Client.ID {
+				t.Errorf("got sender %d, want %d", relayed.Sender, sender.Client.ID)
+			}
+			if relayed.Type != relayv1.Relay_HOST {
+				t.Errorf("got type %s, want %s", relayed.Type, relayv1.Relay_HOST)
+			}
+			if relayed.SentAt < before {
+				t.Errorf("got sent at %d, want at least %d", relayed.SentAt, before)
+			}
+			if !proto.Equal(relayed.Relay, relay) {
+				t.Errorf("got relay %v, want %v", relayed.Relay, relay)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"sync"
+)
+
+// CapabilityReliable is the capability clients opt into during the handshake to receive relayed messages with
+// reliable, ordered, exactly once delivery
+const CapabilityReliable = "reliable"
+
+const (
+	// DefaultReliableWindow is the default number of relayed messages that can be sent to a client without being
+	// acknowledged
+	DefaultReliableWindow = 32
+	// DefaultReliableQueueLimit is the default number of relayed messages that can be waiting to be sent or
+	// acknowledged for a client, before the oldest waiting message is dropped
+	DefaultReliableQueueLimit = 1024
+)
+
+// NewReliable creates a new reliable delivery tracker with the window and queue limit provided
+func NewReliable(window int, queueLimit int) *Reliable {
+	return &Reliable{
+		Window:     window,
+		QueueLimit: queueLimit,
+		states:     make(map[reliableKey]*reliableState),
+	}
+}
+
+// Reliable tracks reliable delivery of relayed messages for clients that have opted into it. Each message sent to a
+// client is given a sequence number and kept until the client acknowledges it, with at most a window of messages
+// unacknowledged at once; messages beyond the window are queued until earlier messages are acknowledged. Messages are
+// relayed to a client by the goroutines of every sender, so each batch of messages takes a turn to be written, and
+// waits for the batches given earlier sequence numbers to be written first. Messages sent by a client with a sequence
+// number are acknowledged, with any duplicates dropped. State is tracked by room and client ID so that unacknowledged
+// messages can be retransmitted when the client reconnects
+type Reliable struct {
+	Window     int
+	QueueLimit int
+
+	mutex  sync.Mutex
+	states map[reliableKey]*reliableState
+}
+
+type reliableKey struct {
+	roomID   int32
+	clientID int32
+}
+
+type reliableState struct {
+	nextSequence uint64
+	unacked      []ReliableMessage
+	pending      [][]byte
+	// lastReceived is the sequence number every message from the client up to has been received, with received
+	// holding any later sequence numbers received out of order
+	lastReceived uint64
+	received     map[uint64]bool
+	// gap is set if messages were dropped because the queue limit was reached
+	gap bool
+	// lastTurn is closed once the latest batch of messages to the client has been written
+	lastTurn chan struct{}
+}
+
+// takeTurn returns a channel to wait on before writing to the client, closed once every earlier batch has been
+// written, and a channel to close once this batch has been written. The caller must hold the tracker's lock
+func (s *reliableState) takeTurn() (<-chan struct{}, chan struct{}) {
+	previous := s.lastTurn
+	turn := make(chan struct{})
+	s.lastTurn = turn
+	return previous, turn
+}
+
+// writeInTurn waits for every earlier batch of messages to the client to be written, then writes this batch
+func writeInTurn(previous <-chan struct{}, turn chan struct{}, write func()) {
+	defer close(turn)
+	if previous != nil {
+		<-previous
+	}
+	write()
+}
+
+// ReliableMessage is a relayed message sent to a client with reliable delivery, with the sequence number assigned to
+// it
+type ReliableMessage struct {
+	Sequence uint64
+	Data     []byte
+}
+
+// Join starts tracking reliable delivery for a client that has newly joined a room, discarding any previous state
+// for the client ID
+func (r *Reliable) Join(roomID int32, clientID int32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.states[reliableKey{roomID, clientID}] = &reliableState{
+		nextSequence: 1,
+	}
+}
+
+// Rejoin resumes tracking reliable delivery for a client that has reconnected to a room, writing the messages that
+// should be retransmitted, and if any messages were dropped, before any messages sent after it. If the client was not
+// previously tracked it is tracked from now on
+func (r *Reliable) Rejoin(roomID int32, clientID int32, write func(messages []ReliableMessage, gap bool)) {
+	r.mutex.Lock()
+
+	key := reliableKey{roomID, clientID}
+	state, exists := r.states[key]
+	if !exists {
+		r.states[key] = &reliableState{
+			nextSequence: 1,
+		}
+		r.mutex.Unlock()
+		return
+	}
+
+	gap := state.gap
+	state.gap = false
+
+	retransmit := make([]ReliableMessage, len(state.unacked))
+	copy(retransmit, state.unacked)
+	previous, turn := state.takeTurn()
+	r.mutex.Unlock()
+
+	writeInTurn(previous, turn, func() {
+		write(retransmit, gap)
+	})
+}
+
+// Tracked determines if reliable delivery is being tracked for a client
+func (r *Reliable) Tracked(roomID int32, clientID int32) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, exists := r.states[reliableKey{roomID, clientID}]
+	return exists
+}
+
+// Send queues a message to be sent to a client, writing the messages that can be sent now within the client's window
+// once every earlier message has been written. If write is nil the messages are only kept to be retransmitted when
+// the client reconnects
+func (r *Reliable) Send(roomID int32, clientID int32, data []byte, write func(messages []ReliableMessage)) {
+	r.mutex.Lock()
+
+	state, exists := r.states[reliableKey{roomID, clientID}]
+	if !exists {
+		r.mutex.Unlock()
+		return
+	}
+
+	state.pending = append(state.pending, data)
+	if r.QueueLimit > 0 && len(state.unacked)+len(state.pending) > r.QueueLimit && len(state.pending) > 0 {
+		// Drop the oldest message that has not been sent yet
+		state.pending = state.pending[1:]
+		state.gap = true
+	}
+
+	r.deliver(state, write)
+}
+
+// Ack handles a client acknowledging every message up to and including the sequence number provided, writing any
+// queued messages that can now be sent within the client's window once every earlier message has been written
+func (r *Reliable) Ack(roomID int32, clientID int32, sequence uint64, write func(messages []ReliableMessage)) {
+	r.mutex.Lock()
+
+	state, exists := r.states[reliableKey{roomID, clientID}]
+	if !exists {
+		r.mutex.Unlock()
+		return
+	}
+
+	acked := 0
+	for acked < len(state.unacked) && state.unacked[acked].Sequence <= sequence {
+		acked++
+	}
+	state.unacked = state.unacked[acked:]
+
+	r.deliver(state, write)
+}
+
+// deliver moves queued messages into the client's window and writes them in turn, the caller must hold the tracker's
+// lock, which is released before waiting to write
+func (r *Reliable) deliver(state *reliableState, write func(messages []ReliableMessage)) {
+	sendable := r.fill(state)
+	if len(sendable) == 0 || write == nil {
+		r.mutex.Unlock()
+		return
+	}
+
+	previous, turn := state.takeTurn()
+	r.mutex.Unlock()
+
+	writeInTurn(previous, turn, func() {
+		write(sendable)
+	})
+}
+
+// Receive handles a client sending a message with a sequence number, returning if the message should be processed,
+// duplicate messages that have already been received should not be processed but should be acknowledged again.
+// Messages received out of order are processed, so a message that arrives after a later message is not mistaken for a
+// duplicate
+func (r *Reliable) Receive(roomID int32, clientID int32, sequence uint64) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	state, exists := r.states[reliableKey{roomID, clientID}]
+	if !exists {
+		return true
+	}
+
+	if sequence <= state.lastReceived || state.received[sequence] {
+		return false
+	}
+
+	if sequence != state.lastReceived+1 {
+		if state.received == nil {
+			state.received = make(map[uint64]bool)
+		}
+		state.received[sequence] = true
+		if r.QueueLimit > 0 && len(state.received) > r.QueueLimit {
+			// Too many messages are waiting on a gap, give up on the oldest gap so the state stays bounded
+			state.lastReceived = lowestSequence(state.received) - 1
+		}
+	} else {
+		state.lastReceived = sequence
+	}
+
+	for state.received[state.lastReceived+1] {
+		state.lastReceived++
+		delete(state.received, state.lastReceived)
+	}
+	return true
+}
+
+func lowestSequence(sequences map[uint64]bool) uint64 {
+	lowest := uint64(0)
+	for sequence := range sequences {
+		if lowest == 0 || sequence < lowest {
+			lowest = sequence
+		}
+	}
+	return lowest
+}
+
+// Forget stops tracking reliable delivery for a client
+func (r *Reliable) Forget(roomID int32, clientID int32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.states, reliableKey{roomID, clientID})
+}
+
+// ForgetRoom stops tracking reliable delivery for every client in a room
+func (r *Reliable) ForgetRoom(roomID int32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key := range r.states {
+		if key.roomID == roomID {
+			delete(r.states, key)
+		}
+	}
+}
+
+// fill moves queued messages into the client's window, assigning them sequence numbers
+func (r *Reliable) fill(state *reliableState) []ReliableMessage {
+	sendable := []ReliableMessage{}
+	for len(state.pending) > 0 && (r.Window <= 0 || len(state.unacked) < r.Window) {
+		message := ReliableMessage{
+			Sequence: state.nextSequence,
+			Data:     state.pending[0],
+		}
+		state.nextSequence++
+		state.pending = state.pending[1:]
+		state.unacked = append(state.unacked, message)
+		sendable = append(sendable, message)
+	}
+	return sendable
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// collect returns a write function that records the sequence numbers written
+func collect(mutex *sync.Mutex, written *[]uint64) func(messages []ReliableMessage) {
+	return func(messages []ReliableMessage) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, message := range messages {
+			*written = append(*written, message.Sequence)
+		}
+	}
+}
+
+func TestReliableWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		window     int
+		queueLimit int
+		sends      int
+		ack        uint64
+		written    []uint64
+		afterAck   []uint64
+		gap        bool
+	}{
+		{
+			name:     "within window",
+			window:   4,
+			sends:    3,
+			ack:      3,
+			written:  []uint64{1, 2, 3},
+			afterAck: []uint64{},
+		},
+		{
+			name:     "beyond window waits for ack",
+			window:   2,
+			sends:    5,
+			ack:      2,
+			written:  []uint64{1, 2},
+			afterAck: []uint64{3, 4},
+		},
+		{
+			name:       "queue limit drops oldest pending",
+			window:     1,
+			queueLimit: 3,
+			sends:      5,
+			ack:        1,
+			written:    []uint64{1},
+			afterAck:   []uint64{2},
+			gap:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reliable := NewReliable(tt.window, tt.queueLimit)
+			reliable.Join(1, 1)
+
+			var mutex sync.Mutex
+			written := []uint64{}
+			for i := 0; i < tt.sends; i++ {
+				reliable.Send(1, 1, []byte(fmt.Sprintf("message %d", i)), collect(&mutex, &written))
+			}
+			if fmt.Sprint(written) != fmt.Sprint(tt.written) {
+				t.Errorf("expected %v to be written, got %v", tt.written, written)
+			}
+
+			afterAck := []uint64{}
+			reliable.Ack(1, 1, tt.ack, collect(&mutex, &afterAck))
+			if fmt.Sprint(afterAck) != fmt.Sprint(tt.afterAck) {
+				t.Errorf("expected %v to be written after ack, got %v", tt.afterAck, afterAck)
+			}
+
+			var retransmitted []uint64
+			var gap bool
+			reliable.Rejoin(1, 1, func(messages []ReliableMessage, dropped bool) {
+				for _, message := range messages {
+					retransmitted = append(retransmitted, message.Sequence)
+				}
+				gap = dropped
+			})
+			if gap != tt.gap {
+				t.Errorf("expected gap to be %t, got %t", tt.gap, gap)
+			}
+			if len(retransmitted) > 0 && retransmitted[0] != tt.ack+1 {
+				t.Errorf("expected retransmit to start after the ack, got %v", retransmitted)
+			}
+		})
+	}
+}
+
+func TestReliableReceive(t *testing.T) {
+	tests := []struct {
+		name       string
+		queueLimit int
+		sequences  []uint64
+		processed  []bool
+	}{
+		{
+			name:      "in order",
+			sequences: []uint64{1, 2, 3},
+			processed: []bool{true, true, true},
+		},
+		{
+			name:      "duplicates",
+			sequences: []uint64{1, 2, 2, 1, 3},
+			processed: []bool{true, true, false, false, true},
+		},
+		{
+			name:      "late message after a gap",
+			sequences: []uint64{1, 3, 2, 3, 2, 4},
+			processed: []bool{true, true, true, false, false, true},
+		},
+		{
+			name:      "several gaps",
+			sequences: []uint64{5, 3, 1, 4, 2, 5},
+			processed: []bool{true, true, true, true, true, false},
+		},
+		{
+			name:       "too many messages waiting on a gap",
+			queueLimit: 2,
+			sequences:  []uint64{2, 3, 4, 1, 5},
+			processed:  []bool{true, true, true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reliable := NewReliable(DefaultReliableWindow, tt.queueLimit)
+			reliable.Join(1, 1)
+			for i, sequence := range tt.sequences {
+				if processed := reliable.Receive(1, 1, sequence); processed != tt.processed[i] {
+					t.Errorf("expected message %d with sequence %d processed to be %t", i, sequence, tt.processed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReliableConcurrentSendersWriteInOrder(t *testing.T) {
+	reliable := NewReliable(0, 0)
+	reliable.Join(1, 1)
+
+	// The write is slow so that later senders are ready to write before earlier senders have finished
+	var mutex sync.Mutex
+	written := []uint64{}
+	write := func(messages []ReliableMessage) {
+		for _, message := range messages {
+			for i := 0; i < 1000; i++ {
+				_ = fmt.Sprint(i)
+			}
+			mutex.Lock()
+			written = append(written, message.Sequence)
+			mutex.Unlock()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for sender := 0; sender < 8; sender++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				reliable.Send(1, 1, []byte("message"), write)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(written) != 400 {
+		t.Fatalf("expected 400 messages written, got %d", len(written))
+	}
+	for i, sequence := range written {
+		if sequence != uint64(i+1) {
+			t.Fatalf("expected messages to be written in sequence order, got %d at position %d", sequence, i)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	relayv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequestIDIsEchoedOnResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		flag transportv1.Payload_FlagType
+		data proto.Message
+		// fromHost sends the request from the room's host rather than a player
+		fromHost bool
+		response transportv1.Payload_FlagType
+	}{
+		{
+			name:     "connect while in a room",
+			flag:     transportv1.Payload_REQUEST_CONNECT,
+			data:     &roomspecv1.JoinRoomRequest{RoomID: 1},
+			response: transportv1.Payload_RESPONSE_ERROR,
+		},
+		{
+			name:     "reconnect while in a room",
+			flag:     transportv1.Payload_REQUEST_RECONNECT,
+			data:     &roomspecv1.RejoinRoomRequest{RoomID: 1},
+			response: transportv1.Payload_RESPONSE_ERROR,
+		},
+		{
+			name:     "list",
+			flag:     transportv1.Payload_REQUEST_LIST,
+			response: transportv1.Payload_RESPONSE_LIST,
+		},
+		{
+			name:     "kick unknown client",
+			flag:     transportv1.Payload_REQUEST_KICK,
+			data:     &roomspecv1.KickRequest{ClientID: -1},
+			fromHost: true,
+			response: transportv1.Payload_RESPONSE_ERROR,
+		},
+		{
+			name:     "grant host without permission",
+			flag:     transportv1.Payload_REQUEST_GRANT_HOST,
+			data:     &roomspecv1.GrantHostRequest{HostID: 0},
+			response: transportv1.Payload_RESPONSE_ERROR,
+		},
+		{
+			name:     "relay without permission",
+			flag:     transportv1.Payload_REQUEST_RELAY_MESSAGE,
+			data:     &relayv1.Relay{Type: relayv1.Relay_BROADCAST},
+			response: transportv1.Payload_RESPONSE_ERROR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = room.SetHost(&host.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			player, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requester := player
+			if tt.fromHost {
+				requester = host
+			}
+			requester.ProtocolVersion = Version2
+
+			var data []byte
+			if tt.data != nil {
+				data, err = proto.Marshal(tt.data)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			requestID := uint32(7)
+			Route(p, &transportv1.Payload{
+				Flag:      tt.flag,
+				Data:      data,
+				RequestID: &requestID,
+			}, requester, room)
+
+			response := &transportv1.Payload{}
+			err = proto.Unmarshal(<-requester.Write, response)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Flag != tt.response {
+				t.Fatalf("got response %s, want %s", response.Flag, tt.response)
+			}
+			if response.RequestID == nil || *response.RequestID != requestID {
+				t.Errorf("expected request ID %d to be echoed, got %v", requestID, response.RequestID)
+			}
+		})
+	}
+}
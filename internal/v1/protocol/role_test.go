@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	clientv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGrantRole(t *testing.T) {
+	tests := []struct {
+		name string
+		// fromPlayer sends the request from a player rather than the room's host
+		fromPlayer bool
+		target     string
+		role       clientv1.SanitisedClient_RoleType
+		reason     transportv1.Error_ReasonType
+	}{
+		{name: "host grants moderator", target: "player", role: clientv1.SanitisedClient_MODERATOR},
+		{name: "host grants player", target: "player", role: clientv1.SanitisedClient_PLAYER},
+		{
+			name:       "player grants moderator",
+			fromPlayer: true,
+			target:     "player",
+			role:       clientv1.SanitisedClient_MODERATOR,
+			reason:     transportv1.Error_NOT_PERMITTED,
+		},
+		{
+			name:   "host grants host role",
+			target: "player",
+			role:   clientv1.SanitisedClient_HOST,
+			reason: transportv1.Error_INVALID_REQUEST,
+		},
+		{
+			name:   "host grants spectator role",
+			target: "player",
+			role:   clientv1.SanitisedClient_SPECTATOR,
+			reason: transportv1.Error_INVALID_REQUEST,
+		},
+		{
+			name:   "role of the host",
+			target: "host",
+			role:   clientv1.SanitisedClient_MODERATOR,
+			reason: transportv1.Error_INVALID_TARGET,
+		},
+		{
+			name:   "role of a spectator",
+			target: "spectator",
+			role:   clientv1.SanitisedClient_MODERATOR,
+			reason: transportv1.Error_INVALID_TARGET,
+		},
+		{
+			name:   "role of an unknown client",
+			target: "unknown",
+			role:   clientv1.SanitisedClient_MODERATOR,
+			reason: transportv1.Error_TARGET_NOT_FOUND,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StandardProtocol{}
+			room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4, MaxSpectators: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = room.SetHost(&host.Client.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			player, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+			spectator, err := room.NewSpectator(newTestSession())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			targets := map[string]int32{
+				"host":      host.Client.ID,
+				"player":    player.Client.ID,
+				"spectator": spectator.Client.ID,
+				"unknown":   -1,
+			}
+			requester := host
+			if tt.fromPlayer {
+				requester = player
+			}
+
+			data, err := proto.Marshal(&roomspecv1.GrantRoleRequest{ClientID: targets[tt.target], Role: tt.role})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p.GrantRole(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_GRANT_ROLE,
+				Data: data,
+			}, requester, room)
+
+			if tt.reason != transportv1.Error_UNKNOWN {
+				if reason := lastErrorReason(t, requester); reason != tt.reason {
+					t.Fatalf("got failure %s, want %s", reason, tt.reason)
+				}
+				return
+			}
+
+			// Every client in the room is told about the change of role
+			for name, notified := range map[string]*sessionv1.Session{"host": host, "spectator": spectator} {
+				payload := &transportv1.Payload{}
+				err = proto.Unmarshal(<-notified.Write, payload)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if payload.Flag != transportv1.Payload_RESPONSE_ROLE_CHANGE {
+					t.Fatalf("%s got flag %s, want %s", name, payload.Flag,
+						transportv1.Payload_RESPONSE_ROLE_CHANGE)
+				}
+				change := &clientv1.SanitisedClient{}
+				err = proto.Unmarshal(payload.Data, change)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if change.ID != player.Client.ID || change.Role != tt.role {
+					t.Fatalf("%s got role change %v, want client %d to become %s", name, change,
+						player.Client.ID, tt.role)
+				}
+			}
+
+			// The new role is included when clients are listed
+			p.List(&transportv1.Payload{Flag: transportv1.Payload_REQUEST_LIST}, host, room)
+			payload := &transportv1.Payload{}
+			err = proto.Unmarshal(<-host.Write, payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			list := &clientv1.ClientList{}
+			err = proto.Unmarshal(payload.Data, list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, client := range list.List {
+				if client.ID == player.Client.ID && client.Role != tt.role {
+					t.Errorf("got listed role %s, want %s", client.Role, tt.role)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+)
+
+// Route passes a client's request to the protocol action that handles it based on the payload's flag, returning the
+// session and the room the client is connected to after the request has been handled
+func Route(p Protocol, payload *transport.Payload, connected *session.Session, currentRoom room.Room) (*session.Session, room.Room) {
+	payload.RequestID = RequestID(connected, payload)
+
+	switch payload.Flag {
+	case transport.Payload_REQUEST_CONNECT:
+		return p.Connect(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_RECONNECT:
+		return p.Reconnect(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_QUICK_JOIN:
+		return p.QuickJoin(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_LIST:
+		p.List(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_RELAY_MESSAGE:
+		p.RelayMessage(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_GRANT_HOST:
+		p.GrantHost(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_KICK:
+		p.Kick(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_ACK:
+		p.Ack(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_GRANT_ROLE:
+		p.GrantRole(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_UPDATE_METADATA:
+		p.UpdateMetadata(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_GET_PROPERTIES:
+		p.GetProperties(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_SET_PROPERTY:
+		p.SetProperty(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_DELETE_PROPERTY:
+		p.DeleteProperty(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_BROWSE_ROOMS:
+		p.BrowseRooms(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_MATCHMAKE:
+		p.Matchmake(payload, connected, currentRoom)
+	case transport.Payload_REQUEST_CANCEL_MATCHMAKE:
+		p.CancelMatchmake(payload, connected, currentRoom)
+	}
+	return connected, currentRoom
+}
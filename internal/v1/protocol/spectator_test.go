@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	clientv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	relayv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSpectatorsJoinSeparatePool(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	room, err := p.CreateRoom(roomv1.Options{MaxClients: 1, MaxSpectators: 1, Public: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		spectator bool
+		reason    transportv1.Error_ReasonType
+		host      bool
+	}{
+		// Spectators can never be host, even when they are the first to join
+		{name: "first spectator", spectator: true},
+		{name: "first player", host: true},
+		{name: "second player", reason: transportv1.Error_ROOM_FULL},
+		{name: "second spectator", spectator: true, reason: transportv1.Error_ROOM_FULL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := proto.Marshal(&roomspecv1.JoinRoomRequest{RoomID: info.ID, Spectator: tt.spectator})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			connected := newTestSession()
+			connected, joined := p.Connect(&transportv1.Payload{
+				Flag: transportv1.Payload_REQUEST_CONNECT,
+				Data: data,
+			}, connected, nil)
+
+			if reason := lastErrorReason(t, connected); reason != tt.reason {
+				t.Fatalf("got failure %s, want %s", reason, tt.reason)
+			}
+			if joined == nil {
+				return
+			}
+
+			isHost, err := room.IsHost(connected.Client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isHost != tt.host {
+				t.Errorf("got host %t, want %t", isHost, tt.host)
+			}
+		})
+	}
+
+	info, err = room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CurrentClients != 1 || info.CurrentSpectators != 1 {
+		t.Errorf("got %d clients and %d spectators, want 1 of each", info.CurrentClients, info.CurrentSpectators)
+	}
+}
+
+// newSpectatorRoom creates a room with a host, a player and a spectator, in that order of joining unless the spectator
+// should join first
+func newSpectatorRoom(t *testing.T, spectatorFirst bool) (roomv1.Room, *sessionv1.Session, *sessionv1.Session, *sessionv1.Session) {
+	room, err := roomv1.NewMemoryRoom(1, 1, roomv1.Options{MaxClients: 4, MaxSpectators: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spectator *sessionv1.Session
+	if spectatorFirst {
+		spectator, err = room.NewSpectator(newTestSession())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	host, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = room.SetHost(&host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spectatorFirst {
+		spectator, err = room.NewSpectator(newTestSession())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return room, host, player, spectator
+}
+
+func TestSpectatorsOnlyReceive(t *testing.T) {
+	p := &StandardProtocol{}
+	room, host, _, spectator := newSpectatorRoom(t, false)
+
+	data, err := proto.Marshal(&relayv1.Relay{Type: relayv1.Relay_HOST})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.RelayMessage(&transportv1.Payload{
+		Flag: transportv1.Payload_REQUEST_RELAY_MESSAGE,
+		Data: data,
+	}, spectator, room)
+
+	if reason := lastErrorReason(t, spectator); reason != transportv1.Error_NOT_PERMITTED {
+		t.Fatalf("got failure %s, want %s", reason, transportv1.Error_NOT_PERMITTED)
+	}
+	if len(host.Write) != 0 {
+		t.Fatalf("expected the host not to receive the spectator's message")
+	}
+
+	data, err = proto.Marshal(&relayv1.Relay{Type: relayv1.Relay_BROADCAST})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.RelayMessage(&transportv1.Payload{
+		Flag: transportv1.Payload_REQUEST_RELAY_MESSAGE,
+		Data: data,
+	}, host, room)
+
+	if len(spectator.Write) != 1 {
+		t.Fatalf("expected the spectator to receive the host's broadcast")
+	}
+
+	data, err = proto.Marshal(&roomspecv1.GrantHostRequest{HostID: spectator.Client.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.GrantHost(&transportv1.Payload{
+		Flag: transportv1.Payload_REQUEST_GRANT_HOST,
+		Data: data,
+	}, host, room)
+
+	if reason := lastErrorReason(t, host); reason != transportv1.Error_INVALID_TARGET {
+		t.Fatalf("got failure %s, want %s", reason, transportv1.Error_INVALID_TARGET)
+	}
+}
+
+func TestMigrateHostSkipsSpectators(t *testing.T) {
+	p := &StandardProtocol{}
+	room, host, player, _ := newSpectatorRoom(t, true)
+
+	err := room.RemoveClient(host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.migrateHost(room)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newHost, err := room.GetHost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newHost == nil || newHost.Client.ID != player.Client.ID {
+		t.Fatalf("expected the player to become host, got %v", newHost)
+	}
+
+	err = room.RemoveClient(player.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.migrateHost(room)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newHost, err = room.GetHost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newHost != nil {
+		t.Fatalf("expected no host when only spectators remain, got client %d", newHost.Client.ID)
+	}
+}
+
+func TestListSeparatesSpectators(t *testing.T) {
+	p := &StandardProtocol{}
+	room, host, player, spectator := newSpectatorRoom(t, false)
+
+	p.List(&transportv1.Payload{Flag: transportv1.Payload_REQUEST_LIST}, host, room)
+
+	payload := &transportv1.Payload{}
+	err := proto.Unmarshal(<-host.Write, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := &clientv1.ClientList{}
+	err = proto.Unmarshal(payload.Data, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.List) != 2 || list.List[0].ID != host.Client.ID || list.List[1].ID != player.Client.ID {
+		t.Errorf("expected the host and player to be listed, got %v", list.List)
+	}
+	if len(list.Spectators) != 1 || list.Spectators[0].ID != spectator.Client.ID ||
+		list.Spectators[0].Role != clientv1.SanitisedClient_SPECTATOR {
+		t.Errorf("expected the spectator to be listed separately, got %v", list.Spectators)
+	}
+}
@@ -19,6 +19,7 @@ package protocol
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/golang/glog"
 	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
@@ -32,88 +33,126 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-// StandardProtocol is the standard implementation of the v1 relay protocol
+// StandardProtocol is the standard implementation of the v1 relay protocol, reliable delivery is only offered to
+// clients if a reliable delivery tracker is provided, and matchmaking is only offered if a matchmaker is provided.
+// Clients quick joining only create rooms if room templates are provided with a template named by the quick join
+// template
 type StandardProtocol struct {
-	RoomManager roomv1.Manager
+	RoomManager       roomv1.Manager
+	Reliable          *Reliable
+	Matchmaker        *Matchmaker
+	Templates         *roomv1.Templates
+	QuickJoinTemplate string
 }
 
-// Connect handles a new client connecting to a room
+// Versions returns the protocol versions supported by the standard protocol
+func (p *StandardProtocol) Versions() []int32 {
+	return []int32{Version1, Version2, Version3}
+}
+
+// Capabilities returns the optional features supported by the standard protocol
+func (p *StandardProtocol) Capabilities() []string {
+	if p.Reliable != nil {
+		return []string{CapabilityReliable}
+	}
+	return []string{}
+}
+
+// Connect handles a new client connecting to a room, identified by either the room's ID and secret, the ID alone for
+// public rooms, or the room's invite code, with the room's password required unless the room's secret is provided
 func (p *StandardProtocol) Connect(payload *transportv1.Payload, connected *sessionv1.Session, currentRoom roomv1.Room) (*sessionv1.Session, roomv1.Room) {
 	if currentRoom != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Cannot connect to a different room while already connected to another",
+			Reason:  transportv1.Error_ALREADY_IN_ROOM,
 		})
 		return connected, currentRoom
 	}
 
+	p.cancelMatchmaking(connected)
+
 	joinRequest := &roomspecv1.JoinRoomRequest{}
 	err := proto.Unmarshal(payload.Data, joinRequest)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("Invalid join request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
 		})
 		return connected, currentRoom
 	}
 
-	rooms, err := p.RoomManager.ListRooms()
+	err = roomv1.ValidateMetadata(joinRequest.Metadata)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusInternalServerError,
-			Message: fmt.Sprintf("Failed to retrieve room list, %v", err),
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.(roomv1.ErrInvalidMetadata).Message,
+			Reason:  transportv1.Error_INVALID_REQUEST,
 		})
 		return connected, currentRoom
 	}
 
-	for _, matchRoom := range rooms {
-		if !matchRoom.RoomMatches(joinRequest.RoomID, joinRequest.RoomSecret) {
-			continue
-		}
-		connected, err = matchRoom.NewClient(connected)
+	if joinRequest.InviteCode != "" {
+		rooms, err := p.RoomManager.ListRooms()
 		if err != nil {
-			switch v := err.(type) {
-			case roomv1.ErrRoomFull:
-				connected.Write <- Fail(&transportv1.Error{
-					Code:    http.StatusBadRequest,
-					Message: v.Message,
-				})
-				return connected, currentRoom
-			default:
-				connected.Write <- Fail(&transportv1.Error{
-					Code:    http.StatusInternalServerError,
-					Message: fmt.Sprintf("Failed to register new client to room, %v", err),
-				})
-				return connected, currentRoom
-			}
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to retrieve room list, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, currentRoom
 		}
+		return p.joinByInviteCode(payload, joinRequest, connected, currentRoom, rooms)
+	}
 
-		responseClient := &clientv1.Client{
-			ID:     connected.Client.ID,
-			Secret: connected.Client.Secret,
+	matchRoom, err := p.RoomManager.GetRoom(joinRequest.RoomID)
+	if err != nil {
+		switch err.(type) {
+		case roomv1.ErrNoRoomFound:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("No valid room match found for ID %d", joinRequest.RoomID),
+				Reason:  transportv1.Error_ROOM_NOT_FOUND,
+			})
+			return connected, currentRoom
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to retrieve room, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, currentRoom
 		}
+	}
 
-		responseData, err := proto.Marshal(responseClient)
-		if err != nil {
-			// Should not occur, panic
-			panic(err)
-		}
+	if matchRoom.RoomMatches(joinRequest.RoomID, joinRequest.RoomSecret) {
+		return p.join(payload, joinRequest, connected, currentRoom, matchRoom, joinRequest.RoomID)
+	}
 
-		connected.Write <- Succeed(&transportv1.Payload{
-			Flag: transportv1.Payload_RESPONSE_CONNECT,
-			Data: responseData,
+	info, err := matchRoom.GetInfo()
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to retrieve room info, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
 		})
+		return connected, currentRoom
+	}
 
-		p.setHostIfNone(connected, matchRoom)
-
-		p.sendClientConnectToHost(connected, matchRoom)
-
-		return connected, matchRoom
+	if info.Public {
+		// Public rooms are listed in the lobby without their secret, so they can be joined by ID alone, with the
+		// room's password if it has one
+		if p.tooManyPasswordFailures(payload, connected) {
+			return connected, currentRoom
+		}
+		return p.joinWithPassword(payload, joinRequest, connected, currentRoom, matchRoom, info)
 	}
 
-	connected.Write <- Fail(&transportv1.Error{
+	connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 		Code:    http.StatusBadRequest,
 		Message: fmt.Sprintf("No valid room match found for ID %d", joinRequest.RoomID),
+		Reason:  transportv1.Error_ROOM_NOT_FOUND,
 	})
 
 	return connected, currentRoom
@@ -122,92 +161,133 @@ func (p *StandardProtocol) Connect(payload *transportv1.Payload, connected *sess
 // Reconnect handles an existing client reconnecting to a room
 func (p *StandardProtocol) Reconnect(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) (*sessionv1.Session, roomv1.Room) {
 	if room != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Cannot connect to a different room while already connected to another",
+			Reason:  transportv1.Error_ALREADY_IN_ROOM,
 		})
 		return connected, room
 	}
 
+	p.cancelMatchmaking(connected)
+
 	rejoinRequest := &roomspecv1.RejoinRoomRequest{}
 	err := proto.Unmarshal(payload.Data, rejoinRequest)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("Invalid join request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
 		})
 		return connected, room
 	}
 
-	rooms, err := p.RoomManager.ListRooms()
+	matchRoom, err := p.RoomManager.GetRoom(rejoinRequest.RoomID)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusInternalServerError,
-			Message: fmt.Sprintf("Failed to retrieve room list, %v", err),
+		switch err.(type) {
+		case roomv1.ErrNoRoomFound:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("No valid room match found for ID %d", rejoinRequest.RoomID),
+				Reason:  transportv1.Error_ROOM_NOT_FOUND,
+			})
+			return connected, room
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to retrieve room, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, room
+		}
+	}
+
+	if !matchRoom.RoomMatches(rejoinRequest.RoomID, rejoinRequest.RoomSecret) {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("No valid room match found for ID %d", rejoinRequest.RoomID),
+			Reason:  transportv1.Error_ROOM_NOT_FOUND,
 		})
 		return connected, room
 	}
 
-	for _, matchRoom := range rooms {
-		if matchRoom.RoomMatches(rejoinRequest.RoomID, rejoinRequest.RoomSecret) {
-			connected, err = matchRoom.ExistingClient(connected, rejoinRequest.ClientID, rejoinRequest.ClientSecret)
-			if err != nil {
-				switch v := err.(type) {
-				case roomv1.ErrInvalidSecret:
-					connected.Write <- Fail(&transportv1.Error{
-						Code:    http.StatusBadRequest,
-						Message: v.Message,
-					})
-					return connected, room
-				case roomv1.ErrRoomFull:
-					connected.Write <- Fail(&transportv1.Error{
-						Code:    http.StatusBadRequest,
-						Message: v.Message,
-					})
-					return connected, room
-				default:
-					connected.Write <- Fail(&transportv1.Error{
-						Code:    http.StatusInternalServerError,
-						Message: fmt.Sprintf("Failed to register existing client to room, %v", err),
-					})
-					return connected, room
-				}
-			}
-
-			responseClient := &clientv1.Client{
-				ID:     connected.Client.ID,
-				Secret: connected.Client.Secret,
-			}
-
-			responseData, err := proto.Marshal(responseClient)
-			if err != nil {
-				// Should not occur, panic
-				panic(err)
-			}
-
-			connected.Write <- Succeed(&transportv1.Payload{
-				Flag: transportv1.Payload_RESPONSE_CONNECT,
-				Data: responseData,
+	connected, err = matchRoom.ExistingClient(connected, rejoinRequest.ClientID, rejoinRequest.ClientSecret)
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrInvalidSecret:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, room
+		case roomv1.ErrRoomFull:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, room
+		case roomv1.ErrNoMatchingClient:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, room
+		case roomv1.ErrRoomOnOtherNode:
+			p.redirect(payload, connected, rejoinRequest.RoomID, v)
+			return connected, room
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to register existing client to room, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
+			return connected, room
+		}
+	}
+
+	responseClient := &clientv1.Client{
+		ID:     connected.Client.ID,
+		Secret: connected.Client.Secret,
+	}
 
-			p.setHostIfNone(connected, matchRoom)
+	responseData, err := proto.Marshal(responseClient)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
 
-			p.sendClientConnectToHost(connected, matchRoom)
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_CONNECT,
+		Data: responseData,
+	})
 
-			return connected, matchRoom
+	if p.Reliable != nil && connected.HasCapability(CapabilityReliable) {
+		p.retransmit(connected, rejoinRequest.RoomID)
+	} else {
+		if p.Reliable != nil {
+			// Client has stopped using reliable delivery
+			p.Reliable.Forget(rejoinRequest.RoomID, connected.Client.ID)
+		}
+		if rejoinRequest.LastSequence != nil {
+			p.replay(connected, matchRoom, *rejoinRequest.LastSequence)
 		}
 	}
 
-	connected.Write <- Fail(&transportv1.Error{
-		Code:    http.StatusBadRequest,
-		Message: fmt.Sprintf("No valid room match found for ID %d", rejoinRequest.RoomID),
-	})
-	return connected, room
+	p.setHostIfNone(connected, matchRoom)
+
+	p.sendClientConnectToHost(connected, matchRoom)
+
+	return connected, matchRoom
 }
 
-// Disconnect handles a client disconnecting from a room and closing the connection
+// Disconnect handles a client disconnecting from a room and closing the connection, the client is remembered so that
+// it can reconnect within the room's reconnect grace period
 func (p *StandardProtocol) Disconnect(connected *sessionv1.Session, room roomv1.Room) {
 	connected.Close()
+	p.cancelMatchmaking(connected)
 	if connected.Client == nil || room == nil || room.GetStatus() == roomv1.StatusClosing {
 		return
 	}
@@ -229,54 +309,102 @@ func (p *StandardProtocol) Disconnect(connected *sessionv1.Session, room roomv1.
 		}
 	}
 
-	p.sendClientDisconnectToHost(connected, room)
+	p.sendClientEventToHost(transportv1.Payload_RESPONSE_CLIENT_DISCONNECT, connected.Client, room)
+
+	err = p.pruneDisconnected(room, time.Now())
+	if err != nil {
+		glog.Errorf("Failed to prune disconnected clients, %v", err)
+	}
+}
+
+// leave handles a client permanently leaving a room and closing the connection, the client is forgotten so that it
+// cannot reconnect
+func (p *StandardProtocol) leave(connected *sessionv1.Session, room roomv1.Room) {
+	connected.Close()
+	if connected.Client == nil || room == nil || room.GetStatus() == roomv1.StatusClosing {
+		return
+	}
+
+	isHost, err := room.IsHost(connected.Client)
+	if err != nil {
+		glog.Errorf("Failed to determine if leaving client with ID %d is host, %v", connected.Client.ID, err)
+	}
+
+	err = room.ForgetClient(connected.Client.ID)
+	if err != nil {
+		glog.Errorf("Failed to remove client with ID %d, %v", connected.Client.ID, err)
+	}
+
+	if p.Reliable != nil {
+		p.Reliable.Forget(p.roomID(room), connected.Client.ID)
+	}
+
+	if isHost {
+		err := p.migrateHost(room)
+		if err != nil {
+			glog.Errorf("Failed to migrate host, %v", err)
+		}
+	}
+
+	p.sendClientEventToHost(transportv1.Payload_RESPONSE_CLIENT_LEAVE, connected.Client, room)
 }
 
 // List handles a client requesting a list of all clients connected to a room
 func (p *StandardProtocol) List(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
 	if connected == nil || room == nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Must be connected to a room to list a room's clients",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
 		})
 		return
 	}
 
+	if !p.checkPermission(payload, connected, room, roomv1.PermissionList, "list the room's clients") {
+		return
+	}
+
 	connectedClients, err := room.GetConnected()
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusInternalServerError,
 			Message: fmt.Sprintf("Failed to retrieve room's connected clients, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
 		})
 		return
 	}
 
 	list := make([]*clientv1.SanitisedClient, 0)
+	spectators := make([]*clientv1.SanitisedClient, 0)
 	for i := 0; i < len(connectedClients); i++ {
 		connectedClient := connectedClients[i]
-		host, err := room.IsHost(connectedClient.Client)
+		sanitised, err := p.sanitise(connectedClient.Client, room)
 		if err != nil {
-			connected.Write <- Fail(&transportv1.Error{
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 				Code:    http.StatusInternalServerError,
-				Message: fmt.Sprintf("Failed to determine if client is host, %v", err),
+				Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
 			return
 		}
-		list = append(list, &clientv1.SanitisedClient{
-			ID:   connectedClient.Client.ID,
-			Host: host,
-		})
+		// Spectators are listed separately, so they are not mistaken for players
+		if sanitised.Role == clientv1.SanitisedClient_SPECTATOR {
+			spectators = append(spectators, sanitised)
+			continue
+		}
+		list = append(list, sanitised)
 	}
 
 	responseData, err := proto.Marshal(&clientv1.ClientList{
-		List: list,
+		List:       list,
+		Spectators: spectators,
 	})
 	if err != nil {
 		// Should not occur, panic
 		panic(err)
 	}
 
-	connected.Write <- Succeed(&transportv1.Payload{
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
 		Flag: transportv1.Payload_RESPONSE_LIST,
 		Data: responseData,
 	})
@@ -286,9 +414,10 @@ func (p *StandardProtocol) List(payload *transportv1.Payload, connected *session
 // RelayMessage handles a client sending a message to the room
 func (p *StandardProtocol) RelayMessage(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
 	if connected == nil || room == nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Must be connected to a room to relay a message",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
 		})
 		return
 	}
@@ -296,106 +425,268 @@ func (p *StandardProtocol) RelayMessage(payload *transportv1.Payload, connected
 	relayMsg := &relayv1.Relay{}
 	err := proto.Unmarshal(payload.Data, relayMsg)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("Relayed message does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	allowed, err := room.AllowRelay(connected.Client.ID, time.Now())
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to check the room's relay rate limit, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	if !allowed {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusTooManyRequests,
+			Message: "Relay rate limit exceeded, message dropped",
+			Reason:  transportv1.Error_RATE_LIMITED,
 		})
 		return
 	}
 
 	connectedClientList, err := room.GetConnected()
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusInternalServerError,
 			Message: fmt.Sprintf("Failed to retrieve room's connected clients, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
 		})
 		return
 	}
 
 	isHost, err := room.IsHost(connected.Client)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusInternalServerError,
 			Message: fmt.Sprintf("Failed to determine if client is host, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
 		})
 		return
 	}
 
-	switch relayMsg.Type {
-	case relayv1.Relay_BROADCAST:
-		if !isHost {
-			connected.Write <- Fail(&transportv1.Error{
-				Code:    http.StatusBadRequest,
-				Message: "Must be host to broadcast",
-			})
-			return
-		}
-		p.broadcast(payload, connected, room, connectedClientList)
+	role, err := room.GetRole(connected.Client.ID)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
 		return
-	case relayv1.Relay_TARGET:
-		if !isHost {
-			connected.Write <- Fail(&transportv1.Error{
-				Code:    http.StatusBadRequest,
-				Message: "Must be host to send targeted messages",
+	}
+
+	if role == roomv1.RoleSpectator {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Spectators cannot relay messages",
+			Reason:  transportv1.Error_NOT_PERMITTED,
+		})
+		return
+	}
+
+	// Stamp the message with the sender's identity, so recipients know who sent it
+	relayed, err := proto.Marshal(&relayv1.RelayedMessage{
+		Sender: connected.Client.ID,
+		Type:   relayMsg.Type,
+		SentAt: time.Now().UnixNano() / int64(time.Millisecond),
+		Relay:  relayMsg,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	// Any client can relay messages to the host, every other relay type is limited by the client's role
+	permitted := true
+	if relayMsg.Type != relayv1.Relay_HOST {
+		permission := roomv1.PermissionTarget
+		if relayMsg.Type == relayv1.Relay_BROADCAST || relayMsg.Type == relayv1.Relay_EXCEPT {
+			permission = roomv1.PermissionBroadcast
+		}
+
+		permitted, err = p.permitted(connected, room, permission)
+		if err != nil {
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to determine client's permissions, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
 			return
 		}
+	}
 
-		if relayMsg.Target == nil {
-			connected.Write <- Fail(&transportv1.Error{
-				Code:    http.StatusBadRequest,
-				Message: "Must provide a target ID to send a message to",
-			})
+	relayErr := validateRelay(relayMsg, isHost, permitted)
+	if relayErr != nil {
+		connected.Write <- FailRequest(payload.RequestID, relayErr)
+		return
+	}
+
+	// Only messages that passed validation use up a reliable sequence number and are acknowledged, so the sender
+	// never believes a rejected message was delivered
+	if payload.Sequence != nil && p.tracked(connected, room) {
+		roomID := p.roomID(room)
+		// Acknowledge the message once it has been handled, including duplicates which are not relayed again
+		defer p.sendAck(connected, *payload.Sequence)
+		if !p.Reliable.Receive(roomID, connected.Client.ID, *payload.Sequence) {
 			return
 		}
+	}
 
+	switch relayMsg.Type {
+	case relayv1.Relay_BROADCAST:
+		p.broadcast(relayed, connected, room, connectedClientList, nil)
+		return
+	case relayv1.Relay_MULTICAST:
+		p.multicast(payload.RequestID, relayed, connected, room, connectedClientList, relayMsg.Targets)
+		return
+	case relayv1.Relay_EXCEPT:
+
+		failures := p.unknownTargets(connected, room, connectedClientList, relayMsg.Targets)
+		p.broadcast(relayed, connected, room, connectedClientList, relayMsg.Targets)
+		p.sendRelayFailures(payload.RequestID, connected, failures)
+		return
+	case relayv1.Relay_TARGET:
 		for _, connectedClient := range connectedClientList {
 			if *relayMsg.Target != connectedClient.Client.ID {
 				continue
 			}
-			connectedClient.Write <- Succeed(&transportv1.Payload{
-				Flag: transportv1.Payload_RESPONSE_RELAY_MESSAGE,
-				Data: payload.Data,
+			p.relay(relayed, connectedClient, room)
+			return
+		}
+
+		disconnected, err := p.isDisconnected(room, *relayMsg.Target)
+		if err != nil {
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to retrieve room's disconnected clients, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
 			return
 		}
-		connected.Write <- Fail(&transportv1.Error{
+
+		if disconnected {
+			// Keep the message so the target receives it if they reconnect
+			p.record(relayed, *relayMsg.Target, room)
+			return
+		}
+
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("No target client found with ID %d", *relayMsg.Target),
+			Reason:  transportv1.Error_TARGET_NOT_FOUND,
 		})
 		return
 	case relayv1.Relay_HOST:
-		if isHost {
-			connected.Write <- Fail(&transportv1.Error{
-				Code:    http.StatusBadRequest,
-				Message: "Hosts cannot send messages to themselves",
+		host, err := room.GetHost()
+		if err != nil {
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to get host, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
 			return
 		}
 
-		host, err := room.GetHost()
+		if host != nil {
+			p.relay(relayed, host, room)
+			return
+		}
+
+		hostID, err := p.disconnectedHost(room)
 		if err != nil {
-			connected.Write <- Fail(&transportv1.Error{
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 				Code:    http.StatusInternalServerError,
 				Message: fmt.Sprintf("Failed to get host, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
 			return
 		}
 
-		host.Write <- Succeed(&transportv1.Payload{
-			Flag: transportv1.Payload_RESPONSE_RELAY_MESSAGE,
-			Data: payload.Data,
-		})
+		if hostID == nil {
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Room has no host to send the message to",
+				Reason:  transportv1.Error_TARGET_NOT_FOUND,
+			})
+			return
+		}
+
+		// Keep the message so the host receives it if they reconnect
+		p.record(relayed, *hostID, room)
 	}
 	return
 }
 
+// validateRelay checks that a relayed message is well formed and that the sender is allowed to send it, returning the
+// error to send back to the client if not
+func validateRelay(relayMsg *relayv1.Relay, isHost bool, permitted bool) *transportv1.Error {
+	switch relayMsg.Type {
+	case relayv1.Relay_BROADCAST, relayv1.Relay_EXCEPT:
+		if !permitted {
+			return &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Client's role is not permitted to broadcast",
+				Reason:  transportv1.Error_NOT_PERMITTED,
+			}
+		}
+	case relayv1.Relay_MULTICAST:
+		if !permitted {
+			return &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Client's role is not permitted to send multicast messages",
+				Reason:  transportv1.Error_NOT_PERMITTED,
+			}
+		}
+
+		if len(relayMsg.Targets) == 0 {
+			return &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Must provide at least one target ID to send a message to",
+				Reason:  transportv1.Error_INVALID_REQUEST,
+			}
+		}
+	case relayv1.Relay_TARGET:
+		if !permitted {
+			return &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Client's role is not permitted to send targeted messages",
+				Reason:  transportv1.Error_NOT_PERMITTED,
+			}
+		}
+
+		if relayMsg.Target == nil {
+			return &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Must provide a target ID to send a message to",
+				Reason:  transportv1.Error_INVALID_REQUEST,
+			}
+		}
+	case relayv1.Relay_HOST:
+		if isHost {
+			return &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: "Hosts cannot send messages to themselves",
+				Reason:  transportv1.Error_INVALID_TARGET,
+			}
+		}
+	}
+	return nil
+}
+
 // GrantHost handles a client transferring the room's host powers to another client
 func (p *StandardProtocol) GrantHost(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
 	if connected == nil || room == nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Must be connected to a room to grant another client host",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
 		})
 		return
 	}
@@ -403,34 +694,23 @@ func (p *StandardProtocol) GrantHost(payload *transportv1.Payload, connected *se
 	grantHostRequest := &roomspecv1.GrantHostRequest{}
 	err := proto.Unmarshal(payload.Data, grantHostRequest)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("Invalid grant host request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
 		})
 		return
 	}
 
-	isHost, err := room.IsHost(connected.Client)
-	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusInternalServerError,
-			Message: fmt.Sprintf("Failed to determine if client is host, %v", err),
-		})
-		return
-	}
-
-	if !isHost {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusBadRequest,
-			Message: "Must be host to grant host to another host",
-		})
+	if !p.checkPermission(payload, connected, room, roomv1.PermissionGrantHost, "grant host to another client") {
 		return
 	}
 
 	if grantHostRequest.HostID == connected.Client.ID {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Cannot transfer host powers to yourself",
+			Reason:  transportv1.Error_INVALID_TARGET,
 		})
 		return
 	}
@@ -439,25 +719,47 @@ func (p *StandardProtocol) GrantHost(payload *transportv1.Payload, connected *se
 	if err != nil {
 		switch v := err.(type) {
 		case roomv1.ErrNoMatchingClient:
-			connected.Write <- Fail(&transportv1.Error{
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 				Code:    http.StatusBadRequest,
 				Message: v.Message,
+				Reason:  transportv1.Error_TARGET_NOT_FOUND,
 			})
 			return
 		default:
-			connected.Write <- Fail(&transportv1.Error{
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 				Code:    http.StatusInternalServerError,
 				Message: fmt.Sprintf("Failed to get client with ID %d, %v", grantHostRequest.HostID, err),
+				Reason:  transportv1.Error_INTERNAL,
 			})
 			return
 		}
 	}
 
-	err = p.changeHost(room, host)
+	hostRole, err := room.GetRole(grantHostRequest.HostID)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	if hostRole == roomv1.RoleSpectator {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot transfer host powers to a spectator",
+			Reason:  transportv1.Error_INVALID_TARGET,
+		})
+		return
+	}
+
+	err = p.changeHost(room, host, connected, payload.RequestID)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusInternalServerError,
 			Message: fmt.Sprintf("Failed to change host, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
 		})
 		return
 	}
@@ -468,9 +770,10 @@ func (p *StandardProtocol) GrantHost(payload *transportv1.Payload, connected *se
 // Kick handles a client removing another client from the room
 func (p *StandardProtocol) Kick(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
 	if connected == nil || room == nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: "Must be connected to a room to kick a client",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
 		})
 		return
 	}
@@ -478,145 +781,1706 @@ func (p *StandardProtocol) Kick(payload *transportv1.Payload, connected *session
 	kickRequest := &roomspecv1.KickRequest{}
 	err := proto.Unmarshal(payload.Data, kickRequest)
 	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
 			Code:    http.StatusBadRequest,
 			Message: fmt.Sprintf("Invalid kick request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
 		})
 		return
 	}
 
-	isHost, err := room.IsHost(connected.Client)
-	if err != nil {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusInternalServerError,
-			Message: fmt.Sprintf("Failed to determine if client is host, %v", err),
+	if !p.checkPermission(payload, connected, room, roomv1.PermissionKick, "kick") {
+		return
+	}
+
+	if kickRequest.ClientID == connected.Client.ID {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot kick yourself",
+			Reason:  transportv1.Error_INVALID_TARGET,
+		})
+		return
+	}
+
+	kickedClient, err := room.GetClient(kickRequest.ClientID)
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrNoMatchingClient:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  transportv1.Error_TARGET_NOT_FOUND,
+			})
+			return
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to kick client with ID %d, %v", kickRequest.ClientID, err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return
+		}
+	}
+
+	kickerRole, err := room.GetRole(connected.Client.ID)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	kickedRole, err := room.GetRole(kickRequest.ClientID)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	if kickedRole == roomv1.RoleHost {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot kick the room's host",
+			Reason:  transportv1.Error_INVALID_TARGET,
+		})
+		return
+	}
+
+	if !kickerRole.Outranks(kickedRole) {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Cannot kick a client with the %s role, only clients with a lower role", kickedRole),
+			Reason:  transportv1.Error_NOT_PERMITTED,
+		})
+		return
+	}
+
+	p.leave(kickedClient, room)
+
+	kickData, err := proto.Marshal(&roomspecv1.KickResponse{
+		ClientID: kickRequest.ClientID,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_KICK,
+		Data: kickData,
+	})
+	return
+}
+
+// GrantRole handles a client changing the role of another client in the room, every client in the room is told about
+// the change
+func (p *StandardProtocol) GrantRole(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if connected == nil || room == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Must be connected to a room to grant another client a role",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
+		})
+		return
+	}
+
+	grantRoleRequest := &roomspecv1.GrantRoleRequest{}
+	err := proto.Unmarshal(payload.Data, grantRoleRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid grant role request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	if !p.checkPermission(payload, connected, room, roomv1.PermissionGrantRole, "grant roles") {
+		return
+	}
+
+	targetRole, err := room.GetRole(grantRoleRequest.ClientID)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	if targetRole == roomv1.RoleHost {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot change the role of the room's host, grant host to another client instead",
+			Reason:  transportv1.Error_INVALID_TARGET,
+		})
+		return
+	}
+
+	if targetRole == roomv1.RoleSpectator {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot change the role of a spectator",
+			Reason:  transportv1.Error_INVALID_TARGET,
+		})
+		return
+	}
+
+	err = room.SetRole(grantRoleRequest.ClientID, roomv1.Role(grantRoleRequest.Role))
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrInvalidRole:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  transportv1.Error_INVALID_REQUEST,
+			})
+			return
+		case roomv1.ErrNoMatchingClient:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  transportv1.Error_TARGET_NOT_FOUND,
+			})
+			return
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to grant role to client with ID %d, %v", grantRoleRequest.ClientID, err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return
+		}
+	}
+
+	roleData, err := proto.Marshal(&clientv1.SanitisedClient{
+		ID:   grantRoleRequest.ClientID,
+		Role: grantRoleRequest.Role,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	p.notifyRoom(transportv1.Payload_RESPONSE_ROLE_CHANGE, roleData, connected, room, payload.RequestID)
+}
+
+// UpdateMetadata handles a client replacing its own metadata, every client in the room is told about the change
+func (p *StandardProtocol) UpdateMetadata(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if connected == nil || room == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Must be connected to a room to update metadata",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
+		})
+		return
+	}
+
+	updateMetadataRequest := &roomspecv1.UpdateMetadataRequest{}
+	err := proto.Unmarshal(payload.Data, updateMetadataRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid update metadata request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	err = room.SetMetadata(connected.Client.ID, updateMetadataRequest.Metadata)
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrInvalidMetadata:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  transportv1.Error_INVALID_REQUEST,
+			})
+			return
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to update client's metadata, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return
+		}
+	}
+
+	sanitised, err := p.sanitise(connected.Client, room)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's role, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	metadataData, err := proto.Marshal(sanitised)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	p.notifyRoom(transportv1.Payload_RESPONSE_METADATA_CHANGE, metadataData, connected, room, payload.RequestID)
+}
+
+// GetProperties handles a client requesting the room's properties, either every property or only the keys requested
+func (p *StandardProtocol) GetProperties(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if connected == nil || room == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Must be connected to a room to get the room's properties",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
+		})
+		return
+	}
+
+	getPropertiesRequest := &roomspecv1.GetPropertiesRequest{}
+	err := proto.Unmarshal(payload.Data, getPropertiesRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid get properties request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	properties, err := room.GetProperties()
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to retrieve room's properties, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	list := make([]*roomspecv1.Property, 0, len(properties))
+	if len(getPropertiesRequest.Keys) == 0 {
+		for key, property := range properties {
+			list = append(list, propertySpec(key, property))
+		}
+	} else {
+		// Keys that do not exist are left out of the list
+		for _, key := range getPropertiesRequest.Keys {
+			property, exists := properties[key]
+			if exists {
+				list = append(list, propertySpec(key, property))
+			}
+		}
+	}
+
+	responseData, err := proto.Marshal(&roomspecv1.PropertyList{
+		Properties: list,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_PROPERTIES,
+		Data: responseData,
+	})
+}
+
+// SetProperty handles a client setting one of the room's properties, every client in the room is told about the
+// change
+func (p *StandardProtocol) SetProperty(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if connected == nil || room == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Must be connected to a room to set the room's properties",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
+		})
+		return
+	}
+
+	setPropertyRequest := &roomspecv1.SetPropertyRequest{}
+	err := proto.Unmarshal(payload.Data, setPropertyRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid set property request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	if !p.checkPermission(payload, connected, room, roomv1.PermissionSetProperties, "set the room's properties") {
+		return
+	}
+
+	property, err := room.SetProperty(setPropertyRequest.Key, setPropertyRequest.Value, setPropertyRequest.ExpectedVersion)
+	if err != nil {
+		p.failProperty(payload, connected, setPropertyRequest.Key, err)
+		return
+	}
+
+	p.sendPropertyChange(payload, connected, room, propertySpec(setPropertyRequest.Key, property), false)
+}
+
+// DeleteProperty handles a client deleting one of the room's properties, every client in the room is told about the
+// change
+func (p *StandardProtocol) DeleteProperty(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if connected == nil || room == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Must be connected to a room to delete the room's properties",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
+		})
+		return
+	}
+
+	deletePropertyRequest := &roomspecv1.DeletePropertyRequest{}
+	err := proto.Unmarshal(payload.Data, deletePropertyRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid delete property request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	if !p.checkPermission(payload, connected, room, roomv1.PermissionSetProperties, "delete the room's properties") {
+		return
+	}
+
+	version, err := room.DeleteProperty(deletePropertyRequest.Key, deletePropertyRequest.ExpectedVersion)
+	if err != nil {
+		p.failProperty(payload, connected, deletePropertyRequest.Key, err)
+		return
+	}
+
+	p.sendPropertyChange(payload, connected, room, &roomspecv1.Property{
+		Key:     deletePropertyRequest.Key,
+		Version: version,
+	}, true)
+}
+
+// BrowseRooms handles a client requesting a page of the public rooms that can be joined, clients do not need to be
+// connected to a room to browse rooms
+func (p *StandardProtocol) BrowseRooms(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	browseRequest := &roomspecv1.BrowseRoomsRequest{}
+	err := proto.Unmarshal(payload.Data, browseRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid browse rooms request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	rooms, err := p.RoomManager.ListRooms()
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to retrieve room list, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return
+	}
+
+	publicRooms, err := roomv1.ListPublicRooms(rooms, roomv1.PublicRoomFilter{
+		Name:       browseRequest.Name,
+		Tags:       browseRequest.Tags,
+		Properties: browseRequest.Properties,
+		Offset:     browseRequest.Offset,
+		Limit:      browseRequest.Limit,
+	})
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrInvalidFilter:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  transportv1.Error_INVALID_REQUEST,
+			})
+			return
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to list public rooms, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return
+		}
+	}
+
+	list := &roomspecv1.PublicRoomList{
+		Rooms:  make([]*roomspecv1.PublicRoom, 0, len(publicRooms.Rooms)),
+		Total:  publicRooms.Total,
+		Offset: publicRooms.Offset,
+	}
+	for _, publicRoom := range publicRooms.Rooms {
+		list.Rooms = append(list.Rooms, &roomspecv1.PublicRoom{
+			ID:                publicRoom.ID,
+			Name:              publicRoom.Name,
+			Tags:              publicRoom.Tags,
+			MaxClients:        publicRoom.MaxClients,
+			CurrentClients:    publicRoom.CurrentClients,
+			MaxSpectators:     publicRoom.MaxSpectators,
+			CurrentSpectators: publicRoom.CurrentSpectators,
+			Properties:        publicRoom.Properties,
+		})
+	}
+
+	responseData, err := proto.Marshal(list)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_PUBLIC_ROOMS,
+		Data: responseData,
+	})
+}
+
+// Matchmake handles a client joining a matchmaking queue, once enough compatible clients are waiting a room is created
+// for them and they are all connected to it. Clients do not need to be connected to a room to matchmake, but cannot
+// matchmake while connected to one
+func (p *StandardProtocol) Matchmake(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if p.Matchmaker == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Matchmaking is not enabled on this server",
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	if room != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot matchmake while already connected to a room",
+			Reason:  transportv1.Error_ALREADY_IN_ROOM,
+		})
+		return
+	}
+
+	matchmakeRequest := &roomspecv1.MatchmakeRequest{}
+	err := proto.Unmarshal(payload.Data, matchmakeRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid matchmake request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	err = ValidateMatchmake(matchmakeRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.(ErrInvalidMatchmake).Message,
+			Reason:  Reason(err),
+		})
+		return
+	}
+
+	ticket := &MatchmakeTicket{
+		Session:    connected,
+		RequestID:  payload.RequestID,
+		Queue:      matchmakeRequest.Queue,
+		RoomSize:   matchmakeRequest.RoomSize,
+		Attributes: matchmakeRequest.Attributes,
+		Filters:    matchmakeRequest.Filters,
+		Expires:    time.Now().Add(matchmakeTimeout(matchmakeRequest.TimeoutSeconds)),
+	}
+
+	if !p.Matchmaker.Enqueue(ticket) {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Already waiting in a matchmaking queue",
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	p.sendMatchmakeStatus(payload.RequestID, ticket, &roomspecv1.MatchmakeStatus{
+		Status:  roomspecv1.MatchmakeStatus_QUEUED,
+		Waiting: p.Matchmaker.Waiting(ticket.Queue)[ticket],
+	})
+
+	if p.matchQueue(ticket.Queue) {
+		p.sendQueueStatus(ticket.Queue, nil)
+		return
+	}
+
+	// Only the clients already waiting need to know that another client has joined the queue
+	p.sendQueueStatus(ticket.Queue, connected)
+}
+
+// CancelMatchmake handles a client leaving the matchmaking queue it is waiting in
+func (p *StandardProtocol) CancelMatchmake(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	var ticket *MatchmakeTicket
+	if p.Matchmaker != nil {
+		ticket = p.Matchmaker.Cancel(connected)
+	}
+
+	if ticket == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Not waiting in a matchmaking queue",
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	p.sendMatchmakeStatus(payload.RequestID, ticket, &roomspecv1.MatchmakeStatus{
+		Status: roomspecv1.MatchmakeStatus_CANCELLED,
+	})
+
+	p.sendQueueStatus(ticket.Queue, nil)
+}
+
+// QuickJoin handles a client joining the fullest public room matching its filter that still has a free slot, if no
+// room matches and the protocol has a quick join template a new public room is created for the client
+func (p *StandardProtocol) QuickJoin(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) (*sessionv1.Session, roomv1.Room) {
+	if room != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Cannot connect to a different room while already connected to another",
+			Reason:  transportv1.Error_ALREADY_IN_ROOM,
+		})
+		return connected, room
+	}
+
+	p.cancelMatchmaking(connected)
+
+	quickJoinRequest := &roomspecv1.QuickJoinRequest{}
+	err := proto.Unmarshal(payload.Data, quickJoinRequest)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid quick join request provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return connected, room
+	}
+
+	err = roomv1.ValidateMetadata(quickJoinRequest.Metadata)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.(roomv1.ErrInvalidMetadata).Message,
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return connected, room
+	}
+
+	joiner, ok := p.RoomManager.(roomv1.QuickJoiner)
+	if !ok {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Quick join is not supported by this server",
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return connected, room
+	}
+
+	var options *roomv1.Options
+	if p.Templates != nil && p.QuickJoinTemplate != "" {
+		// If the template has been deleted no room is created for the client, only existing rooms are joined
+		templateOptions, err := p.Templates.Options(p.QuickJoinTemplate, nil)
+		if err == nil {
+			// Rooms created for the client are named and tagged so that later clients with the same filter match them
+			templateOptions.Name = quickJoinRequest.Name
+			templateOptions.Tags = quickJoinRequest.Tags
+			options = &templateOptions
+		}
+	}
+
+	connected, joined, created, err := joiner.QuickJoin(connected, roomv1.PublicRoomFilter{
+		Name:       quickJoinRequest.Name,
+		Tags:       quickJoinRequest.Tags,
+		Properties: quickJoinRequest.Properties,
+	}, options)
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrNoRoomFound:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, room
+		case roomv1.ErrInvalidListing:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  transportv1.Error_INVALID_REQUEST,
+			})
+			return connected, room
+		case roomv1.ErrRequestTooManyClients:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusServiceUnavailable,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, room
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to quick join a room, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, room
+		}
+	}
+
+	info, err := joined.GetInfo()
+	if err != nil {
+		glog.Errorf("Failed to retrieve quick joined room's info, %v", err)
+		return connected, joined
+	}
+
+	responseData, err := proto.Marshal(&roomspecv1.QuickJoinResponse{
+		RoomID:     info.ID,
+		RoomSecret: info.Secret,
+		Created:    created,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_QUICK_JOIN,
+		Data: responseData,
+	})
+
+	if len(quickJoinRequest.Metadata) > 0 {
+		err = joined.SetMetadata(connected.Client.ID, quickJoinRequest.Metadata)
+		if err != nil {
+			glog.Errorf("Failed to set client's metadata, %v", err)
+		}
+	}
+
+	p.welcome(nil, connected, joined, info.ID)
+
+	return connected, joined
+}
+
+// ProcessMatchmaking times out any clients that have waited in a matchmaking queue past their timeout as of the time
+// provided, and retries matching the clients still waiting, such as when rooms could not previously be created
+// because the server was at capacity
+func (p *StandardProtocol) ProcessMatchmaking(now time.Time) {
+	if p.Matchmaker == nil {
+		return
+	}
+
+	changed := make(map[string]bool)
+	for _, ticket := range p.Matchmaker.Expire(now) {
+		p.sendMatchmakeStatus(nil, ticket, &roomspecv1.MatchmakeStatus{
+			Status: roomspecv1.MatchmakeStatus_TIMED_OUT,
+		})
+		changed[ticket.Queue] = true
+	}
+
+	for _, queue := range p.Matchmaker.Queues() {
+		if p.matchQueue(queue) {
+			changed[queue] = true
+		}
+	}
+
+	for queue := range changed {
+		p.sendQueueStatus(queue, nil)
+	}
+}
+
+// Ack handles a client acknowledging the relayed messages it has received, sending any queued messages that now fit
+// in the client's window
+func (p *StandardProtocol) Ack(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room) {
+	if connected == nil || room == nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "Must be connected to a room to acknowledge messages",
+			Reason:  transportv1.Error_NOT_IN_ROOM,
+		})
+		return
+	}
+
+	if !p.tracked(connected, room) {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Must opt into the '%s' capability to acknowledge messages", CapabilityReliable),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	ack := &relayv1.Ack{}
+	err := proto.Unmarshal(payload.Data, ack)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid ack provided, does not conform to spec, %v", err),
+			Reason:  transportv1.Error_INVALID_REQUEST,
+		})
+		return
+	}
+
+	p.Reliable.Ack(p.roomID(room), connected.Client.ID, ack.Sequence, func(messages []ReliableMessage) {
+		p.writeReliable(connected, messages)
+	})
+}
+
+// CloseRoom handles a room being closed and all clients disconnecting
+func (p *StandardProtocol) CloseRoom(roomID int32) error {
+	retrievedRoom, err := p.RoomManager.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	retrievedRoom.SetStatus(roomv1.StatusClosing)
+
+	connectedClientList, err := retrievedRoom.GetConnected()
+	if err != nil {
+		return err
+	}
+
+	for _, connectedClient := range connectedClientList {
+		p.Disconnect(connectedClient, retrievedRoom)
+	}
+
+	if p.Reliable != nil {
+		p.Reliable.ForgetRoom(roomID)
+	}
+
+	return p.RoomManager.DeleteRoom(roomID)
+}
+
+// ExpireClients forgets any disconnected clients in a room whose reconnect grace period has passed as of the time
+// provided, notifying the host that they have left
+func (p *StandardProtocol) ExpireClients(roomID int32, now time.Time) error {
+	retrievedRoom, err := p.RoomManager.GetRoom(roomID)
+	if err != nil {
+		return err
+	}
+
+	if retrievedRoom.GetStatus() == roomv1.StatusClosing {
+		return nil
+	}
+
+	return p.pruneDisconnected(retrievedRoom, now)
+}
+
+// CreateRoom creates a new room
+func (p *StandardProtocol) CreateRoom(options roomv1.Options) (roomv1.Room, error) {
+	return p.RoomManager.CreateRoom(options)
+}
+
+// GetRoom returns any matching room, if no room is found an error is returned
+func (p *StandardProtocol) GetRoom(roomID int32) (roomv1.Room, error) {
+	return p.RoomManager.GetRoom(roomID)
+}
+
+// Summary returns a summary of all rooms
+func (p *StandardProtocol) Summary() (*api.RoomsSummary, error) {
+	return p.RoomManager.Summary()
+}
+
+// ListRooms returns a list of all rooms
+func (p *StandardProtocol) ListRooms() ([]roomv1.Room, error) {
+	return p.RoomManager.ListRooms()
+}
+
+// RotateInviteCode gives a room a new invite code, if the room manager does not support invite codes an error is
+// returned
+func (p *StandardProtocol) RotateInviteCode(roomID int32) (string, error) {
+	rotator, ok := p.RoomManager.(roomv1.InviteCodeRotator)
+	if !ok {
+		return "", fmt.Errorf("room manager does not support rotating invite codes")
+	}
+	return rotator.RotateInviteCode(roomID)
+}
+
+// joinByInviteCode connects a client to the room with the invite code in the join request, checking the client has
+// provided the room's password if it has one. A connection that has given too many wrong invite codes or passwords
+// can no longer join rooms without their secret
+func (p *StandardProtocol) joinByInviteCode(payload *transportv1.Payload, joinRequest *roomspecv1.JoinRoomRequest, connected *sessionv1.Session, currentRoom roomv1.Room, rooms []roomv1.Room) (*sessionv1.Session, roomv1.Room) {
+	if p.tooManyPasswordFailures(payload, connected) {
+		return connected, currentRoom
+	}
+
+	matchRoom, err := roomv1.FindByInviteCode(rooms, joinRequest.InviteCode)
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrNoRoomFound:
+			connected.PasswordFailures++
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, currentRoom
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to find room by invite code, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, currentRoom
+		}
+	}
+
+	info, err := matchRoom.GetInfo()
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to retrieve room info, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return connected, currentRoom
+	}
+
+	return p.joinWithPassword(payload, joinRequest, connected, currentRoom, matchRoom, info)
+}
+
+// tooManyPasswordFailures determines if a client has given too many wrong invite codes or passwords on its connection
+// to be allowed to try again, telling the client if so
+func (p *StandardProtocol) tooManyPasswordFailures(payload *transportv1.Payload, connected *sessionv1.Session) bool {
+	if connected.PasswordFailures < roomv1.MaxSessionPasswordFailures {
+		return false
+	}
+
+	connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+		Code:    http.StatusTooManyRequests,
+		Message: "Too many wrong invite codes or passwords given, cannot join rooms without their secret",
+		Reason:  transportv1.Error_RATE_LIMITED,
+	})
+	return true
+}
+
+// joinWithPassword connects a client to a room it has asked to join without the room's secret, checking the client
+// has provided the room's password if it has one
+func (p *StandardProtocol) joinWithPassword(payload *transportv1.Payload, joinRequest *roomspecv1.JoinRoomRequest, connected *sessionv1.Session, currentRoom roomv1.Room, matchRoom roomv1.Room, info *api.RoomInfo) (*sessionv1.Session, roomv1.Room) {
+	matches, err := matchRoom.CheckPassword(joinRequest.Password)
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrRoomOnOtherNode:
+			p.redirect(payload, connected, info.ID, v)
+			return connected, currentRoom
+		case roomv1.ErrTooManyPasswordFailures:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusTooManyRequests,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, currentRoom
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to check room's password, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, currentRoom
+		}
+	}
+
+	if !matches {
+		connected.PasswordFailures++
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusForbidden,
+			Message: "Incorrect password provided for the room",
+			Reason:  transportv1.Error_WRONG_PASSWORD,
+		})
+		return connected, currentRoom
+	}
+
+	return p.join(payload, joinRequest, connected, currentRoom, matchRoom, info.ID)
+}
+
+// join connects a client to a room it has asked to join, as either a client or a spectator, setting the client's
+// metadata and welcoming it to the room
+func (p *StandardProtocol) join(payload *transportv1.Payload, joinRequest *roomspecv1.JoinRoomRequest, connected *sessionv1.Session, currentRoom roomv1.Room, matchRoom roomv1.Room, roomID int32) (*sessionv1.Session, roomv1.Room) {
+	var err error
+	if joinRequest.Spectator {
+		connected, err = matchRoom.NewSpectator(connected)
+	} else {
+		connected, err = matchRoom.NewClient(connected)
+	}
+	if err != nil {
+		switch v := err.(type) {
+		case roomv1.ErrRoomFull:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: v.Message,
+				Reason:  Reason(err),
+			})
+			return connected, currentRoom
+		case roomv1.ErrRoomOnOtherNode:
+			p.redirect(payload, connected, roomID, v)
+			return connected, currentRoom
+		default:
+			connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+				Code:    http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to register new client to room, %v", err),
+				Reason:  transportv1.Error_INTERNAL,
+			})
+			return connected, currentRoom
+		}
+	}
+
+	if len(joinRequest.Metadata) > 0 {
+		err = matchRoom.SetMetadata(connected.Client.ID, joinRequest.Metadata)
+		if err != nil {
+			glog.Errorf("Failed to set client's metadata, %v", err)
+		}
+	}
+
+	p.welcome(payload.RequestID, connected, matchRoom, roomID)
+
+	return connected, matchRoom
+}
+
+// welcome tells a client that has newly joined a room its ID and secret, starting reliable delivery if the client
+// opted into it, making the client host if the room has none and telling the host about the new client
+func (p *StandardProtocol) welcome(requestID *uint32, connected *sessionv1.Session, room roomv1.Room, roomID int32) {
+	responseClient := &clientv1.Client{
+		ID:     connected.Client.ID,
+		Secret: connected.Client.Secret,
+	}
+
+	responseData, err := proto.Marshal(responseClient)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- SucceedRequest(requestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_CONNECT,
+		Data: responseData,
+	})
+
+	if p.Reliable != nil && connected.HasCapability(CapabilityReliable) {
+		p.Reliable.Join(roomID, connected.Client.ID)
+	}
+
+	p.setHostIfNone(connected, room)
+
+	p.sendClientConnectToHost(connected, room)
+}
+
+// matchQueue places each group of compatible clients waiting in a matchmaking queue into a new room, returning if
+// any clients left the queue. If a room cannot be created the group is returned to the front of the queue to be
+// retried later
+func (p *StandardProtocol) matchQueue(queue string) bool {
+	groups := p.Matchmaker.Match(queue)
+	for i, group := range groups {
+		err := p.startMatch(group)
+		if err != nil {
+			glog.V(1).Infof("Failed to create room for matchmaking queue %s, %v", queue, err)
+			// Keep the groups in queue order so the longest waiting clients are still matched first
+			for j := len(groups) - 1; j >= i; j-- {
+				p.Matchmaker.Requeue(groups[j])
+			}
+			return i > 0
+		}
+	}
+	return len(groups) > 0
+}
+
+// startMatch creates a room for a group of matched clients and assigns each of them to it, waking each client's
+// session to join the room, as only a client's own session changes the room it is in
+func (p *StandardProtocol) startMatch(group []*MatchmakeTicket) error {
+	room, err := p.RoomManager.CreateRoom(roomv1.Options{
+		MaxClients:  group[0].RoomSize,
+		IdleTimeout: MatchedRoomIdleTimeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	info, err := room.GetInfo()
+	if err != nil {
+		return err
+	}
+
+	for _, ticket := range group {
+		p.Matchmaker.Assign(ticket, info.ID)
+		ticket.Session.NotifyMatched()
+	}
+
+	return nil
+}
+
+// JoinMatch handles a client joining the room it has been matched into, if the client has cancelled matchmaking
+// since it was matched nothing happens. This runs on the client's own session so it cannot race the client's requests
+func (p *StandardProtocol) JoinMatch(connected *sessionv1.Session, currentRoom roomv1.Room) (*sessionv1.Session, roomv1.Room) {
+	if p.Matchmaker == nil {
+		return connected, currentRoom
+	}
+
+	ticket := p.Matchmaker.Claim(connected)
+	if ticket == nil {
+		return connected, currentRoom
+	}
+
+	if currentRoom != nil {
+		p.sendMatchmakeStatus(nil, ticket, &roomspecv1.MatchmakeStatus{
+			Status: roomspecv1.MatchmakeStatus_CANCELLED,
+		})
+		return connected, currentRoom
+	}
+
+	room, err := p.RoomManager.GetRoom(ticket.RoomID)
+	var info *api.RoomInfo
+	if err == nil {
+		info, err = room.GetInfo()
+	}
+	var joined *sessionv1.Session
+	if err == nil {
+		joined, err = room.NewClient(connected)
+	}
+	if err != nil {
+		glog.Errorf("Failed to connect matched client to room with ID %d, %v", ticket.RoomID, err)
+		p.sendMatchmakeStatus(nil, ticket, &roomspecv1.MatchmakeStatus{
+			Status: roomspecv1.MatchmakeStatus_CANCELLED,
+		})
+		return connected, currentRoom
+	}
+
+	p.sendMatchmakeStatus(nil, ticket, &roomspecv1.MatchmakeStatus{
+		Status:     roomspecv1.MatchmakeStatus_MATCHED,
+		RoomID:     info.ID,
+		RoomSecret: info.Secret,
+	})
+
+	p.welcome(nil, joined, room, info.ID)
+
+	return joined, room
+}
+
+// cancelMatchmaking removes a client from the matchmaking queue it is waiting in, if any, such as when the client
+// connects to a room directly or disconnects
+func (p *StandardProtocol) cancelMatchmaking(connected *sessionv1.Session) {
+	if p.Matchmaker == nil {
+		return
+	}
+
+	ticket := p.Matchmaker.Cancel(connected)
+	if ticket == nil {
+		return
+	}
+
+	p.sendMatchmakeStatus(nil, ticket, &roomspecv1.MatchmakeStatus{
+		Status: roomspecv1.MatchmakeStatus_CANCELLED,
+	})
+
+	p.sendQueueStatus(ticket.Queue, nil)
+}
+
+// sendMatchmakeStatus tells a client about the status of its matchmaking, nothing is sent if the client's session has
+// closed, and the status is dropped if the session does not accept it in time
+func (p *StandardProtocol) sendMatchmakeStatus(requestID *uint32, ticket *MatchmakeTicket, status *roomspecv1.MatchmakeStatus) {
+	if ticket.Session.Closed {
+		return
+	}
+
+	status.Queue = ticket.Queue
+	status.RoomSize = ticket.RoomSize
+
+	statusData, err := proto.Marshal(status)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	select {
+	case ticket.Session.Write <- SucceedRequest(requestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_MATCHMAKE_STATUS,
+		Data: statusData,
+	}):
+	case <-ticket.Session.CloseSignal:
+	case <-time.After(matchmakeStatusTimeout):
+		glog.Warningf("Dropped matchmaking status for a client in queue %s, the client's session is not accepting messages",
+			ticket.Queue)
+	}
+}
+
+// sendQueueStatus tells every client waiting in a matchmaking queue how many compatible clients are waiting with
+// them, except for the client provided
+func (p *StandardProtocol) sendQueueStatus(queue string, except *sessionv1.Session) {
+	for ticket, waiting := range p.Matchmaker.Waiting(queue) {
+		if ticket.Session == except {
+			continue
+		}
+		p.sendMatchmakeStatus(nil, ticket, &roomspecv1.MatchmakeStatus{
+			Status:  roomspecv1.MatchmakeStatus_QUEUED,
+			Waiting: waiting,
+		})
+	}
+}
+
+func (p *StandardProtocol) setHostIfNone(connected *sessionv1.Session, room roomv1.Room) {
+	role, err := room.GetRole(connected.Client.ID)
+	if err != nil {
+		glog.Errorf("Failed to determine client's role, %v", err)
+		return
+	}
+
+	if role == roomv1.RoleSpectator {
+		// Spectators can never be host
+		return
+	}
+
+	host, err := room.GetHost()
+	if err != nil {
+		glog.Errorf("Failed to retrieve the current host, %v", err)
+		return
+	}
+
+	if host == nil {
+		// No connected host, either the room has no host or its host has not reconnected since the room was restored,
+		// so the connecting client takes over as host (or takes back its host powers when it was the host)
+		_, err := room.SetHost(&connected.Client.ID)
+		if err != nil {
+			glog.Errorf("Failed to update host, %v", err)
+			return
+		}
+
+		connected.Write <- Succeed(&transportv1.Payload{
+			Flag: transportv1.Payload_RESPONSE_ASSIGN_HOST,
+		})
+		return
+	}
+
+	if host.Client.ID == connected.Client.ID {
+		// Client was already the room's host (e.g. restored after a restart), make sure they know they are host
+		connected.Write <- Succeed(&transportv1.Payload{
+			Flag: transportv1.Payload_RESPONSE_ASSIGN_HOST,
 		})
+	}
+}
+
+// redirect tells a client to connect to a different relay server node, as the room they are joining is owned by that
+// node
+func (p *StandardProtocol) redirect(payload *transportv1.Payload, connected *sessionv1.Session, roomID int32, otherNode roomv1.ErrRoomOnOtherNode) {
+	responseData, err := proto.Marshal(&roomspecv1.RedirectResponse{
+		RoomID:  roomID,
+		Address: otherNode.Address,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- SucceedRequest(payload.RequestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_REDIRECT,
+		Data: responseData,
+	})
+}
+
+func (p *StandardProtocol) broadcast(data []byte, connected *sessionv1.Session, room roomv1.Room, connectedClientList []*sessionv1.Session, except []int32) {
+	excluded := make(map[int32]bool, len(except))
+	for _, id := range except {
+		excluded[id] = true
+	}
+
+	for _, connectedClient := range connectedClientList {
+		if connected.Client.ID == connectedClient.Client.ID {
+			// Message should only be sent to other clients, not sent back to origin
+			continue
+		}
+		if excluded[connectedClient.Client.ID] {
+			continue
+		}
+		p.relay(data, connectedClient, room)
+	}
+
+	disconnectedClientList, err := room.GetDisconnected()
+	if err != nil {
+		glog.Errorf("Failed to retrieve room's disconnected clients, %v", err)
+		return
+	}
+
+	for _, disconnectedClient := range disconnectedClientList {
+		if excluded[disconnectedClient.ID] {
+			continue
+		}
+		p.record(data, disconnectedClient.ID, room)
+	}
+}
+
+// record keeps a relayed message for a disconnected client so that it is delivered if the client reconnects, clients
+// that use reliable delivery have the message queued for retransmission, other clients have it recorded for replay
+func (p *StandardProtocol) record(data []byte, clientID int32, room roomv1.Room) {
+	if p.Reliable != nil && p.Reliable.Tracked(p.roomID(room), clientID) {
+		p.Reliable.Send(p.roomID(room), clientID, data, nil)
 		return
 	}
 
-	if !isHost {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusBadRequest,
-			Message: "Must be host to kick",
-		})
-		return
+	_, err := room.RecordMessage(clientID, data)
+	if err != nil {
+		glog.Errorf("Failed to record message for client with ID %d, %v", clientID, err)
+	}
+}
+
+// disconnectedHost returns the ID of the room's host if the host is disconnected, nil is returned if the room has no
+// host or the host is connected
+func (p *StandardProtocol) disconnectedHost(room roomv1.Room) (*int32, error) {
+	disconnectedClientList, err := room.GetDisconnected()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, disconnectedClient := range disconnectedClientList {
+		isHost, err := room.IsHost(disconnectedClient)
+		if err != nil {
+			return nil, err
+		}
+		if isHost {
+			return &disconnectedClient.ID, nil
+		}
 	}
 
-	if kickRequest.ClientID == connected.Client.ID {
-		connected.Write <- Fail(&transportv1.Error{
-			Code:    http.StatusBadRequest,
-			Message: "Cannot kick yourself",
-		})
-		return
+	return nil, nil
+}
+
+// isDisconnected determines if a client is a disconnected client remembered by the room
+func (p *StandardProtocol) isDisconnected(room roomv1.Room, clientID int32) (bool, error) {
+	disconnectedClientList, err := room.GetDisconnected()
+	if err != nil {
+		return false, err
 	}
 
-	kickedClient, err := room.GetClient(kickRequest.ClientID)
+	for _, disconnectedClient := range disconnectedClientList {
+		if disconnectedClient.ID == clientID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// multicast sends a message to each of the target clients, messages to disconnected targets are kept in case they
+// reconnect, any targets that the message could not be sent to are reported back to the sender
+func (p *StandardProtocol) multicast(requestID *uint32, data []byte, connected *sessionv1.Session, room roomv1.Room, connectedClientList []*sessionv1.Session, targets []int32) {
+	recipients := make(map[int32]*sessionv1.Session, len(connectedClientList))
+	for _, connectedClient := range connectedClientList {
+		recipients[connectedClient.Client.ID] = connectedClient
+	}
+
+	disconnectedClientList, err := room.GetDisconnected()
 	if err != nil {
-		switch v := err.(type) {
-		case roomv1.ErrNoMatchingClient:
-			connected.Write <- Fail(&transportv1.Error{
-				Code:    http.StatusBadRequest,
-				Message: v.Message,
+		glog.Errorf("Failed to retrieve room's disconnected clients, %v", err)
+	}
+	disconnected := make(map[int32]bool, len(disconnectedClientList))
+	for _, disconnectedClient := range disconnectedClientList {
+		disconnected[disconnectedClient.ID] = true
+	}
+
+	failures := []*relayv1.RelayFailure{}
+	seen := make(map[int32]bool, len(targets))
+	for _, target := range targets {
+		if seen[target] {
+			// Duplicate target, only send the message once
+			continue
+		}
+		seen[target] = true
+
+		if target == connected.Client.ID {
+			failures = append(failures, &relayv1.RelayFailure{
+				Target: target,
+				Error: &transportv1.Error{
+					Code:    http.StatusBadRequest,
+					Message: "Clients cannot send messages to themselves",
+					Reason:  transportv1.Error_INVALID_TARGET,
+				},
 			})
-			return
-		default:
-			connected.Write <- Fail(&transportv1.Error{
-				Code:    http.StatusInternalServerError,
-				Message: fmt.Sprintf("Failed to kick client with ID %d, %v", kickRequest.ClientID, err),
+			continue
+		}
+
+		if disconnected[target] {
+			p.record(data, target, room)
+			continue
+		}
+
+		recipient, exists := recipients[target]
+		if !exists {
+			failures = append(failures, &relayv1.RelayFailure{
+				Target: target,
+				Error: &transportv1.Error{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("No target client found with ID %d", target),
+					Reason:  transportv1.Error_TARGET_NOT_FOUND,
+				},
 			})
-			return
+			continue
 		}
+
+		p.relay(data, recipient, room)
 	}
 
-	p.Disconnect(kickedClient, room)
+	p.sendRelayFailures(requestID, connected, failures)
+}
 
-	kickData, err := proto.Marshal(&roomspecv1.KickResponse{
-		ClientID: kickRequest.ClientID,
+// unknownTargets returns a failure for each of the target IDs provided that do not belong to a client in the room,
+// connected or disconnected
+func (p *StandardProtocol) unknownTargets(connected *sessionv1.Session, room roomv1.Room, connectedClientList []*sessionv1.Session, targets []int32) []*relayv1.RelayFailure {
+	known := make(map[int32]bool, len(connectedClientList))
+	for _, connectedClient := range connectedClientList {
+		known[connectedClient.Client.ID] = true
+	}
+
+	disconnectedClientList, err := room.GetDisconnected()
+	if err != nil {
+		glog.Errorf("Failed to retrieve room's disconnected clients, %v", err)
+	}
+	for _, disconnectedClient := range disconnectedClientList {
+		known[disconnectedClient.ID] = true
+	}
+
+	failures := []*relayv1.RelayFailure{}
+	reported := make(map[int32]bool, len(targets))
+	for _, target := range targets {
+		if known[target] || reported[target] {
+			continue
+		}
+		reported[target] = true
+		failures = append(failures, &relayv1.RelayFailure{
+			Target: target,
+			Error: &transportv1.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("No target client found with ID %d", target),
+				Reason:  transportv1.Error_TARGET_NOT_FOUND,
+			},
+		})
+	}
+
+	return failures
+}
+
+// sendRelayFailures reports the targets that a relayed message could not be sent to back to the sender, nothing is
+// sent if there are no failures
+func (p *StandardProtocol) sendRelayFailures(requestID *uint32, connected *sessionv1.Session, failures []*relayv1.RelayFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	failuresData, err := proto.Marshal(&relayv1.RelayFailures{
+		Failures: failures,
 	})
 	if err != nil {
 		// Should not occur, panic
 		panic(err)
 	}
 
-	connected.Write <- Succeed(&transportv1.Payload{
-		Flag: transportv1.Payload_RESPONSE_KICK,
-		Data: kickData,
+	connected.Write <- SucceedRequest(requestID, &transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_RELAY_FAILURES,
+		Data: failuresData,
 	})
-	return
 }
 
-// CloseRoom handles a room being closed and all clients disconnecting
-func (p *StandardProtocol) CloseRoom(roomID int32) error {
-	retrievedRoom, err := p.RoomManager.GetRoom(roomID)
+// relay sends a relayed message to a client, recording it so that it can be replayed if the client reconnects. The
+// data is the relayed message stamped with the sender's identity, which is unwrapped for clients using older protocol
+// versions
+func (p *StandardProtocol) relay(data []byte, recipient *sessionv1.Session, room roomv1.Room) {
+	if p.tracked(recipient, room) {
+		p.Reliable.Send(p.roomID(room), recipient.Client.ID, data, func(messages []ReliableMessage) {
+			p.writeReliable(recipient, messages)
+		})
+		return
+	}
+
+	sequence, err := room.RecordMessage(recipient.Client.ID, data)
 	if err != nil {
-		return err
+		glog.Errorf("Failed to record message for client with ID %d, %v", recipient.Client.ID, err)
 	}
 
-	retrievedRoom.SetStatus(roomv1.StatusClosing)
+	recipient.Write <- Succeed(relayedPayload(recipient, data, sequence))
+}
 
-	connectedClientList, err := retrievedRoom.GetConnected()
+// relayedPayload builds the payload for a relayed message stamped with the sender's identity, clients using protocol
+// version 3 or later receive the stamped message, while clients using older versions receive the original relay
+// message the sender sent
+func relayedPayload(recipient *sessionv1.Session, data []byte, sequence *uint64) *transportv1.Payload {
+	if recipient.ProtocolVersion >= Version3 {
+		return &transportv1.Payload{
+			Flag:     transportv1.Payload_RESPONSE_RELAYED_MESSAGE,
+			Data:     data,
+			Sequence: sequence,
+		}
+	}
+
+	relayed := &relayv1.RelayedMessage{}
+	err := proto.Unmarshal(data, relayed)
 	if err != nil {
-		return err
+		// Should not occur, panic
+		panic(err)
 	}
 
-	for _, connectedClient := range connectedClientList {
-		p.Disconnect(connectedClient, retrievedRoom)
+	relayData, err := proto.Marshal(relayed.Relay)
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
 	}
 
-	return p.RoomManager.DeleteRoom(roomID)
+	return &transportv1.Payload{
+		Flag:     transportv1.Payload_RESPONSE_RELAY_MESSAGE,
+		Data:     relayData,
+		Sequence: sequence,
+	}
 }
 
-// CreateRoom creates a new room
-func (p *StandardProtocol) CreateRoom(maxClients int32) (roomv1.Room, error) {
-	return p.RoomManager.CreateRoom(maxClients)
+// permitted determines if a client's role in a room permits it to do something
+func (p *StandardProtocol) permitted(connected *sessionv1.Session, room roomv1.Room, permission roomv1.Permission) (bool, error) {
+	role, err := room.GetRole(connected.Client.ID)
+	if err != nil {
+		return false, err
+	}
+	return room.GetPermissions().Allows(role, permission), nil
 }
 
-// GetRoom returns any matching room, if no room is found an error is returned
-func (p *StandardProtocol) GetRoom(roomID int32) (roomv1.Room, error) {
-	return p.RoomManager.GetRoom(roomID)
-}
+// checkPermission determines if a client's role in a room permits it to do something, sending the client an error if
+// not
+func (p *StandardProtocol) checkPermission(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room, permission roomv1.Permission, action string) bool {
+	permitted, err := p.permitted(connected, room, permission)
+	if err != nil {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to determine client's permissions, %v", err),
+			Reason:  transportv1.Error_INTERNAL,
+		})
+		return false
+	}
 
-// Summary returns a summary of all rooms
-func (p *StandardProtocol) Summary() (*api.RoomsSummary, error) {
-	return p.RoomManager.Summary()
+	if !permitted {
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Client's role is not permitted to %s", action),
+			Reason:  transportv1.Error_NOT_PERMITTED,
+		})
+		return false
+	}
+
+	return true
 }
 
-// ListRooms returns a list of all rooms
-func (p *StandardProtocol) ListRooms() ([]roomv1.Room, error) {
-	return p.RoomManager.ListRooms()
+// sanitise builds the details of a client that can be shared with other clients, including the client's role
+func (p *StandardProtocol) sanitise(client *clientv1.Client, room roomv1.Room) (*clientv1.SanitisedClient, error) {
+	role, err := room.GetRole(client.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := room.GetMetadata(client.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientv1.SanitisedClient{
+		ID:       client.ID,
+		Host:     role == roomv1.RoleHost,
+		Role:     clientv1.SanitisedClient_RoleType(role),
+		Metadata: metadata,
+	}, nil
 }
 
-func (p *StandardProtocol) setHostIfNone(connected *sessionv1.Session, room roomv1.Room) {
-	host, err := room.GetHost()
+// notifyRoom sends a notification to every client connected to the room, the requesting client's copy includes the
+// request ID
+func (p *StandardProtocol) notifyRoom(flag transportv1.Payload_FlagType, data []byte, requester *sessionv1.Session, room roomv1.Room, requestID *uint32) {
+	connectedClients, err := room.GetConnected()
 	if err != nil {
-		glog.Errorf("Failed to retrieve the current host, %v", err)
+		glog.Errorf("Failed to retrieve room's connected clients, %v", err)
 		return
 	}
 
-	if host == nil {
-		_, err := room.SetHost(&connected.Client.ID)
-		if err != nil {
-			glog.Errorf("Failed to update host, %v", err)
-			return
+	for _, connectedClient := range connectedClients {
+		notification := &transportv1.Payload{
+			Flag: flag,
+			Data: data,
+		}
+		if connectedClient.Client.ID == requester.Client.ID {
+			connectedClient.Write <- SucceedRequest(requestID, notification)
+			continue
 		}
+		connectedClient.Write <- Succeed(notification)
+	}
+}
 
-		connected.Write <- Succeed(&transportv1.Payload{
-			Flag: transportv1.Payload_RESPONSE_ASSIGN_HOST,
+// failProperty tells a client that changing one of the room's properties failed
+func (p *StandardProtocol) failProperty(payload *transportv1.Payload, connected *sessionv1.Session, key string, err error) {
+	switch v := err.(type) {
+	case roomv1.ErrInvalidProperty:
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusBadRequest,
+			Message: v.Message,
+			Reason:  Reason(err),
+		})
+	case roomv1.ErrPropertyConflict:
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusConflict,
+			Message: v.Message,
+			Reason:  Reason(err),
+		})
+	case roomv1.ErrNoMatchingProperty:
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusNotFound,
+			Message: v.Message,
+			Reason:  Reason(err),
+		})
+	default:
+		connected.Write <- FailRequest(payload.RequestID, &transportv1.Error{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to change property '%s', %v", key, err),
+			Reason:  transportv1.Error_INTERNAL,
 		})
 	}
 }
 
-func (p *StandardProtocol) broadcast(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room, connectedClientList []*sessionv1.Session) {
-	for _, connectedClient := range connectedClientList {
-		if connected.Client.ID == connectedClient.Client.ID {
-			// Message should only be sent to other clients, not sent back to origin
-			continue
+// sendPropertyChange tells every client in the room that one of the room's properties has changed
+func (p *StandardProtocol) sendPropertyChange(payload *transportv1.Payload, connected *sessionv1.Session, room roomv1.Room, property *roomspecv1.Property, deleted bool) {
+	changeData, err := proto.Marshal(&roomspecv1.PropertyChange{
+		Property: property,
+		Deleted:  deleted,
+		ClientID: connected.Client.ID,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	p.notifyRoom(transportv1.Payload_RESPONSE_PROPERTY_CHANGE, changeData, connected, room, payload.RequestID)
+}
+
+// propertySpec converts one of a room's properties into the form sent to clients
+func propertySpec(key string, property roomv1.Property) *roomspecv1.Property {
+	return &roomspecv1.Property{
+		Key:     key,
+		Value:   property.Value,
+		Version: property.Version,
+	}
+}
+
+// tracked determines if reliable delivery is being tracked for a client
+func (p *StandardProtocol) tracked(connected *sessionv1.Session, room roomv1.Room) bool {
+	return p.Reliable != nil && connected.Client != nil && p.Reliable.Tracked(p.roomID(room), connected.Client.ID)
+}
+
+// roomID returns the ID of a room, rooms that fail to provide their info are given an ID of zero
+func (p *StandardProtocol) roomID(room roomv1.Room) int32 {
+	info, err := room.GetInfo()
+	if err != nil {
+		glog.Errorf("Failed to retrieve room info, %v", err)
+		return 0
+	}
+	return info.ID
+}
+
+// writeReliable sends relayed messages with their reliable delivery sequence numbers to a client, giving up if the
+// client's session closes, as the messages are kept until acknowledged and are retransmitted when it reconnects
+func (p *StandardProtocol) writeReliable(connected *sessionv1.Session, messages []ReliableMessage) {
+	for _, message := range messages {
+		sequence := message.Sequence
+		select {
+		case connected.Write <- Succeed(relayedPayload(connected, message.Data, &sequence)):
+		case <-connected.CloseSignal:
+			return
 		}
-		connectedClient.Write <- Succeed(&transportv1.Payload{
-			Flag: transportv1.Payload_RESPONSE_RELAY_MESSAGE,
-			Data: payload.Data,
+	}
+}
+
+// sendAck acknowledges a message sent by a client with reliable delivery
+func (p *StandardProtocol) sendAck(connected *sessionv1.Session, sequence uint64) {
+	ackData, err := proto.Marshal(&relayv1.Ack{
+		Sequence: sequence,
+	})
+	if err != nil {
+		// Should not occur, panic
+		panic(err)
+	}
+
+	connected.Write <- Succeed(&transportv1.Payload{
+		Flag: transportv1.Payload_RESPONSE_ACK,
+		Data: ackData,
+	})
+}
+
+// retransmit resumes reliable delivery for a reconnecting client, resending every message it has not acknowledged, if
+// any messages were dropped the client is sent a replay gap first
+func (p *StandardProtocol) retransmit(connected *sessionv1.Session, roomID int32) {
+	p.Reliable.Rejoin(roomID, connected.Client.ID, func(messages []ReliableMessage, gap bool) {
+		p.writeRetransmit(connected, messages, gap)
+	})
+}
+
+// writeRetransmit sends a reconnecting client the messages it has not acknowledged, preceded by a replay gap if any
+// messages were dropped
+func (p *StandardProtocol) writeRetransmit(connected *sessionv1.Session, messages []ReliableMessage, gap bool) {
+	if gap {
+		replayGap := &relayv1.ReplayGap{}
+		if len(messages) > 0 {
+			replayGap.FirstSequence = messages[0].Sequence
+		}
+
+		gapData, err := proto.Marshal(replayGap)
+		if err != nil {
+			// Should not occur, panic
+			panic(err)
+		}
+
+		select {
+		case connected.Write <- Succeed(&transportv1.Payload{
+			Flag: transportv1.Payload_RESPONSE_REPLAY_GAP,
+			Data: gapData,
+		}):
+		case <-connected.CloseSignal:
+			return
+		}
+	}
+
+	p.writeReliable(connected, messages)
+}
+
+// replay sends a reconnecting client any relayed messages it missed after the last sequence number it saw, if any
+// messages are no longer stored the client is sent a replay gap before the messages that are still stored
+func (p *StandardProtocol) replay(connected *sessionv1.Session, room roomv1.Room, lastSequence uint64) {
+	messages, gap, err := room.ReplayMessages(connected.Client.ID, lastSequence)
+	if err != nil {
+		glog.Errorf("Failed to retrieve messages to replay for client with ID %d, %v", connected.Client.ID, err)
+		return
+	}
+
+	if gap {
+		replayGap := &relayv1.ReplayGap{
+			LastSequence: lastSequence,
+		}
+		if len(messages) > 0 {
+			replayGap.FirstSequence = messages[0].Sequence
+		}
+
+		gapData, err := proto.Marshal(replayGap)
+		if err != nil {
+			// Should not occur, panic
+			panic(err)
+		}
+
+		connected.Write <- Succeed(&transportv1.Payload{
+			Flag: transportv1.Payload_RESPONSE_REPLAY_GAP,
+			Data: gapData,
 		})
 	}
+
+	for _, message := range messages {
+		sequence := message.Sequence
+		connected.Write <- Succeed(relayedPayload(connected, message.Data, &sequence))
+	}
 }
 
 func (p *StandardProtocol) migrateHost(room roomv1.Room) error {
@@ -625,17 +2489,24 @@ func (p *StandardProtocol) migrateHost(room roomv1.Room) error {
 		return err
 	}
 
-	if len(connectedClients) <= 0 {
-		_, err = room.SetHost(nil)
-		return err
+	// Spectators can never be host, pick the first client that is not a spectator
+	for _, connectedClient := range connectedClients {
+		role, err := room.GetRole(connectedClient.Client.ID)
+		if err != nil {
+			return err
+		}
+		if role != roomv1.RoleSpectator {
+			return p.changeHost(room, connectedClient, nil, nil)
+		}
 	}
 
-	newHost := connectedClients[0]
-
-	return p.changeHost(room, newHost)
+	_, err = room.SetHost(nil)
+	return err
 }
 
-func (p *StandardProtocol) changeHost(room roomv1.Room, host *sessionv1.Session) error {
+// changeHost transfers host powers to the host provided, if the change was requested by a client the request ID is
+// included in the finish host migration message sent to the requesting client
+func (p *StandardProtocol) changeHost(room roomv1.Room, host *sessionv1.Session, requester *sessionv1.Session, requestID *uint32) error {
 	connectedClients, err := room.GetConnected()
 	if err != nil {
 		return err
@@ -668,10 +2539,15 @@ func (p *StandardProtocol) changeHost(room roomv1.Room, host *sessionv1.Session)
 	})
 
 	for _, connectedClient := range connectedClients {
-		connectedClient.Write <- Succeed(&transportv1.Payload{
+		finishMigration := &transportv1.Payload{
 			Flag: transportv1.Payload_RESPONSE_FINISH_HOST_MIGRATE,
 			Data: finishMigrationBytes,
-		})
+		}
+		if requester != nil && connectedClient.Client.ID == requester.Client.ID {
+			connectedClient.Write <- SucceedRequest(requestID, finishMigration)
+			continue
+		}
+		connectedClient.Write <- Succeed(finishMigration)
 	}
 
 	return nil
@@ -688,10 +2564,13 @@ func (p *StandardProtocol) sendClientConnectToHost(connecting *sessionv1.Session
 		return
 	}
 
-	responseData, err := proto.Marshal(&clientv1.SanitisedClient{
-		ID:   connecting.Client.ID,
-		Host: connecting.Client.ID == host.Client.ID,
-	})
+	sanitised, err := p.sanitise(connecting.Client, room)
+	if err != nil {
+		glog.Errorf("Failed to determine client's role, %v", err)
+		return
+	}
+
+	responseData, err := proto.Marshal(sanitised)
 	if err != nil {
 		// Should not occur, panic
 		panic(err)
@@ -704,7 +2583,8 @@ func (p *StandardProtocol) sendClientConnectToHost(connecting *sessionv1.Session
 	return
 }
 
-func (p *StandardProtocol) sendClientDisconnectToHost(disconnecting *sessionv1.Session, room roomv1.Room) {
+// sendClientEventToHost notifies the room's host that a client has disconnected or left
+func (p *StandardProtocol) sendClientEventToHost(flag transportv1.Payload_FlagType, client *clientv1.Client, room roomv1.Room) {
 	host, err := room.GetHost()
 	if err != nil {
 		glog.Errorf("Failed to retrieve the current host, %v", err)
@@ -715,18 +2595,55 @@ func (p *StandardProtocol) sendClientDisconnectToHost(disconnecting *sessionv1.S
 		return
 	}
 
-	responseData, err := proto.Marshal(&clientv1.SanitisedClient{
-		ID:   disconnecting.Client.ID,
-		Host: disconnecting.Client.ID == host.Client.ID,
-	})
+	sanitised, err := p.sanitise(client, room)
+	if err != nil {
+		glog.Errorf("Failed to determine client's role, %v", err)
+		return
+	}
+
+	responseData, err := proto.Marshal(sanitised)
 	if err != nil {
 		// Should not occur, panic
 		panic(err)
 	}
 
 	host.Write <- Succeed(&transportv1.Payload{
-		Flag: transportv1.Payload_RESPONSE_CLIENT_DISCONNECT,
+		Flag: flag,
 		Data: responseData,
 	})
 	return
 }
+
+// pruneDisconnected forgets any disconnected clients that have expired or are over the room's limit, notifying the
+// host that they have left, and handing host powers to a connected client if the host was forgotten
+func (p *StandardProtocol) pruneDisconnected(room roomv1.Room, now time.Time) error {
+	hostID, err := p.disconnectedHost(room)
+	if err != nil {
+		return err
+	}
+
+	pruned, err := room.PruneDisconnected(now)
+	if err != nil {
+		return err
+	}
+
+	for _, client := range pruned {
+		if hostID != nil && client.ID == *hostID {
+			// The host has been forgotten, so hand host powers to a connected client
+			err = p.migrateHost(room)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	for _, client := range pruned {
+		if p.Reliable != nil {
+			p.Reliable.Forget(p.roomID(room), client.ID)
+		}
+		p.sendClientEventToHost(transportv1.Payload_RESPONSE_CLIENT_LEAVE, client, room)
+	}
+
+	return nil
+}
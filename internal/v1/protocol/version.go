@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+)
+
+const (
+	// Version1 is the original protocol version, used by clients that do not perform a handshake
+	Version1 int32 = 1
+	// Version2 is the protocol version that introduces the v2 envelope, with request IDs included in payloads
+	Version2 int32 = 2
+	// Version3 is the protocol version that wraps relayed messages with the identity of the sender, sent using
+	// RESPONSE_RELAYED_MESSAGE rather than RESPONSE_RELAY_MESSAGE
+	Version3 int32 = 3
+)
+
+// RequestID returns the request ID to echo in the response to a client's request, request IDs are part of the v2
+// envelope so nil is returned for clients using protocol version 1
+func RequestID(connected *sessionv1.Session, payload *transportv1.Payload) *uint32 {
+	if connected.ProtocolVersion < Version2 {
+		return nil
+	}
+	return payload.RequestID
+}
+
+// ErrUnsupportedVersion occurs when a client requests a protocol version that no protocol supports
+type ErrUnsupportedVersion struct {
+	Message string
+}
+
+func (e ErrUnsupportedVersion) Error() string {
+	return "unsupported protocol version"
+}
+
+// Negotiate selects the protocol that best matches a client's handshake, picking the highest protocol version that
+// is supported by both the client and one of the protocols provided. The capabilities returned are the capabilities
+// that both the client and the selected protocol support
+func Negotiate(protocols []Protocol, handshake *transportv1.HandshakeRequest) (Protocol, int32, []string, error) {
+	var selected Protocol
+	selectedVersion := int32(0)
+	supported := []int32{}
+	for _, protocol := range protocols {
+		for _, version := range protocol.Versions() {
+			supported = append(supported, version)
+			if version <= handshake.ProtocolVersion && version > selectedVersion {
+				selected = protocol
+				selectedVersion = version
+			}
+		}
+	}
+
+	if selected == nil {
+		return nil, 0, nil, ErrUnsupportedVersion{
+			Message: fmt.Sprintf("Unsupported protocol version %d, supported versions are %v", handshake.ProtocolVersion, supported),
+		}
+	}
+
+	capabilities := []string{}
+	for _, capability := range selected.Capabilities() {
+		for _, requested := range handshake.Capabilities {
+			if capability == requested {
+				capabilities = append(capabilities, capability)
+				break
+			}
+		}
+	}
+
+	return selected, selectedVersion, capabilities, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+
+	roomv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/room"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	transportv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRouteEchoesRequestIDFromVersion2(t *testing.T) {
+	p := &StandardProtocol{
+		RoomManager: roomv1.NewMemoryManager(100, func(id, secret int32, options roomv1.Options) (roomv1.Room, error) {
+			return roomv1.NewMemoryRoom(id, secret, options)
+		}, 1),
+	}
+
+	tests := []struct {
+		name    string
+		version int32
+		echoed  bool
+	}{
+		{name: "version 1", version: Version1},
+		{name: "version 2", version: Version2, echoed: true},
+		{name: "version 3", version: Version3, echoed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connected := newTestSession()
+			connected.ProtocolVersion = tt.version
+
+			data, err := proto.Marshal(&roomspecv1.JoinRoomRequest{RoomID: -1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			requestID := uint32(42)
+			Route(p, &transportv1.Payload{
+				Flag:      transportv1.Payload_REQUEST_CONNECT,
+				Data:      data,
+				RequestID: &requestID,
+			}, connected, nil)
+
+			response := &transportv1.Payload{}
+			err = proto.Unmarshal(<-connected.Write, response)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.echoed && (response.RequestID == nil || *response.RequestID != requestID) {
+				t.Fatalf("expected request ID %d to be echoed, got %v", requestID, response.RequestID)
+			}
+			if !tt.echoed && response.RequestID != nil {
+				t.Fatalf("expected no request ID, got %d", *response.RequestID)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	protocols := []Protocol{&StandardProtocol{Reliable: NewReliable(8, 64)}}
+
+	tests := []struct {
+		name         string
+		handshake    *transportv1.HandshakeRequest
+		version      int32
+		capabilities []string
+		unsupported  bool
+	}{
+		{name: "highest version", handshake: &transportv1.HandshakeRequest{ProtocolVersion: 3}, version: Version3, capabilities: []string{}},
+		{name: "newer client", handshake: &transportv1.HandshakeRequest{ProtocolVersion: 9}, version: Version3, capabilities: []string{}},
+		{name: "older client", handshake: &transportv1.HandshakeRequest{ProtocolVersion: 2}, version: Version2, capabilities: []string{}},
+		{
+			name:         "shared capabilities",
+			handshake:    &transportv1.HandshakeRequest{ProtocolVersion: 3, Capabilities: []string{CapabilityReliable, "unknown"}},
+			version:      Version3,
+			capabilities: []string{CapabilityReliable},
+		},
+		{name: "unsupported version", handshake: &transportv1.HandshakeRequest{ProtocolVersion: 0}, unsupported: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, version, capabilities, err := Negotiate(protocols, tt.handshake)
+			if tt.unsupported {
+				if _, ok := err.(ErrUnsupportedVersion); !ok {
+					t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to negotiate: %v", err)
+			}
+			if version != tt.version {
+				t.Errorf("got version %d, want %d", version, tt.version)
+			}
+			if len(capabilities) != len(tt.capabilities) {
+				t.Fatalf("got capabilities %v, want %v", capabilities, tt.capabilities)
+			}
+			for i := range capabilities {
+				if capabilities[i] != tt.capabilities[i] {
+					t.Errorf("got capabilities %v, want %v", capabilities, tt.capabilities)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,590 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+	clientv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltRoomsBucket     = []byte("rooms")
+	boltTemplatesBucket = []byte("templates")
+)
+
+// boltRoomRecord is the persisted representation of a room, connected sessions are not persisted, instead every
+// client is persisted so that they can reconnect after a restart
+type boltRoomRecord struct {
+	ID         int32              `json:"id"`
+	Secret     int32              `json:"secret"`
+	MaxClients int32              `json:"max_clients"`
+	HostID     *int32             `json:"host_id,omitempty"`
+	Status     Status             `json:"status"`
+	Clients    []boltClientRecord `json:"clients"`
+	// NextClientID is the ID given to the next client to join, zero in records stored before it was included
+	NextClientID int32 `json:"next_client_id,omitempty"`
+
+	MaxLifetime        time.Duration `json:"max_lifetime,omitempty"`
+	IdleTimeout        time.Duration `json:"idle_timeout,omitempty"`
+	NeverJoinedTimeout time.Duration `json:"never_joined_timeout,omitempty"`
+	CreatedAt          time.Time     `json:"created_at"`
+	Joined             bool          `json:"joined"`
+
+	ReconnectGracePeriod   time.Duration       `json:"reconnect_grace_period,omitempty"`
+	MaxDisconnectedClients int32               `json:"max_disconnected_clients,omitempty"`
+	ReservationPolicy      ReservationPolicy   `json:"reservation_policy,omitempty"`
+	ReplayBufferSize       int32               `json:"replay_buffer_size,omitempty"`
+	Permissions            Permissions         `json:"permissions,omitempty"`
+	MaxSpectators          int32               `json:"max_spectators,omitempty"`
+	Properties             map[string]Property `json:"properties,omitempty"`
+	PropertiesVersion      uint64              `json:"properties_version,omitempty"`
+	Public                 bool                `json:"public,omitempty"`
+	Name                   string              `json:"name,omitempty"`
+	Tags                   []string            `json:"tags,omitempty"`
+	RelayRateLimit         int32               `json:"relay_rate_limit,omitempty"`
+	Template               string              `json:"template,omitempty"`
+	InviteCode             string              `json:"invite_code,omitempty"`
+	PasswordHash           string              `json:"password_hash,omitempty"`
+}
+
+type boltClientRecord struct {
+	ID       int32             `json:"id"`
+	Secret   int32             `json:"secret"`
+	Role     Role              `json:"role,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewBoltManager creates a new room manager that persists rooms to the bolt database provided, any rooms previously
+// persisted to the database are loaded, with all of their clients marked as disconnected from when they are loaded so
+// they can reconnect, and the room treated as idle from when it is loaded
+func NewBoltManager(db *bolt.DB, maxClients int32, ceilCommittedToNearest int32) (*BoltManager, error) {
+	manager := &BoltManager{
+		DB: db,
+	}
+
+	manager.MemoryManager = NewMemoryManager(maxClients, func(id, secret int32, options Options) (Room, error) {
+		return NewBoltRoom(db, id, secret, options)
+	}, ceilCommittedToNearest)
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltRoomsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = manager.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// BoltManager manages rooms in memory, while persisting room state to a bolt database so that rooms survive restarts
+type BoltManager struct {
+	*MemoryManager
+	DB *bolt.DB
+}
+
+// DeleteRoom deletes a room from memory and the database, specified by an ID
+func (m *BoltManager) DeleteRoom(id int32) error {
+	err := m.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRoomsBucket).Delete(boltRoomKey(id))
+	})
+	if err != nil {
+		return err
+	}
+	return m.MemoryManager.DeleteRoom(id)
+}
+
+func (m *BoltManager) load() error {
+	closing := []int32{}
+	missingInviteCode := []int32{}
+
+	err := m.DB.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRoomsBucket).ForEach(func(key, value []byte) error {
+			var record boltRoomRecord
+			err := json.Unmarshal(value, &record)
+			if err != nil {
+				return err
+			}
+
+			if !record.ReservationPolicy.valid() {
+				return ErrInvalidReservationPolicy{
+					Message: fmt.Sprintf("Room with ID %d has unknown reservation policy %d", record.ID, record.ReservationPolicy),
+				}
+			}
+
+			if record.Status == StatusClosing {
+				// Room was in the process of closing, finish closing it
+				closing = append(closing, record.ID)
+				return nil
+			}
+
+			if record.InviteCode == "" {
+				// Room was persisted before invite codes, give it one once every room is loaded
+				missingInviteCode = append(missingInviteCode, record.ID)
+			}
+
+			now := time.Now()
+
+			disconnected := make([]*DisconnectedClient, 0, len(record.Clients))
+			roles := make(map[int32]Role)
+			metadata := make(map[int32]map[string]string)
+			for _, client := range record.Clients {
+				disconnected = append(disconnected, &DisconnectedClient{
+					Client: &clientv1.Client{
+						ID:     client.ID,
+						Secret: client.Secret,
+					},
+					DisconnectedAt: now,
+				})
+				if client.Role != RolePlayer {
+					roles[client.ID] = client.Role
+				}
+				if len(client.Metadata) > 0 {
+					metadata[client.ID] = client.Metadata
+				}
+			}
+
+			properties := record.Properties
+			if properties == nil {
+				properties = make(map[string]Property)
+			}
+
+			permissions := record.Permissions
+			if permissions == nil {
+				permissions = DefaultPermissions()
+			}
+
+			var idleSince *time.Time
+			if record.Joined {
+				idleSince = &now
+			}
+
+			m.Rooms[record.ID] = &BoltRoom{
+				MemoryRoom: &MemoryRoom{
+					ID:                     record.ID,
+					Secret:                 record.Secret,
+					MaxClients:             record.MaxClients,
+					MaxSpectators:          record.MaxSpectators,
+					Public:                 record.Public,
+					Name:                   record.Name,
+					Tags:                   record.Tags,
+					MaxLifetime:            record.MaxLifetime,
+					IdleTimeout:            record.IdleTimeout,
+					NeverJoinedTimeout:     record.NeverJoinedTimeout,
+					ReconnectGracePeriod:   record.ReconnectGracePeriod,
+					MaxDisconnectedClients: record.MaxDisconnectedClients,
+					ReservationPolicy:      record.ReservationPolicy,
+					ReplayBufferSize:       record.ReplayBufferSize,
+					Permissions:            permissions,
+					RelayRateLimit:         record.RelayRateLimit,
+					Template:               record.Template,
+					InviteCode:             record.InviteCode,
+					PasswordHash:           record.PasswordHash,
+					Roles:                  roles,
+					Metadata:               metadata,
+					Properties:             properties,
+					PropertiesVersion:      record.PropertiesVersion,
+					ReplayBuffers:          make(map[int32]*ReplayBuffer),
+					RelayAllowances:        make(map[int32]*RelayAllowance),
+					CreatedAt:              record.CreatedAt,
+					IdleSince:              idleSince,
+					Joined:                 record.Joined,
+					HostID:                 record.HostID,
+					NextClientID:           nextClientID(record.NextClientID, disconnected),
+					ConnectedClients:       []*sessionv1.Session{},
+					DisconnectedClients:    disconnected,
+					RoomStatus:             record.Status,
+				},
+				DB: m.DB,
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range closing {
+		err = m.DeleteRoom(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range missingInviteCode {
+		_, err = m.RotateInviteCode(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewBoltRoom creates a new room that is persisted to the bolt database provided, it can return an error if the
+// options are invalid or if the room fails to persist
+func NewBoltRoom(db *bolt.DB, id int32, secret int32, options Options) (*BoltRoom, error) {
+	memoryRoom, err := NewMemoryRoom(id, secret, options)
+	if err != nil {
+		return nil, err
+	}
+
+	room := &BoltRoom{
+		MemoryRoom: memoryRoom,
+		DB:         db,
+	}
+
+	err = room.persist()
+	if err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// BoltRoom represents a room with connected sessions stored in memory, and the room's configuration, host and
+// clients persisted to a bolt database
+type BoltRoom struct {
+	*MemoryRoom
+	DB *bolt.DB
+
+	// revision numbers each captured state of the room, guarded by the room's lock
+	revision uint64
+	// persisted is the revision last written to the database, guarded by persistMutex, which is held while writing
+	persisted    uint64
+	persistMutex sync.Mutex
+}
+
+// SetStatus sets the room's status
+func (r *BoltRoom) SetStatus(status Status) {
+	r.MemoryRoom.SetStatus(status)
+	r.persistOrLog()
+}
+
+// NewClient handles creating a new client for the room for the connection provided
+func (r *BoltRoom) NewClient(connected *sessionv1.Session) (*sessionv1.Session, error) {
+	connected, err := r.MemoryRoom.NewClient(connected)
+	if err != nil {
+		return connected, err
+	}
+	return connected, r.persist()
+}
+
+// NewSpectator handles creating a new spectator for the room for the connection provided
+func (r *BoltRoom) NewSpectator(connected *sessionv1.Session) (*sessionv1.Session, error) {
+	connected, err := r.MemoryRoom.NewSpectator(connected)
+	if err != nil {
+		return connected, err
+	}
+	return connected, r.persist()
+}
+
+// ExistingClient handles regenerating a client based on a previously disconnected client for the connection provided
+func (r *BoltRoom) ExistingClient(connected *sessionv1.Session, clientID int32, clientSecret int32) (*sessionv1.Session, error) {
+	connected, err := r.MemoryRoom.ExistingClient(connected, clientID, clientSecret)
+	if err != nil {
+		return connected, err
+	}
+	return connected, r.persist()
+}
+
+// RemoveClient handles removing a client from the room
+func (r *BoltRoom) RemoveClient(clientID int32) error {
+	err := r.MemoryRoom.RemoveClient(clientID)
+	if err != nil {
+		return err
+	}
+	return r.persist()
+}
+
+// ForgetClient handles a client permanently leaving the room
+func (r *BoltRoom) ForgetClient(clientID int32) error {
+	err := r.MemoryRoom.ForgetClient(clientID)
+	if err != nil {
+		return err
+	}
+	return r.persist()
+}
+
+// PruneDisconnected forgets any disconnected clients whose reconnect grace period has passed or that are over the
+// room's limit, returning the clients forgotten
+func (r *BoltRoom) PruneDisconnected(now time.Time) ([]*clientv1.Client, error) {
+	pruned, err := r.MemoryRoom.PruneDisconnected(now)
+	if err != nil {
+		return nil, err
+	}
+	if len(pruned) == 0 {
+		return pruned, nil
+	}
+	return pruned, r.persist()
+}
+
+// SetRole sets the role of a client connected to the room
+func (r *BoltRoom) SetRole(clientID int32, role Role) error {
+	err := r.MemoryRoom.SetRole(clientID, role)
+	if err != nil {
+		return err
+	}
+	return r.persist()
+}
+
+// SetMetadata replaces the metadata of a client connected to the room
+func (r *BoltRoom) SetMetadata(clientID int32, metadata map[string]string) error {
+	err := r.MemoryRoom.SetMetadata(clientID, metadata)
+	if err != nil {
+		return err
+	}
+	return r.persist()
+}
+
+// SetProperty sets one of the room's properties
+func (r *BoltRoom) SetProperty(key string, value string, expectedVersion *uint64) (Property, error) {
+	property, err := r.MemoryRoom.SetProperty(key, value, expectedVersion)
+	if err != nil {
+		return property, err
+	}
+	return property, r.persist()
+}
+
+// DeleteProperty deletes one of the room's properties
+func (r *BoltRoom) DeleteProperty(key string, expectedVersion *uint64) (uint64, error) {
+	version, err := r.MemoryRoom.DeleteProperty(key, expectedVersion)
+	if err != nil {
+		return version, err
+	}
+	return version, r.persist()
+}
+
+// SetInviteCode replaces the room's invite code
+func (r *BoltRoom) SetInviteCode(code string) error {
+	err := r.MemoryRoom.SetInviteCode(code)
+	if err != nil {
+		return err
+	}
+	return r.persist()
+}
+
+// SetHost sets a room's host, can be set to nil for no host
+func (r *BoltRoom) SetHost(hostID *int32) (*sessionv1.Session, error) {
+	host, err := r.MemoryRoom.SetHost(hostID)
+	if err != nil {
+		return nil, err
+	}
+	return host, r.persist()
+}
+
+func (r *BoltRoom) persistOrLog() {
+	err := r.persist()
+	if err != nil {
+		glog.Errorf("Failed to persist room with ID %d, %v", r.ID, err)
+	}
+}
+
+// persist writes the room's state to the database. The state is captured while the room is locked, but written after
+// unlocking so that the room's clients are not held up by disk I/O, each capture is numbered so that a write is skipped
+// if a later state has already been written
+func (r *BoltRoom) persist() error {
+	revision, value, err := r.capture()
+	if err != nil {
+		return err
+	}
+
+	r.persistMutex.Lock()
+	defer r.persistMutex.Unlock()
+
+	if revision < r.persisted {
+		return nil
+	}
+
+	err = r.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRoomsBucket).Put(boltRoomKey(r.ID), value)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.persisted = revision
+	return nil
+}
+
+// capture encodes the room's current state for writing to the database, along with the revision of the state
+func (r *BoltRoom) capture() (uint64, []byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.revision++
+
+	record := boltRoomRecord{
+		ID:         r.ID,
+		Secret:     r.Secret,
+		MaxClients: r.MaxClients,
+		HostID:     r.HostID,
+		Status:     r.RoomStatus,
+		Clients:    make([]boltClientRecord, 0, len(r.ConnectedClients)+len(r.DisconnectedClients)),
+
+		NextClientID: r.NextClientID,
+
+		MaxLifetime:        r.MaxLifetime,
+		IdleTimeout:        r.IdleTimeout,
+		NeverJoinedTimeout: r.NeverJoinedTimeout,
+		CreatedAt:          r.CreatedAt,
+		Joined:             r.Joined,
+
+		ReconnectGracePeriod:   r.ReconnectGracePeriod,
+		MaxDisconnectedClients: r.MaxDisconnectedClients,
+		ReservationPolicy:      r.ReservationPolicy,
+		ReplayBufferSize:       r.ReplayBufferSize,
+		Permissions:            r.Permissions,
+		MaxSpectators:          r.MaxSpectators,
+		Properties:             r.Properties,
+		PropertiesVersion:      r.PropertiesVersion,
+		Public:                 r.Public,
+		Name:                   r.Name,
+		Tags:                   r.Tags,
+		RelayRateLimit:         r.RelayRateLimit,
+		Template:               r.Template,
+		InviteCode:             r.InviteCode,
+		PasswordHash:           r.PasswordHash,
+	}
+
+	for _, connected := range r.ConnectedClients {
+		record.Clients = append(record.Clients, boltClientRecord{
+			ID:       connected.Client.ID,
+			Secret:   connected.Client.Secret,
+			Role:     r.Roles[connected.Client.ID],
+			Metadata: r.Metadata[connected.Client.ID],
+		})
+	}
+
+	for _, disconnected := range r.DisconnectedClients {
+		record.Clients = append(record.Clients, boltClientRecord{
+			ID:       disconnected.ID,
+			Secret:   disconnected.Secret,
+			Role:     r.Roles[disconnected.ID],
+			Metadata: r.Metadata[disconnected.ID],
+		})
+	}
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return r.revision, value, nil
+}
+
+func boltRoomKey(id int32) []byte {
+	return []byte(strconv.FormatInt(int64(id), 10))
+}
+
+// NewBoltTemplateStore creates a new template store that persists templates to the bolt database provided, so that
+// templates registered at runtime survive restarts
+func NewBoltTemplateStore(db *bolt.DB) (*BoltTemplateStore, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltTemplatesBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltTemplateStore{
+		DB: db,
+	}, nil
+}
+
+// BoltTemplateStore persists templates to a bolt database, alongside the rooms
+type BoltTemplateStore struct {
+	DB *bolt.DB
+}
+
+// GetTemplate returns the template with the name provided, nil if none found
+func (s *BoltTemplateStore) GetTemplate(name string) (*api.RoomTemplate, error) {
+	var template *api.RoomTemplate
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltTemplatesBucket).Get([]byte(name))
+		if value == nil {
+			return nil
+		}
+		template = &api.RoomTemplate{}
+		return json.Unmarshal(value, template)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ListTemplates returns every template
+func (s *BoltTemplateStore) ListTemplates() ([]*api.RoomTemplate, error) {
+	list := []*api.RoomTemplate{}
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltTemplatesBucket).ForEach(func(key, value []byte) error {
+			template := &api.RoomTemplate{}
+			err := json.Unmarshal(value, template)
+			if err != nil {
+				return err
+			}
+			list = append(list, template)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// PutTemplate persists a template, replacing any existing template with the same name
+func (s *BoltTemplateStore) PutTemplate(template *api.RoomTemplate) error {
+	value, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltTemplatesBucket).Put([]byte(template.Name), value)
+	})
+}
+
+// DeleteTemplate removes the template with the name provided, returning if it existed
+func (s *BoltTemplateStore) DeleteTemplate(name string) (bool, error) {
+	deleted := false
+	err := s.DB.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltTemplatesBucket)
+		if bucket.Get([]byte(name)) == nil {
+			return nil
+		}
+		deleted = true
+		return bucket.Delete([]byte(name))
+	})
+	return deleted, err
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestReservationPolicyString(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ReservationPolicy
+		want   string
+	}{
+		{name: "first come", policy: ReservationPolicyFirstCome, want: "FIRST_COME"},
+		{name: "reserve", policy: ReservationPolicyReserve, want: "RESERVE"},
+		{name: "negative", policy: ReservationPolicy(-1), want: "UNKNOWN"},
+		{name: "out of range", policy: ReservationPolicy(2), want: "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoltLoadRejectsUnknownReservationPolicy(t *testing.T) {
+	db := openTestDB(t, filepath.Join(t.TempDir(), "rooms.db"))
+	defer db.Close()
+
+	_, err := NewBoltManager(db, 100, 1)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	record, err := json.Marshal(boltRoomRecord{
+		ID:                1,
+		Secret:            1,
+		MaxClients:        4,
+		ReservationPolicy: ReservationPolicy(7),
+		InviteCode:        "ABCD1234",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRoomsBucket).Put(boltRoomKey(1), record)
+	})
+	if err != nil {
+		t.Fatalf("failed to store room: %v", err)
+	}
+
+	_, err = NewBoltManager(db, 100, 1)
+	if _, ok := err.(ErrInvalidReservationPolicy); !ok {
+		t.Fatalf("expected ErrInvalidReservationPolicy loading the room, got %v", err)
+	}
+}
+
+func TestBoltConcurrentChangesPersistLatestState(t *testing.T) {
+	db := openTestDB(t, filepath.Join(t.TempDir(), "rooms.db"))
+	defer db.Close()
+
+	manager, err := NewBoltManager(db, 100, 1)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	room, err := manager.CreateRoom(Options{MaxClients: 32})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Errorf("failed to join room: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	reloaded, err := NewBoltManager(db, 100, 1)
+	if err != nil {
+		t.Fatalf("failed to reload manager: %v", err)
+	}
+	restored, err := reloaded.GetRoom(info.ID)
+	if err != nil {
+		t.Fatalf("failed to get restored room: %v", err)
+	}
+	disconnected, err := restored.GetDisconnected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disconnected) != 16 {
+		t.Errorf("got %d persisted clients, want 16", len(disconnected))
+	}
+}
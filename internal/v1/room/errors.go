@@ -69,3 +69,206 @@ type ErrMaxClientTooSmall struct {
 func (e ErrMaxClientTooSmall) Error() string {
 	return "max clients too small"
 }
+
+// ErrInvalidTimeout occurs when trying to create a room with a timeout that is invalid
+type ErrInvalidTimeout struct {
+	Message string
+}
+
+func (e ErrInvalidTimeout) Error() string {
+	return "invalid timeout"
+}
+
+// ErrInvalidDisconnectedLimit occurs when trying to create a room with a disconnected clients limit that is invalid
+type ErrInvalidDisconnectedLimit struct {
+	Message string
+}
+
+func (e ErrInvalidDisconnectedLimit) Error() string {
+	return "invalid disconnected clients limit"
+}
+
+// ErrInvalidSpectatorLimit occurs when trying to create a room with a max spectators value that is invalid
+type ErrInvalidSpectatorLimit struct {
+	Message string
+}
+
+func (e ErrInvalidSpectatorLimit) Error() string {
+	return "invalid spectator limit"
+}
+
+// ErrInvalidReservationPolicy occurs when trying to create a room with a reservation policy that does not exist
+type ErrInvalidReservationPolicy struct {
+	Message string
+}
+
+func (e ErrInvalidReservationPolicy) Error() string {
+	return "invalid reservation policy"
+}
+
+// ErrInvalidReplayBufferSize occurs when trying to create a room with a replay buffer size that is invalid
+type ErrInvalidReplayBufferSize struct {
+	Message string
+}
+
+func (e ErrInvalidReplayBufferSize) Error() string {
+	return "invalid replay buffer size"
+}
+
+// ErrInvalidPermissions occurs when trying to create a room with permissions that include roles or permissions that do
+// not exist
+type ErrInvalidPermissions struct {
+	Message string
+}
+
+func (e ErrInvalidPermissions) Error() string {
+	return "invalid permissions"
+}
+
+// ErrInvalidRelayPolicy occurs when trying to create a room with a relay policy that includes relay types that do not
+// exist, or alongside permissions
+type ErrInvalidRelayPolicy struct {
+	Message string
+}
+
+func (e ErrInvalidRelayPolicy) Error() string {
+	return "invalid relay policy"
+}
+
+// ErrInvalidRole occurs when trying to give a client a role that does not exist or cannot be granted
+type ErrInvalidRole struct {
+	Message string
+}
+
+func (e ErrInvalidRole) Error() string {
+	return "invalid role"
+}
+
+// ErrInvalidMetadata occurs when a client's metadata has too many entries, or has keys or values that are invalid
+type ErrInvalidMetadata struct {
+	Message string
+}
+
+func (e ErrInvalidMetadata) Error() string {
+	return "invalid metadata"
+}
+
+// ErrInvalidProperty occurs when trying to set a room property with a key or value that is invalid, or when the room
+// already has the maximum number of properties
+type ErrInvalidProperty struct {
+	Message string
+}
+
+func (e ErrInvalidProperty) Error() string {
+	return "invalid property"
+}
+
+// ErrPropertyConflict occurs when trying to change a room property that is not at the version expected
+type ErrPropertyConflict struct {
+	Message string
+}
+
+func (e ErrPropertyConflict) Error() string {
+	return "property conflict"
+}
+
+// ErrNoMatchingProperty occurs when trying to delete a room property that does not exist
+type ErrNoMatchingProperty struct {
+	Message string
+}
+
+func (e ErrNoMatchingProperty) Error() string {
+	return "no matching property"
+}
+
+// ErrInvalidListing occurs when trying to create a room with a name or tags that are invalid
+type ErrInvalidListing struct {
+	Message string
+}
+
+func (e ErrInvalidListing) Error() string {
+	return "invalid listing"
+}
+
+// ErrInvalidFilter occurs when trying to list public rooms with a filter that is invalid
+type ErrInvalidFilter struct {
+	Message string
+}
+
+func (e ErrInvalidFilter) Error() string {
+	return "invalid filter"
+}
+
+// ErrRoomOnOtherNode occurs when a room is owned by a different relay server node, the address of the node that owns
+// the room is provided so clients can be redirected to it
+type ErrRoomOnOtherNode struct {
+	Message string
+	Address string
+}
+
+func (e ErrRoomOnOtherNode) Error() string {
+	return "room on other node"
+}
+
+// ErrRoomAlreadyExists occurs when trying to add a room with an ID that is already in use
+type ErrRoomAlreadyExists struct {
+	Message string
+}
+
+func (e ErrRoomAlreadyExists) Error() string {
+	return "room already exists"
+}
+
+// ErrUnsupportedSnapshot occurs when trying to import a snapshot with a version that is not supported
+type ErrUnsupportedSnapshot struct {
+	Message string
+}
+
+func (e ErrUnsupportedSnapshot) Error() string {
+	return "unsupported snapshot"
+}
+
+// ErrInvalidRateLimit occurs when trying to create a room with a negative relay rate limit
+type ErrInvalidRateLimit struct {
+	Message string
+}
+
+func (e ErrInvalidRateLimit) Error() string {
+	return "invalid rate limit"
+}
+
+// ErrInvalidTemplate occurs when trying to register a room template that is invalid
+type ErrInvalidTemplate struct {
+	Message string
+}
+
+func (e ErrInvalidTemplate) Error() string {
+	return "invalid template"
+}
+
+// ErrNoTemplateFound occurs when a room template with a name cannot be found
+type ErrNoTemplateFound struct {
+	Message string
+}
+
+func (e ErrNoTemplateFound) Error() string {
+	return "no template found"
+}
+
+// ErrTooManyPasswordFailures occurs when too many wrong passwords have been given, either for a room or by a client
+type ErrTooManyPasswordFailures struct {
+	Message string
+}
+
+func (e ErrTooManyPasswordFailures) Error() string {
+	return "too many password failures"
+}
+
+// ErrInvalidPassword occurs when trying to create a room with a password that is too long
+type ErrInvalidPassword struct {
+	Message string
+}
+
+func (e ErrInvalidPassword) Error() string {
+	return "invalid password"
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// InviteCodeLength is the number of characters in a room's invite code
+	InviteCodeLength = 6
+	// MaxPasswordLength is the maximum length in bytes of a room's password
+	MaxPasswordLength = 128
+	// MaxSessionPasswordFailures is how many wrong invite codes or passwords a client can give on one connection,
+	// after which the connection can no longer join rooms by invite code
+	MaxSessionPasswordFailures = 5
+	// MaxRoomPasswordFailures is how many wrong passwords can be given for a room in each PasswordFailureWindow, after
+	// which the room refuses to check passwords until the window ends
+	MaxRoomPasswordFailures = 20
+	// PasswordFailureWindow is the period that wrong passwords for a room are counted over
+	PasswordFailureWindow = time.Minute
+)
+
+// inviteCodeAlphabet is the characters invite codes are made from, upper case letters and digits without the
+// characters that are easily mistaken for each other (0, O, 1 and I)
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const (
+	passwordHashScheme = "pbkdf2-sha256"
+	passwordIterations = 100000
+	passwordSaltLength = 16
+)
+
+// InviteCodeRotator defines a contract for room managers that can give a room a new invite code, after which the
+// room's previous invite code can no longer be used to join it
+type InviteCodeRotator interface {
+	RotateInviteCode(id int32) (string, error)
+}
+
+// NormaliseInviteCode converts an invite code as entered by a player into the form invite codes are generated in,
+// ignoring case and surrounding whitespace
+func NormaliseInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// FindByInviteCode returns the room with the invite code provided, if none found an error is returned
+func FindByInviteCode(rooms []Room, code string) (Room, error) {
+	code = NormaliseInviteCode(code)
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+		if code != "" && info.InviteCode == code {
+			return room, nil
+		}
+	}
+
+	return nil, ErrNoRoomFound{
+		Message: fmt.Sprintf("No room found with the invite code '%s'", code),
+	}
+}
+
+// RotateInviteCode gives the room with the ID provided a new invite code, unique across the room manager's rooms
+func (m *MemoryManager) RotateInviteCode(id int32) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	room, exists := m.Rooms[id]
+	if !exists {
+		return "", ErrNoRoomFound{
+			Message: fmt.Sprintf("No room found with the ID %d", id),
+		}
+	}
+
+	code, err := uniqueInviteCode(m.Rooms)
+	if err != nil {
+		return "", err
+	}
+
+	err = room.SetInviteCode(code)
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// uniqueInviteCode generates an invite code that is not used by any of the rooms provided, the caller must hold the
+// lock of the manager the rooms belong to
+func uniqueInviteCode(rooms map[int32]Room) (string, error) {
+	for {
+		code := newInviteCode()
+		inUse, err := inviteCodeInUse(rooms, code)
+		if err != nil {
+			return "", err
+		}
+		if !inUse {
+			return code, nil
+		}
+	}
+}
+
+func inviteCodeInUse(rooms map[int32]Room, code string) (bool, error) {
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			return false, err
+		}
+		if info.InviteCode == code {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newInviteCode() string {
+	code := make([]byte, InviteCodeLength)
+	for i := range code {
+		code[i] = inviteCodeAlphabet[mathrand.Intn(len(inviteCodeAlphabet))]
+	}
+	return string(code)
+}
+
+// SetInviteCode replaces the room's invite code
+func (r *MemoryRoom) SetInviteCode(code string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.InviteCode = code
+	return nil
+}
+
+// CheckPassword determines if the password provided is the room's password, rooms without a password accept any
+// password. Once too many wrong passwords have been given for the room an error is returned without checking the
+// password, until the failure window ends
+func (r *MemoryRoom) CheckPassword(password string) (bool, error) {
+	now := time.Now()
+
+	r.mutex.Lock()
+	passwordHash := r.PasswordHash
+	allowed := passwordHash == "" || r.PasswordFailures.take(now)
+	r.mutex.Unlock()
+
+	if passwordHash == "" {
+		return true, nil
+	}
+
+	if !allowed {
+		return false, ErrTooManyPasswordFailures{
+			Message: fmt.Sprintf("Too many wrong passwords given for room with ID %d, try again later", r.ID),
+		}
+	}
+
+	// The room is not locked while hashing, so checking a password does not hold up the room's other clients
+	matches, err := passwordMatches(passwordHash, password)
+	if err != nil || !matches {
+		return matches, err
+	}
+
+	// The attempt was counted as a failure before hashing so that concurrent checks cannot exceed the limit
+	r.mutex.Lock()
+	r.PasswordFailures.refund()
+	r.mutex.Unlock()
+	return true, nil
+}
+
+// PasswordFailures counts the wrong passwords given for a room in the current PasswordFailureWindow
+type PasswordFailures struct {
+	WindowStart time.Time
+	Failures    int32
+}
+
+// take counts an attempt against the limit as of the time provided, returning false if the limit has been reached
+func (f *PasswordFailures) take(now time.Time) bool {
+	if now.Sub(f.WindowStart) >= PasswordFailureWindow {
+		f.WindowStart = now
+		f.Failures = 0
+	}
+
+	if f.Failures >= MaxRoomPasswordFailures {
+		return false
+	}
+
+	f.Failures++
+	return true
+}
+
+// refund stops counting an attempt that turned out to be the right password
+func (f *PasswordFailures) refund() {
+	if f.Failures > 0 {
+		f.Failures--
+	}
+}
+
+// validatePassword checks that a room's password is within the size limit
+func validatePassword(password string) error {
+	if len(password) > MaxPasswordLength {
+		return ErrInvalidPassword{
+			Message: fmt.Sprintf("The room's password must be at most %d bytes", MaxPasswordLength),
+		}
+	}
+	return nil
+}
+
+// hashPassword hashes a room's password with a random salt using PBKDF2, so that the password itself is never stored,
+// an empty password is not hashed as the room has no password
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+
+	salt := make([]byte, passwordSaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	hash := pbkdf2.Key([]byte(password), salt, passwordIterations, sha256.Size, sha256.New)
+	return strings.Join([]string{
+		passwordHashScheme,
+		strconv.Itoa(passwordIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	}, "$"), nil
+}
+
+// passwordMatches determines if a password matches a hash generated by hashPassword
+func passwordMatches(encoded string, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != passwordHashScheme {
+		return false, fmt.Errorf("unsupported password hash")
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, err
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, err
+	}
+
+	derived := pbkdf2.Key([]byte(password), salt, iterations, len(hash), sha256.New)
+	return subtle.ConstantTimeCompare(hash, derived) == 1, nil
+}
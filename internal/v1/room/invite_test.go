@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testPasswordHash is "hunter2" hashed with a low iteration count, so that tests checking many passwords are quick
+const testPasswordHash = "pbkdf2-sha256$1000$MDEyMzQ1Njc4OWFiY2RlZg$pj4T35D2v4tYmC1sTJ1y5tcMADOdtnQGvuHmyYDQh2g"
+
+func TestPasswordHashing(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		matches  bool
+		err      bool
+	}{
+		{
+			name:     "right password",
+			hash:     testPasswordHash,
+			password: "hunter2",
+			matches:  true,
+		},
+		{
+			name:     "wrong password",
+			hash:     testPasswordHash,
+			password: "hunter3",
+		},
+		{
+			name:     "unsupported scheme",
+			hash:     "md5$1000$MDEyMzQ1Njc4OWFiY2RlZg$pj4T35D2v4tYmC1sTJ1y5tcMADOdtnQGvuHmyYDQh2g",
+			password: "hunter2",
+			err:      true,
+		},
+		{
+			name:     "malformed iterations",
+			hash:     "pbkdf2-sha256$many$MDEyMzQ1Njc4OWFiY2RlZg$pj4T35D2v4tYmC1sTJ1y5tcMADOdtnQGvuHmyYDQh2g",
+			password: "hunter2",
+			err:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := passwordMatches(tt.hash, tt.password)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error checking password")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to check password: %v", err)
+			}
+			if matches != tt.matches {
+				t.Errorf("expected matches to be %t, got %t", tt.matches, matches)
+			}
+		})
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	empty, err := hashPassword("")
+	if err != nil || empty != "" {
+		t.Fatalf("expected an empty password not to be hashed, got %q, %v", empty, err)
+	}
+
+	first, err := hashPassword("hunter2")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	second, err := hashPassword("hunter2")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	if strings.Contains(first, "hunter2") {
+		t.Errorf("expected the password not to be stored in the hash")
+	}
+	if first == second {
+		t.Errorf("expected each hash to use a different salt")
+	}
+
+	matches, err := passwordMatches(first, "hunter2")
+	if err != nil || !matches {
+		t.Errorf("expected the hashed password to match, got %t, %v", matches, err)
+	}
+}
+
+func TestCheckPasswordFailureLimit(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 2})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	room.PasswordHash = testPasswordHash
+
+	// Right passwords do not count towards the limit
+	for i := 0; i < 3; i++ {
+		matches, err := room.CheckPassword("hunter2")
+		if err != nil || !matches {
+			t.Fatalf("expected right password to match, got %t, %v", matches, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < MaxRoomPasswordFailures; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matches, err := room.CheckPassword("wrong")
+			if err != nil || matches {
+				t.Errorf("expected wrong password not to match, got %t, %v", matches, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, err = room.CheckPassword("hunter2")
+	if _, ok := err.(ErrTooManyPasswordFailures); !ok {
+		t.Fatalf("expected ErrTooManyPasswordFailures once the limit is reached, got %v", err)
+	}
+
+	room.mutex.Lock()
+	room.PasswordFailures.WindowStart = time.Now().Add(-PasswordFailureWindow)
+	room.mutex.Unlock()
+
+	matches, err := room.CheckPassword("hunter2")
+	if err != nil || !matches {
+		t.Errorf("expected passwords to be checked again once the window ends, got %t, %v", matches, err)
+	}
+}
+
+func TestNormaliseInviteCode(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{code: "ABC234", expected: "ABC234"},
+		{code: "abc234", expected: "ABC234"},
+		{code: "  aBc234\n", expected: "ABC234"},
+		{code: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if code := NormaliseInviteCode(tt.code); code != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestRotateInviteCode(t *testing.T) {
+	manager := newTestManager()
+	rooms := make([]Room, 50)
+	for i := range rooms {
+		room, err := manager.CreateRoom(Options{MaxClients: 2})
+		if err != nil {
+			t.Fatalf("failed to create room: %v", err)
+		}
+		rooms[i] = room
+	}
+
+	info, err := rooms[0].GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := info.InviteCode
+
+	code, err := manager.RotateInviteCode(info.ID)
+	if err != nil {
+		t.Fatalf("failed to rotate invite code: %v", err)
+	}
+	if code == previous {
+		t.Errorf("expected a new invite code")
+	}
+	if len(code) != InviteCodeLength || strings.Trim(code, inviteCodeAlphabet) != "" {
+		t.Errorf("expected %d characters from the invite code alphabet, got %q", InviteCodeLength, code)
+	}
+
+	all, err := manager.ListRooms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindByInviteCode(all, previous); err == nil {
+		t.Errorf("expected the previous invite code to no longer find the room")
+	}
+	found, err := FindByInviteCode(all, strings.ToLower(code))
+	if err != nil || found != rooms[0] {
+		t.Errorf("expected the new invite code to find the room, got %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, room := range all {
+		info, err := room.GetInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[info.InviteCode] {
+			t.Errorf("expected invite codes to be unique, %s is used twice", info.InviteCode)
+		}
+		seen[info.InviteCode] = true
+	}
+
+	if _, err := manager.RotateInviteCode(-1); err == nil {
+		t.Errorf("expected an error rotating the invite code of a missing room")
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sync"
+	"time"
 
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
 	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
@@ -43,27 +45,43 @@ type MemoryManager struct {
 	MaxClients             int32
 	Rooms                  map[int32]Room
 	CeilCommittedToNearest int32
+
+	// mutex guards Rooms, every read of Rooms must hold at least a read lock and every change must hold the write lock.
+	// The manager's lock is always taken before any room's lock, never after
+	mutex sync.RWMutex
+	// quickJoinMutex makes sure only one client is quick joining at a time, so that the choice of room and any room
+	// created for the client are seen by the next client
+	quickJoinMutex sync.Mutex
 }
 
 // GetRoom retrieves a room specified by an ID
 func (m *MemoryManager) GetRoom(id int32) (Room, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	room := m.Rooms[id]
 	if room == nil {
 		return nil, ErrNoRoomFound{
 			Message: fmt.Sprintf("No room found with the ID %d", id),
 		}
 	}
-	return m.Rooms[id], nil
+	return room, nil
 }
 
 // DeleteRoom deletes a room from memory specified by an ID
 func (m *MemoryManager) DeleteRoom(id int32) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	delete(m.Rooms, id)
 	return nil
 }
 
 // ListRooms returns a list of all the room manager's rooms
 func (m *MemoryManager) ListRooms() ([]Room, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	list := make([]Room, 0, len(m.Rooms))
 	for _, room := range m.Rooms {
 		list = append(list, room)
@@ -73,6 +91,14 @@ func (m *MemoryManager) ListRooms() ([]Room, error) {
 
 // Summary generates a rooms summary from all the rooms in the room manager
 func (m *MemoryManager) Summary() (*api.RoomsSummary, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.summary()
+}
+
+// summary generates a rooms summary, the caller must hold the manager's lock
+func (m *MemoryManager) summary() (*api.RoomsSummary, error) {
 	currentClients := int32(0)
 	committedClients := int32(0)
 	for _, room := range m.Rooms {
@@ -80,8 +106,8 @@ func (m *MemoryManager) Summary() (*api.RoomsSummary, error) {
 		if err != nil {
 			return nil, err
 		}
-		currentClients += info.CurrentClients
-		committedClients += int32(math.Ceil(float64(info.MaxClients)/float64(m.CeilCommittedToNearest)) * float64(m.CeilCommittedToNearest))
+		currentClients += info.CurrentClients + info.CurrentSpectators
+		committedClients += int32(math.Ceil(float64(info.MaxClients+info.MaxSpectators)/float64(m.CeilCommittedToNearest)) * float64(m.CeilCommittedToNearest))
 	}
 	return &api.RoomsSummary{
 		NumberOfRooms:    int32(len(m.Rooms)),
@@ -92,15 +118,17 @@ func (m *MemoryManager) Summary() (*api.RoomsSummary, error) {
 }
 
 // CreateRoom creates a new room in the room manager
-func (m *MemoryManager) CreateRoom(maxClients int32) (Room, error) {
+func (m *MemoryManager) CreateRoom(options Options) (Room, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
-	summary, err := m.Summary()
+	summary, err := m.summary()
 
 	if err != nil {
 		return nil, err
 	}
 
-	newCommittedClients := maxClients + summary.CommittedClients
+	newCommittedClients := options.MaxClients + options.MaxSpectators + summary.CommittedClients
 
 	if summary.MaxClients-(newCommittedClients) < 0 {
 		return nil, ErrRequestTooManyClients{
@@ -127,7 +155,12 @@ func (m *MemoryManager) CreateRoom(maxClients int32) (Room, error) {
 		}
 	}
 
-	room, err := m.RoomFactory(roomID, rand.Int31(), maxClients)
+	options.InviteCode, err = uniqueInviteCode(m.Rooms)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := m.RoomFactory(roomID, rand.Int31(), options)
 	if err != nil {
 		return nil, err
 	}
@@ -136,50 +169,245 @@ func (m *MemoryManager) CreateRoom(maxClients int32) (Room, error) {
 	return room, nil
 }
 
-// NewMemoryRoom creates a new memory room with some default options, it can return an error if the maxClients value
-// is invalid (less than 1)
-func NewMemoryRoom(id int32, secret int32, maxClients int32) (*MemoryRoom, error) {
-	if maxClients <= 0 {
-		return nil, ErrMaxClientTooSmall{
-			Message: fmt.Sprintf("The room must have a maximum clients value of 1 or more, %d is invalid", maxClients),
+// ValidateOptions checks that a room's options are valid, it can return an error if the maxClients value is invalid
+// (less than 1), if any of the timeouts or limits are negative, or if the permissions, listing, properties or password
+// are invalid
+func ValidateOptions(options Options) error {
+	if options.MaxClients <= 0 {
+		return ErrMaxClientTooSmall{
+			Message: fmt.Sprintf("The room must have a maximum clients value of 1 or more, %d is invalid", options.MaxClients),
+		}
+	}
+
+	if options.MaxLifetime < 0 || options.IdleTimeout < 0 || options.NeverJoinedTimeout < 0 ||
+		options.ReconnectGracePeriod < 0 {
+		return ErrInvalidTimeout{
+			Message: "The room's timeouts must be zero (disabled) or more",
+		}
+	}
+
+	if !options.ReservationPolicy.valid() {
+		return ErrInvalidReservationPolicy{
+			Message: fmt.Sprintf("Unknown reservation policy %d", options.ReservationPolicy),
+		}
+	}
+
+	if options.ReplayBufferSize < 0 {
+		return ErrInvalidReplayBufferSize{
+			Message: fmt.Sprintf("The room must have a replay buffer size of zero (disabled) or more, %d is invalid", options.ReplayBufferSize),
+		}
+	}
+
+	permissions := options.Permissions
+	if permissions == nil {
+		permissions = DefaultPermissions()
+	}
+
+	err := permissions.validate()
+	if err != nil {
+		return err
+	}
+
+	if options.MaxSpectators < 0 {
+		return ErrInvalidSpectatorLimit{
+			Message: fmt.Sprintf("The room must have a maximum spectators value of zero (no spectators) or more, %d is invalid", options.MaxSpectators),
+		}
+	}
+
+	err = validateListing(options.Name, options.Tags)
+	if err != nil {
+		return err
+	}
+
+	if options.RelayRateLimit < 0 {
+		return ErrInvalidRateLimit{
+			Message: fmt.Sprintf("The room must have a relay rate limit of zero (no limit) or more, %d is invalid", options.RelayRateLimit),
 		}
 	}
 
+	if len(options.Properties) > MaxProperties {
+		return ErrInvalidProperty{
+			Message: fmt.Sprintf("The room can have at most %d properties, %d provided", MaxProperties, len(options.Properties)),
+		}
+	}
+
+	for key, value := range options.Properties {
+		err = validateProperty(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = validatePassword(options.Password)
+	if err != nil {
+		return err
+	}
+
+	if options.MaxDisconnectedClients < 0 {
+		return ErrInvalidDisconnectedLimit{
+			Message: fmt.Sprintf("The room must have a maximum disconnected clients value of zero (no limit) or more, %d is invalid", options.MaxDisconnectedClients),
+		}
+	}
+
+	return nil
+}
+
+// NewMemoryRoom creates a new memory room with the options provided, it can return an error if the options are
+// invalid
+func NewMemoryRoom(id int32, secret int32, options Options) (*MemoryRoom, error) {
+	err := ValidateOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := options.Permissions
+	if permissions == nil {
+		permissions = DefaultPermissions()
+	}
+
+	properties, version := initialProperties(options.Properties)
+
+	passwordHash, err := hashPassword(options.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	return &MemoryRoom{
-		ID:                  id,
-		Secret:              secret,
-		MaxClients:          maxClients,
-		ConnectedClients:    []*sessionv1.Session{},
-		DisconnectedClients: []*clientv1.Client{},
-		RoomStatus:          StatusRunning,
+		ID:                     id,
+		Secret:                 secret,
+		MaxClients:             options.MaxClients,
+		MaxSpectators:          options.MaxSpectators,
+		Public:                 options.Public,
+		Name:                   options.Name,
+		Tags:                   options.Tags,
+		MaxLifetime:            options.MaxLifetime,
+		IdleTimeout:            options.IdleTimeout,
+		NeverJoinedTimeout:     options.NeverJoinedTimeout,
+		ReconnectGracePeriod:   options.ReconnectGracePeriod,
+		MaxDisconnectedClients: options.MaxDisconnectedClients,
+		ReservationPolicy:      options.ReservationPolicy,
+		ReplayBufferSize:       options.ReplayBufferSize,
+		Permissions:            permissions,
+		RelayRateLimit:         options.RelayRateLimit,
+		Template:               options.Template,
+		InviteCode:             options.InviteCode,
+		PasswordHash:           passwordHash,
+		Roles:                  make(map[int32]Role),
+		Metadata:               make(map[int32]map[string]string),
+		Properties:             properties,
+		PropertiesVersion:      version,
+		ReplayBuffers:          make(map[int32]*ReplayBuffer),
+		RelayAllowances:        make(map[int32]*RelayAllowance),
+		CreatedAt:              time.Now(),
+		ConnectedClients:       []*sessionv1.Session{},
+		DisconnectedClients:    []*DisconnectedClient{},
+		RoomStatus:             StatusRunning,
 	}, nil
 }
 
 // MemoryRoom represents a room in memory, with the connected clients and options stored in memory
 type MemoryRoom struct {
-	ID                  int32
-	Secret              int32
-	MaxClients          int32
-	HostID              *int32
+	ID                     int32
+	Secret                 int32
+	MaxClients             int32
+	MaxSpectators          int32
+	Public                 bool
+	Name                   string
+	Tags                   []string
+	MaxLifetime            time.Duration
+	IdleTimeout            time.Duration
+	NeverJoinedTimeout     time.Duration
+	ReconnectGracePeriod   time.Duration
+	MaxDisconnectedClients int32
+	ReservationPolicy      ReservationPolicy
+	ReplayBufferSize       int32
+	Permissions            Permissions
+	RelayRateLimit         int32
+	Template               string
+	InviteCode             string
+	PasswordHash           string
+	CreatedAt              time.Time
+	// IdleSince is when the last client left the room, nil if clients are connected or no client has joined yet
+	IdleSince *time.Time
+	Joined    bool
+	HostID    *int32
+	// NextClientID is the ID given to the next client to join, client IDs are never reused, even once a client has
+	// been forgotten
+	NextClientID        int32
 	ConnectedClients    []*sessionv1.Session
-	DisconnectedClients []*clientv1.Client
-	RoomStatus          Status
+	DisconnectedClients []*DisconnectedClient
+	// Roles are the roles of clients that have been granted a role other than player, by client ID
+	Roles map[int32]Role
+	// Metadata are the metadata set by clients, by client ID
+	Metadata map[int32]map[string]string
+	// Properties are the room's shared state, by key, with PropertiesVersion the version of the latest change
+	Properties        map[string]Property
+	PropertiesVersion uint64
+	// ReplayBuffers are the relayed messages sent to each client, by client ID, only stored in memory
+	ReplayBuffers map[int32]*ReplayBuffer
+	// RelayAllowances track how many messages each client can still relay under the rate limit, by client ID, only
+	// stored in memory
+	RelayAllowances map[int32]*RelayAllowance
+	// PasswordFailures tracks the wrong passwords given for the room, only stored in memory
+	PasswordFailures PasswordFailures
+	RoomStatus       Status
+
+	// mutex guards the room's state, as the room is used by the goroutines of every client connected to it and by the
+	// reaper. Every method that reads or changes the room's state holds it, which also makes checking for a free slot
+	// and taking it a single step, so that clients racing for the last slot cannot both take it
+	mutex sync.Mutex
+}
+
+// nextClientID returns the ID to give the next client to join a restored room, which is the stored next ID unless a
+// restored client already has that ID or later, e.g. if the room was stored before the next ID was
+func nextClientID(stored int32, clients []*DisconnectedClient) int32 {
+	next := stored
+	for _, client := range clients {
+		if next <= client.ID {
+			next = client.ID + 1
+		}
+	}
+	return next
+}
+
+// DisconnectedClient is a client that has disconnected from a room, but is remembered so it can reconnect
+type DisconnectedClient struct {
+	*clientv1.Client
+	DisconnectedAt time.Time
 }
 
 // GetStatus returns the room's status
 func (r *MemoryRoom) GetStatus() Status {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	return r.RoomStatus
 }
 
 // SetStatus sets the room's status
 func (r *MemoryRoom) SetStatus(status Status) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.RoomStatus = status
 }
 
+// open returns an error if the room is closing, so that no client can join a room after it has started disconnecting
+// its clients, the caller must hold the room's lock
+func (r *MemoryRoom) open() error {
+	if r.RoomStatus == StatusClosing {
+		return ErrNoRoomFound{
+			Message: fmt.Sprintf("Room with ID %d is closing", r.ID),
+		}
+	}
+	return nil
+}
+
 // IsHost determines if a client is the room's host
 func (r *MemoryRoom) IsHost(potentialHost *clientv1.Client) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	// Not host if no host assigned, or ID doesn't match host ID
-	return &potentialHost.ID == r.HostID, nil
+	return r.HostID != nil && potentialHost.ID == *r.HostID, nil
 }
 
 // RoomMatches determines if a room matches the ID and secret provided
@@ -189,48 +417,355 @@ func (r *MemoryRoom) RoomMatches(id int32, secret int32) bool {
 
 // GetInfo generates the room's info
 func (r *MemoryRoom) GetInfo() (*api.RoomInfo, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	return &api.RoomInfo{
 		ID:             r.ID,
 		Secret:         r.Secret,
 		MaxClients:     r.MaxClients,
-		CurrentClients: int32(len(r.ConnectedClients)),
+		CurrentClients: r.connectedCount(false),
 		RoomStatus:     r.RoomStatus.String(),
+		ExpiresAt:      r.expiry(),
+
+		MaxSpectators:     r.MaxSpectators,
+		CurrentSpectators: r.connectedCount(true),
+
+		ReservedClients:   r.reserved(time.Now(), false),
+		ReservationPolicy: r.ReservationPolicy.String(),
+		Permissions:       r.Permissions.Names(),
+		ClientRelayTypes:  r.Permissions.RelayTypes(),
+		Properties:        r.propertyValues(),
+
+		Public: r.Public,
+		Name:   r.Name,
+		Tags:   r.Tags,
+
+		RelayRateLimit: r.RelayRateLimit,
+		Template:       r.Template,
+
+		InviteCode:        r.InviteCode,
+		PasswordProtected: r.PasswordHash != "",
 	}, nil
 }
 
-// NewClient handles creating a new client for the room for the connection provided
-func (r *MemoryRoom) NewClient(connected *sessionv1.Session) (*sessionv1.Session, error) {
-	if int32(len(r.ConnectedClients)) >= r.MaxClients {
-		return connected, ErrRoomFull{
-			Message: fmt.Sprintf("Room with ID %d is full", r.ID),
+// GetPermissions returns what clients in each role are permitted to do in the room
+func (r *MemoryRoom) GetPermissions() Permissions {
+	return r.Permissions
+}
+
+// GetRole returns a client's role in the room, the room's host always has the host role
+func (r *MemoryRoom) GetRole(clientID int32) (Role, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.HostID != nil && *r.HostID == clientID {
+		return RoleHost, nil
+	}
+
+	role, exists := r.Roles[clientID]
+	if !exists {
+		return RolePlayer, nil
+	}
+	return role, nil
+}
+
+// SetRole sets the role of a client connected to the room, the host role cannot be set as it is only held by the
+// room's host, and the spectator role is only held by clients that joined as spectators
+func (r *MemoryRoom) SetRole(clientID int32, role Role) error {
+	if role == RoleHost {
+		return ErrInvalidRole{
+			Message: fmt.Sprintf("The %s role cannot be granted, grant host to the client instead", RoleHost),
 		}
 	}
 
-	newID := int32(0)
-	for i := 0; i < len(r.ConnectedClients); i++ {
-		if newID <= r.ConnectedClients[i].Client.ID {
-			newID = r.ConnectedClients[i].Client.ID + 1
+	if role == RoleSpectator {
+		return ErrInvalidRole{
+			Message: fmt.Sprintf("The %s role cannot be granted, clients must join the room as a spectator", RoleSpectator),
 		}
 	}
-	for i := 0; i < len(r.DisconnectedClients); i++ {
-		if newID <= r.DisconnectedClients[i].ID {
-			newID = r.DisconnectedClients[i].ID + 1
+
+	if role < RolePlayer || role > RoleHost {
+		return ErrInvalidRole{
+			Message: fmt.Sprintf("Unknown role %d, must be one of %s or %s", role, RolePlayer, RoleModerator),
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, err := r.client(clientID)
+	if err != nil {
+		return err
+	}
+
+	if r.spectator(clientID) {
+		return ErrInvalidRole{
+			Message: fmt.Sprintf("Cannot change the role of client with ID %d, spectators cannot be granted roles", clientID),
 		}
 	}
 
+	if role == RolePlayer {
+		delete(r.Roles, clientID)
+		return nil
+	}
+
+	r.Roles[clientID] = role
+	return nil
+}
+
+// GetMetadata returns the metadata of a client in the room, clients that have not set any metadata have none
+func (r *MemoryRoom) GetMetadata(clientID int32) (map[string]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.Metadata[clientID], nil
+}
+
+// SetMetadata replaces the metadata of a client connected to the room, the metadata must be within the size limits
+func (r *MemoryRoom) SetMetadata(clientID int32, metadata map[string]string) error {
+	err := ValidateMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, err = r.client(clientID)
+	if err != nil {
+		return err
+	}
+
+	if len(metadata) == 0 {
+		delete(r.Metadata, clientID)
+		return nil
+	}
+
+	r.Metadata[clientID] = metadata
+	return nil
+}
+
+// GetProperties returns the room's properties, by key
+func (r *MemoryRoom) GetProperties() (map[string]Property, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	properties := make(map[string]Property, len(r.Properties))
+	for key, property := range r.Properties {
+		properties[key] = property
+	}
+	return properties, nil
+}
+
+// SetProperty sets one of the room's properties, if an expected version is provided the property is only set if it is
+// at that version, with version zero expecting the property to not exist yet
+func (r *MemoryRoom) SetProperty(key string, value string, expectedVersion *uint64) (Property, error) {
+	err := validateProperty(key, value)
+	if err != nil {
+		return Property{}, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current, exists := r.Properties[key]
+	err = checkVersion(key, current, exists, expectedVersion)
+	if err != nil {
+		return Property{}, err
+	}
+
+	if !exists && len(r.Properties) >= MaxProperties {
+		return Property{}, ErrInvalidProperty{
+			Message: fmt.Sprintf("Room with ID %d already has the maximum of %d properties", r.ID, MaxProperties),
+		}
+	}
+
+	r.PropertiesVersion++
+	property := Property{
+		Value:   value,
+		Version: r.PropertiesVersion,
+	}
+	r.Properties[key] = property
+	return property, nil
+}
+
+// DeleteProperty deletes one of the room's properties, returning the version of the change. If an expected version
+// is provided the property is only deleted if it is at that version
+func (r *MemoryRoom) DeleteProperty(key string, expectedVersion *uint64) (uint64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current, exists := r.Properties[key]
+	if !exists {
+		return 0, ErrNoMatchingProperty{
+			Message: fmt.Sprintf("No property found with key '%s'", key),
+		}
+	}
+
+	err := checkVersion(key, current, exists, expectedVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	r.PropertiesVersion++
+	delete(r.Properties, key)
+	return r.PropertiesVersion, nil
+}
+
+// propertyValues returns the values of the room's properties, by key
+func (r *MemoryRoom) propertyValues() map[string]string {
+	values := make(map[string]string, len(r.Properties))
+	for key, property := range r.Properties {
+		values[key] = property.Value
+	}
+	return values
+}
+
+// spectator determines if a client joined the room as a spectator
+func (r *MemoryRoom) spectator(clientID int32) bool {
+	return r.Roles[clientID] == RoleSpectator
+}
+
+// capacity returns the maximum number of connected clients in either the spectator or player pool
+func (r *MemoryRoom) capacity(spectators bool) int32 {
+	if spectators {
+		return r.MaxSpectators
+	}
+	return r.MaxClients
+}
+
+// connectedCount returns the number of connected clients in either the spectator or player pool
+func (r *MemoryRoom) connectedCount(spectators bool) int32 {
+	count := int32(0)
+	for _, connectedClient := range r.ConnectedClients {
+		if r.spectator(connectedClient.Client.ID) == spectators {
+			count++
+		}
+	}
+	return count
+}
+
+// reserved returns the number of slots in either the spectator or player pool reserved for disconnected clients that
+// can still reconnect, slots are only reserved if the room uses the reserve policy
+func (r *MemoryRoom) reserved(now time.Time, spectators bool) int32 {
+	if r.ReservationPolicy != ReservationPolicyReserve {
+		return 0
+	}
+
+	reserved := int32(0)
+	for _, disconnected := range r.DisconnectedClients {
+		if r.spectator(disconnected.ID) != spectators {
+			continue
+		}
+		if r.graceExpired(disconnected, now) {
+			continue
+		}
+		reserved++
+	}
+	return reserved
+}
+
+// graceExpired determines if a disconnected client's reconnect grace period has passed
+func (r *MemoryRoom) graceExpired(disconnected *DisconnectedClient, now time.Time) bool {
+	return r.ReconnectGracePeriod > 0 && !now.Before(disconnected.DisconnectedAt.Add(r.ReconnectGracePeriod))
+}
+
+// Expiry returns when the room expires based on its timeouts, if the room has no applicable timeouts nil is returned
+func (r *MemoryRoom) Expiry() *time.Time {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.expiry()
+}
+
+// expiry returns when the room expires, the caller must hold the room's lock
+func (r *MemoryRoom) expiry() *time.Time {
+	var expiry *time.Time
+	earliest := func(candidate time.Time) {
+		if expiry == nil || candidate.Before(*expiry) {
+			expiry = &candidate
+		}
+	}
+
+	if r.MaxLifetime > 0 {
+		earliest(r.CreatedAt.Add(r.MaxLifetime))
+	}
+
+	if !r.Joined && r.NeverJoinedTimeout > 0 {
+		earliest(r.CreatedAt.Add(r.NeverJoinedTimeout))
+	}
+
+	if r.IdleSince != nil && r.IdleTimeout > 0 {
+		earliest(r.IdleSince.Add(r.IdleTimeout))
+	}
+
+	return expiry
+}
+
+// NewClient handles creating a new client for the room for the connection provided, any slots reserved for
+// disconnected clients cannot be taken
+func (r *MemoryRoom) NewClient(connected *sessionv1.Session) (*sessionv1.Session, error) {
+	return r.join(connected, false)
+}
+
+// NewSpectator handles creating a new spectator for the room for the connection provided, spectators have their own
+// slots separate from other clients
+func (r *MemoryRoom) NewSpectator(connected *sessionv1.Session) (*sessionv1.Session, error) {
+	return r.join(connected, true)
+}
+
+// join adds a new client to either the spectator or player pool, if the pool has a free slot
+func (r *MemoryRoom) join(connected *sessionv1.Session, spectator bool) (*sessionv1.Session, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.open()
+	if err != nil {
+		return connected, err
+	}
+
+	if r.connectedCount(spectator)+r.reserved(time.Now(), spectator) >= r.capacity(spectator) {
+		if spectator && r.MaxSpectators == 0 {
+			return connected, ErrRoomFull{
+				Message: fmt.Sprintf("Room with ID %d does not allow spectators", r.ID),
+			}
+		}
+		return connected, ErrRoomFull{
+			Message: fmt.Sprintf("Room with ID %d is full", r.ID),
+		}
+	}
+
+	newID := r.NextClientID
+	r.NextClientID++
+
 	connected.Client = &clientv1.Client{
 		ID:     newID,
 		Secret: rand.Int31(),
 	}
 
 	r.ConnectedClients = append(r.ConnectedClients, connected)
+	r.Joined = true
+	r.IdleSince = nil
+
+	if spectator {
+		r.Roles[newID] = RoleSpectator
+	}
 
 	return connected, nil
 }
 
 // ExistingClient handles regenerating a client based on a previously disconnected client for the connection provided
 func (r *MemoryRoom) ExistingClient(connected *sessionv1.Session, clientID int32, clientSecret int32) (*sessionv1.Session, error) {
-	if int32(len(r.ConnectedClients)) >= r.MaxClients {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	err := r.open()
+	if err != nil {
+		return connected, err
+	}
+
+	spectator := r.spectator(clientID)
+	if r.connectedCount(spectator) >= r.capacity(spectator) {
 		return connected, ErrRoomFull{
 			Message: fmt.Sprintf("Room with ID %d is full", r.ID),
 		}
@@ -239,6 +774,12 @@ func (r *MemoryRoom) ExistingClient(connected *sessionv1.Session, clientID int32
 	for i := 0; i < len(r.DisconnectedClients); i++ {
 		matchClient := r.DisconnectedClients[i]
 		if clientID == matchClient.ID {
+			if r.graceExpired(matchClient, time.Now()) {
+				// The client is forgotten and its departure announced once the room is next pruned
+				return connected, ErrNoMatchingClient{
+					Message: fmt.Sprintf("Reconnect grace period for client with ID %d has passed", clientID),
+				}
+			}
 			if clientSecret == matchClient.Secret {
 				connected.Client = &clientv1.Client{
 					ID:     clientID,
@@ -246,6 +787,8 @@ func (r *MemoryRoom) ExistingClient(connected *sessionv1.Session, clientID int32
 				}
 				r.DisconnectedClients = append(r.DisconnectedClients[:i], r.DisconnectedClients[i+1:]...)
 				r.ConnectedClients = append(r.ConnectedClients, connected)
+				r.Joined = true
+				r.IdleSince = nil
 
 				return connected, nil
 			}
@@ -262,6 +805,13 @@ func (r *MemoryRoom) ExistingClient(connected *sessionv1.Session, clientID int32
 
 // GetClient returns a client with the ID provided, if none found an error is returned
 func (r *MemoryRoom) GetClient(clientID int32) (*session.Session, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.client(clientID)
+}
+
+// client returns a connected client with the ID provided, the caller must hold the room's lock
+func (r *MemoryRoom) client(clientID int32) (*session.Session, error) {
 	for _, connectedClient := range r.ConnectedClients {
 		if connectedClient.Client.ID == clientID {
 			return connectedClient, nil
@@ -275,10 +825,20 @@ func (r *MemoryRoom) GetClient(clientID int32) (*session.Session, error) {
 
 // RemoveClient handles removing a client from the room
 func (r *MemoryRoom) RemoveClient(clientID int32) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	for i, connectedClient := range r.ConnectedClients {
 		if clientID == connectedClient.Client.ID {
 			r.ConnectedClients = append(r.ConnectedClients[:i], r.ConnectedClients[i+1:]...)
-			r.DisconnectedClients = append(r.DisconnectedClients, connectedClient.Client)
+			r.DisconnectedClients = append(r.DisconnectedClients, &DisconnectedClient{
+				Client:         connectedClient.Client,
+				DisconnectedAt: time.Now(),
+			})
+			if len(r.ConnectedClients) == 0 {
+				now := time.Now()
+				r.IdleSince = &now
+			}
 			return nil
 		}
 	}
@@ -287,19 +847,155 @@ func (r *MemoryRoom) RemoveClient(clientID int32) error {
 	}
 }
 
-// GetConnected returns a list of all currently connected sessions
+// ForgetClient handles a client permanently leaving the room, removing the client whether it is connected or
+// disconnected so that it cannot reconnect
+func (r *MemoryRoom) ForgetClient(clientID int32) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.forget(clientID)
+
+	for i, disconnected := range r.DisconnectedClients {
+		if disconnected.ID == clientID {
+			r.DisconnectedClients = append(r.DisconnectedClients[:i], r.DisconnectedClients[i+1:]...)
+			return nil
+		}
+	}
+
+	for i, connectedClient := range r.ConnectedClients {
+		if connectedClient.Client.ID == clientID {
+			r.ConnectedClients = append(r.ConnectedClients[:i], r.ConnectedClients[i+1:]...)
+			if len(r.ConnectedClients) == 0 {
+				now := time.Now()
+				r.IdleSince = &now
+			}
+			return nil
+		}
+	}
+
+	return ErrNoMatchingClient{
+		Message: fmt.Sprintf("No client found with ID %d", clientID),
+	}
+}
+
+// forget removes everything the room stores about a client, including its host powers, the caller must hold the
+// room's lock
+func (r *MemoryRoom) forget(clientID int32) {
+	if r.HostID != nil && *r.HostID == clientID {
+		r.HostID = nil
+	}
+	delete(r.ReplayBuffers, clientID)
+	delete(r.RelayAllowances, clientID)
+	delete(r.Roles, clientID)
+	delete(r.Metadata, clientID)
+}
+
+// PruneDisconnected forgets any disconnected clients whose reconnect grace period has passed, and the clients that
+// disconnected earliest if there are more disconnected clients than the room's limit, returning the clients forgotten
+func (r *MemoryRoom) PruneDisconnected(now time.Time) ([]*clientv1.Client, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	pruned := []*clientv1.Client{}
+	remaining := make([]*DisconnectedClient, 0, len(r.DisconnectedClients))
+
+	for _, disconnected := range r.DisconnectedClients {
+		if r.graceExpired(disconnected, now) {
+			pruned = append(pruned, disconnected.Client)
+			r.forget(disconnected.ID)
+			continue
+		}
+		remaining = append(remaining, disconnected)
+	}
+
+	if r.MaxDisconnectedClients > 0 && int32(len(remaining)) > r.MaxDisconnectedClients {
+		// Disconnected clients are stored in the order they disconnected, so the earliest are first
+		excess := int32(len(remaining)) - r.MaxDisconnectedClients
+		for _, disconnected := range remaining[:excess] {
+			pruned = append(pruned, disconnected.Client)
+			r.forget(disconnected.ID)
+		}
+		remaining = remaining[excess:]
+	}
+
+	r.DisconnectedClients = remaining
+
+	return pruned, nil
+}
+
+// GetConnected returns a list of all currently connected sessions, the list is a copy so it is not changed by clients
+// joining or leaving while it is in use
 func (r *MemoryRoom) GetConnected() ([]*sessionv1.Session, error) {
-	return r.ConnectedClients, nil
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	connected := make([]*sessionv1.Session, len(r.ConnectedClients))
+	copy(connected, r.ConnectedClients)
+	return connected, nil
+}
+
+// GetDisconnected returns a list of all disconnected clients that are remembered by the room
+func (r *MemoryRoom) GetDisconnected() ([]*clientv1.Client, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	disconnected := make([]*clientv1.Client, 0, len(r.DisconnectedClients))
+	for _, disconnectedClient := range r.DisconnectedClients {
+		disconnected = append(disconnected, disconnectedClient.Client)
+	}
+	return disconnected, nil
+}
+
+// RecordMessage stores a relayed message sent to a client so that it can be replayed, returning the message's
+// sequence number, if replaying messages is disabled for the room nil is returned
+func (r *MemoryRoom) RecordMessage(clientID int32, data []byte) (*uint64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.ReplayBufferSize <= 0 {
+		return nil, nil
+	}
+
+	buffer, exists := r.ReplayBuffers[clientID]
+	if !exists {
+		buffer = NewReplayBuffer(r.ReplayBufferSize)
+		r.ReplayBuffers[clientID] = buffer
+	}
+
+	sequence := buffer.Add(data)
+	return &sequence, nil
+}
+
+// ReplayMessages returns the relayed messages sent to a client after the last sequence number provided, and if any
+// messages have been missed because they are no longer stored
+func (r *MemoryRoom) ReplayMessages(clientID int32, lastSequence uint64) ([]ReplayMessage, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	buffer, exists := r.ReplayBuffers[clientID]
+	if !exists {
+		return []ReplayMessage{}, lastSequence > 0, nil
+	}
+
+	messages, gap := buffer.Since(lastSequence)
+	return messages, gap, nil
 }
 
 // SetHost sets a room's host, can be set to nil for no host
 func (r *MemoryRoom) SetHost(hostID *int32) (*sessionv1.Session, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.setHost(hostID)
+}
+
+// setHost sets a room's host, the caller must hold the room's lock
+func (r *MemoryRoom) setHost(hostID *int32) (*sessionv1.Session, error) {
 	if hostID == nil {
 		r.HostID = nil
 		return nil, nil
 	}
 
-	host, err := r.GetClient(*hostID)
+	host, err := r.client(*hostID)
 	if err != nil {
 		return nil, err
 	}
@@ -309,18 +1005,22 @@ func (r *MemoryRoom) SetHost(hostID *int32) (*sessionv1.Session, error) {
 	return host, nil
 }
 
-// GetHost gets a room's host
+// GetHost gets a room's host, nil is returned if the room has no host or the host is not connected. A host that is
+// not connected (e.g. restored after a restart) keeps its host powers until it reconnects or another client is made
+// host
 func (r *MemoryRoom) GetHost() (*sessionv1.Session, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	if r.HostID == nil {
-		r.HostID = nil
 		return nil, nil
 	}
 
-	host, err := r.GetClient(*r.HostID)
+	host, err := r.client(*r.HostID)
 	if err != nil {
 		switch err.(type) {
 		case ErrNoMatchingClient:
-			return r.SetHost(nil)
+			return nil, nil
 		default:
 			return nil, err
 		}
@@ -0,0 +1,510 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+)
+
+func newTestManager() *MemoryManager {
+	return NewMemoryManager(10000, func(id, secret int32, options Options) (Room, error) {
+		return NewMemoryRoom(id, secret, options)
+	}, 1)
+}
+
+func newTestSession() *sessionv1.Session {
+	return &sessionv1.Session{
+		Write:       make(chan []byte, 16),
+		CloseSignal: make(chan struct{}),
+	}
+}
+
+func TestConcurrentJoinsCannotOverfillRoom(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxClients int32
+		joining    int
+		spectators bool
+	}{
+		{name: "clients racing for one slot", maxClients: 1, joining: 20},
+		{name: "clients racing for some slots", maxClients: 5, joining: 50},
+		{name: "fewer clients than slots", maxClients: 50, joining: 10},
+		{name: "spectators racing for slots", maxClients: 3, joining: 30, spectators: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := Options{MaxClients: tt.maxClients}
+			if tt.spectators {
+				options.MaxSpectators = tt.maxClients
+			}
+			room, err := NewMemoryRoom(1, 1, options)
+			if err != nil {
+				t.Fatalf("failed to create room, %v", err)
+			}
+
+			var wg sync.WaitGroup
+			var mutex sync.Mutex
+			joined := 0
+			for i := 0; i < tt.joining; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					join := room.NewClient
+					if tt.spectators {
+						join = room.NewSpectator
+					}
+					_, err := join(newTestSession())
+					if err == nil {
+						mutex.Lock()
+						joined++
+						mutex.Unlock()
+						return
+					}
+					if _, full := err.(ErrRoomFull); !full {
+						t.Errorf("unexpected error joining room, %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			want := tt.joining
+			if int32(want) > tt.maxClients {
+				want = int(tt.maxClients)
+			}
+			if joined != want {
+				t.Errorf("got %d clients joined, want %d", joined, want)
+			}
+
+			connected, err := room.GetConnected()
+			if err != nil {
+				t.Fatalf("failed to get connected clients, %v", err)
+			}
+			ids := make(map[int32]bool)
+			for _, client := range connected {
+				if ids[client.Client.ID] {
+					t.Errorf("client ID %d given to more than one client", client.Client.ID)
+				}
+				ids[client.Client.ID] = true
+			}
+		})
+	}
+}
+
+func TestConcurrentRoomAccess(t *testing.T) {
+	manager := newTestManager()
+
+	room, err := manager.CreateRoom(Options{MaxClients: 100, ReplayBufferSize: 8, ReconnectGracePeriod: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("failed to create room, %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connected, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Errorf("failed to join room, %v", err)
+				return
+			}
+			id := connected.Client.ID
+			for j := 0; j < 10; j++ {
+				room.RecordMessage(id, []byte("message"))
+				room.SetMetadata(id, map[string]string{"name": "client"})
+				room.SetProperty("key", "value", nil)
+				room.GetInfo()
+				room.GetHost()
+			}
+			room.SetHost(&id)
+			room.RemoveClient(id)
+			room.ExistingClient(newTestSession(), id, connected.Client.Secret)
+			room.ForgetClient(id)
+		}()
+	}
+
+	// The reaper and API work on rooms while clients are using them
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				room.PruneDisconnected(time.Now())
+				manager.ListRooms()
+				manager.Summary()
+				manager.Export()
+				created, err := manager.CreateRoom(Options{MaxClients: 2})
+				if err != nil {
+					t.Errorf("failed to create room, %v", err)
+					return
+				}
+				info, _ := created.GetInfo()
+				manager.RotateInviteCode(info.ID)
+				manager.DeleteRoom(info.ID)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	connected, err := room.GetConnected()
+	if err != nil {
+		t.Fatalf("failed to get connected clients, %v", err)
+	}
+	if len(connected) != 0 {
+		t.Errorf("got %d clients still connected, want 0", len(connected))
+	}
+}
+
+func TestConcurrentQuickJoin(t *testing.T) {
+	manager := newTestManager()
+
+	const clients = 40
+	const maxClients = 4
+
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, err := manager.QuickJoin(newTestSession(), PublicRoomFilter{}, &Options{MaxClients: maxClients})
+			if err != nil {
+				t.Errorf("failed to quick join, %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rooms, err := manager.ListRooms()
+	if err != nil {
+		t.Fatalf("failed to list rooms, %v", err)
+	}
+
+	if len(rooms) != clients/maxClients {
+		t.Errorf("got %d rooms, want %d", len(rooms), clients/maxClients)
+	}
+
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			t.Fatalf("failed to get room info, %v", err)
+		}
+		if info.CurrentClients != maxClients {
+			t.Errorf("room %d has %d clients, want %d", info.ID, info.CurrentClients, maxClients)
+		}
+	}
+}
+
+func TestClosingRoomCannotBeJoined(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 4, MaxSpectators: 4})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	connected, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+	err = room.RemoveClient(connected.Client.ID)
+	if err != nil {
+		t.Fatalf("failed to disconnect client: %v", err)
+	}
+
+	room.SetStatus(StatusClosing)
+
+	tests := []struct {
+		name string
+		join func() error
+	}{
+		{name: "new client", join: func() error {
+			_, err := room.NewClient(newTestSession())
+			return err
+		}},
+		{name: "new spectator", join: func() error {
+			_, err := room.NewSpectator(newTestSession())
+			return err
+		}},
+		{name: "existing client", join: func() error {
+			_, err := room.ExistingClient(newTestSession(), connected.Client.ID, connected.Client.Secret)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.join().(ErrNoRoomFound); !ok {
+				t.Errorf("expected ErrNoRoomFound joining a closing room")
+			}
+		})
+	}
+}
+
+func TestDisconnectedHostKeepsHost(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	host, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+	_, err = room.SetHost(&host.Client.ID)
+	if err != nil {
+		t.Fatalf("failed to set host: %v", err)
+	}
+	err = room.RemoveClient(host.Client.ID)
+	if err != nil {
+		t.Fatalf("failed to disconnect client: %v", err)
+	}
+
+	connectedHost, err := room.GetHost()
+	if err != nil {
+		t.Fatalf("failed to get host: %v", err)
+	}
+	if connectedHost != nil {
+		t.Fatalf("expected no connected host while the host is disconnected")
+	}
+
+	isHost, err := room.IsHost(host.Client)
+	if err != nil {
+		t.Fatalf("failed to check host: %v", err)
+	}
+	if !isHost {
+		t.Fatalf("expected the disconnected client to still be host after reading the host")
+	}
+
+	reconnected, err := room.ExistingClient(newTestSession(), host.Client.ID, host.Client.Secret)
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+
+	connectedHost, err = room.GetHost()
+	if err != nil {
+		t.Fatalf("failed to get host: %v", err)
+	}
+	if connectedHost != reconnected {
+		t.Fatalf("expected the reconnected client to be host")
+	}
+}
+
+func TestPruneDisconnected(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		// after is how long after the clients disconnected the room is pruned
+		after  time.Duration
+		pruned []int
+	}{
+		{name: "no limits", options: Options{MaxClients: 4}, after: time.Hour, pruned: []int{}},
+		{
+			name:    "within grace period",
+			options: Options{MaxClients: 4, ReconnectGracePeriod: time.Minute},
+			after:   time.Second,
+			pruned:  []int{},
+		},
+		{
+			name:    "grace period passed",
+			options: Options{MaxClients: 4, ReconnectGracePeriod: time.Minute},
+			after:   time.Hour,
+			pruned:  []int{0, 1, 2},
+		},
+		{
+			name:    "earliest beyond limit",
+			options: Options{MaxClients: 4, MaxDisconnectedClients: 1},
+			after:   time.Second,
+			pruned:  []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, err := NewMemoryRoom(1, 1, tt.options)
+			if err != nil {
+				t.Fatalf("failed to create room: %v", err)
+			}
+
+			clients := []*sessionv1.Session{}
+			for i := 0; i < 3; i++ {
+				connected, err := room.NewClient(newTestSession())
+				if err != nil {
+					t.Fatalf("failed to join room: %v", err)
+				}
+				err = room.SetMetadata(connected.Client.ID, map[string]string{"team": "red"})
+				if err != nil {
+					t.Fatalf("failed to set metadata: %v", err)
+				}
+				err = room.RemoveClient(connected.Client.ID)
+				if err != nil {
+					t.Fatalf("failed to disconnect client: %v", err)
+				}
+				clients = append(clients, connected)
+			}
+
+			pruned, err := room.PruneDisconnected(time.Now().Add(tt.after))
+			if err != nil {
+				t.Fatalf("failed to prune disconnected clients: %v", err)
+			}
+			if len(pruned) != len(tt.pruned) {
+				t.Fatalf("got %d pruned clients, want %d", len(pruned), len(tt.pruned))
+			}
+
+			for i, index := range tt.pruned {
+				if pruned[i].ID != clients[index].Client.ID {
+					t.Errorf("pruned client %d: got ID %d, want %d", i, pruned[i].ID, clients[index].Client.ID)
+				}
+				if _, exists := room.Metadata[pruned[i].ID]; exists {
+					t.Errorf("pruned client %d: metadata was not forgotten", i)
+				}
+			}
+		})
+	}
+}
+
+func TestExistingClientGracePeriod(t *testing.T) {
+	tests := []struct {
+		name string
+		// ago is how long before reconnecting the client disconnected
+		ago     time.Duration
+		options Options
+		wantErr bool
+	}{
+		{name: "no grace period", ago: time.Hour, options: Options{MaxClients: 4}},
+		{
+			name:    "within grace period",
+			ago:     time.Second,
+			options: Options{MaxClients: 4, ReconnectGracePeriod: time.Minute},
+		},
+		{
+			name:    "grace period passed",
+			ago:     time.Hour,
+			options: Options{MaxClients: 4, ReconnectGracePeriod: time.Minute},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, err := NewMemoryRoom(1, 1, tt.options)
+			if err != nil {
+				t.Fatalf("failed to create room: %v", err)
+			}
+
+			connected, err := room.NewClient(newTestSession())
+			if err != nil {
+				t.Fatalf("failed to join room: %v", err)
+			}
+			err = room.RemoveClient(connected.Client.ID)
+			if err != nil {
+				t.Fatalf("failed to disconnect client: %v", err)
+			}
+			room.DisconnectedClients[0].DisconnectedAt = time.Now().Add(-tt.ago)
+
+			_, err = room.ExistingClient(newTestSession(), connected.Client.ID, connected.Client.Secret)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("failed to reconnect: %v", err)
+				}
+				return
+			}
+			if _, ok := err.(ErrNoMatchingClient); !ok {
+				t.Fatalf("expected ErrNoMatchingClient, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPruneDisconnectedForgetsHost(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 4, ReconnectGracePeriod: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	host, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+	_, err = room.SetHost(&host.Client.ID)
+	if err != nil {
+		t.Fatalf("failed to set host: %v", err)
+	}
+	err = room.RemoveClient(host.Client.ID)
+	if err != nil {
+		t.Fatalf("failed to disconnect client: %v", err)
+	}
+
+	_, err = room.PruneDisconnected(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to prune disconnected clients: %v", err)
+	}
+
+	if room.HostID != nil {
+		t.Errorf("expected the forgotten host to no longer be host, got host ID %d", *room.HostID)
+	}
+}
+
+func TestClientIDsAreNotReused(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 4, ReconnectGracePeriod: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	first, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+	second, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+
+	// Forget the client with the highest ID, which must not be given to the next client to join
+	err = room.RemoveClient(second.Client.ID)
+	if err != nil {
+		t.Fatalf("failed to disconnect client: %v", err)
+	}
+	_, err = room.PruneDisconnected(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to prune disconnected clients: %v", err)
+	}
+
+	third, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatalf("failed to join room: %v", err)
+	}
+	if third.Client.ID == first.Client.ID || third.Client.ID == second.Client.ID {
+		t.Fatalf("expected a new client ID, got reused ID %d", third.Client.ID)
+	}
+
+	// The next ID is kept when the room is restored from a snapshot
+	restored, err := NewMemoryRoom(1, 1, Options{MaxClients: 4})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	err = restored.Restore(room.Snapshot())
+	if err != nil {
+		t.Fatalf("failed to restore room: %v", err)
+	}
+	if restored.NextClientID != room.NextClientID {
+		t.Errorf("got restored next client ID %d, want %d", restored.NextClientID, room.NextClientID)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	// MaxMetadataEntries is the maximum number of entries a client's metadata can have
+	MaxMetadataEntries = 16
+	// MaxMetadataKeyLength is the maximum length in bytes of a key in a client's metadata
+	MaxMetadataKeyLength = 64
+	// MaxMetadataValueLength is the maximum length in bytes of a value in a client's metadata
+	MaxMetadataValueLength = 256
+)
+
+// ValidateMetadata checks that a client's metadata is within the size limits, with non empty keys
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataEntries {
+		return ErrInvalidMetadata{
+			Message: fmt.Sprintf("Metadata can have at most %d entries, %d provided", MaxMetadataEntries, len(metadata)),
+		}
+	}
+
+	for key, value := range metadata {
+		if key == "" {
+			return ErrInvalidMetadata{
+				Message: "Metadata keys cannot be empty",
+			}
+		}
+
+		if len(key) > MaxMetadataKeyLength || !utf8.ValidString(key) {
+			return ErrInvalidMetadata{
+				Message: fmt.Sprintf("Metadata key '%s' is invalid, keys must be valid UTF-8 of at most %d bytes", key,
+					MaxMetadataKeyLength),
+			}
+		}
+
+		if len(value) > MaxMetadataValueLength || !utf8.ValidString(value) {
+			return ErrInvalidMetadata{
+				Message: fmt.Sprintf("Metadata value for key '%s' is invalid, values must be valid UTF-8 of at most %d bytes",
+					key, MaxMetadataValueLength),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/relay"
+)
+
+// Role defines a client's role in a room, which determines what the client is permitted to do
+type Role int32
+
+func (r Role) String() string {
+	return [...]string{"PLAYER", "MODERATOR", "SPECTATOR", "HOST"}[r]
+}
+
+const (
+	// RolePlayer is the role every client has when it joins a room
+	RolePlayer Role = iota
+	// RoleModerator is a role for clients that help the host manage the room
+	RoleModerator
+	// RoleSpectator is a role for clients that are only watching the room
+	RoleSpectator
+	// RoleHost is the role of the room's host, it cannot be granted directly and is only held by the client that is
+	// the room's host
+	RoleHost
+)
+
+// Outranks determines if a role has more authority in a room than another role, spectators have the least authority,
+// followed by players, moderators and then the host
+func (r Role) Outranks(other Role) bool {
+	return r.rank() > other.rank()
+}
+
+func (r Role) rank() int {
+	return [...]int{1, 2, 0, 3}[r]
+}
+
+// ParseRole converts a role name into a role
+func ParseRole(name string) (Role, error) {
+	for _, role := range []Role{RolePlayer, RoleModerator, RoleSpectator, RoleHost} {
+		if role.String() == name {
+			return role, nil
+		}
+	}
+	return RolePlayer, ErrInvalidRole{
+		Message: fmt.Sprintf("Unknown role '%s', must be one of %s, %s, %s or %s", name, RolePlayer, RoleModerator,
+			RoleSpectator, RoleHost),
+	}
+}
+
+// Permission defines an action in a room that a client must be permitted to perform
+type Permission int32
+
+func (p Permission) String() string {
+	return [...]string{"BROADCAST", "TARGET", "KICK", "GRANT_HOST", "GRANT_ROLE", "LIST", "SET_PROPERTIES"}[p]
+}
+
+const (
+	// PermissionBroadcast permits relaying messages to every client in the room, using the BROADCAST and EXCEPT relay
+	// types
+	PermissionBroadcast Permission = iota
+	// PermissionTarget permits relaying messages to specific clients in the room, using the TARGET and MULTICAST relay
+	// types
+	PermissionTarget
+	// PermissionKick permits removing other clients from the room
+	PermissionKick
+	// PermissionGrantHost permits transferring the room's host to another client
+	PermissionGrantHost
+	// PermissionGrantRole permits changing the role of other clients in the room
+	PermissionGrantRole
+	// PermissionList permits listing the clients in the room
+	PermissionList
+	// PermissionSetProperties permits setting and deleting the room's properties
+	PermissionSetProperties
+)
+
+// ParsePermission converts a permission name into a permission
+func ParsePermission(name string) (Permission, error) {
+	for _, permission := range []Permission{PermissionBroadcast, PermissionTarget, PermissionKick, PermissionGrantHost,
+		PermissionGrantRole, PermissionList, PermissionSetProperties} {
+		if permission.String() == name {
+			return permission, nil
+		}
+	}
+	return PermissionBroadcast, ErrInvalidPermissions{
+		Message: fmt.Sprintf("Unknown permission '%s', must be one of %s, %s, %s, %s, %s, %s or %s", name,
+			PermissionBroadcast, PermissionTarget, PermissionKick, PermissionGrantHost, PermissionGrantRole,
+			PermissionList, PermissionSetProperties),
+	}
+}
+
+// Permissions defines what clients in each role are permitted to do in a room, the host is always permitted to do
+// everything so its permissions cannot be configured
+type Permissions map[Role][]Permission
+
+// DefaultPermissions returns the permissions rooms use unless configured otherwise, moderators can message and kick
+// clients, while every client can list the clients in the room. Clients are only moderators if granted the role, so
+// by default only the host can message other clients, kick and grant host or roles
+func DefaultPermissions() Permissions {
+	return Permissions{
+		RoleModerator: {PermissionBroadcast, PermissionTarget, PermissionKick, PermissionList},
+		RolePlayer:    {PermissionList},
+		RoleSpectator: {PermissionList},
+	}
+}
+
+// Allows determines if clients in a role are permitted to do something
+func (p Permissions) Allows(role Role, permission Permission) bool {
+	if role == RoleHost {
+		return true
+	}
+	for _, allowed := range p[role] {
+		if allowed == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the permissions granted to each role, by role name
+func (p Permissions) Names() map[string][]string {
+	names := make(map[string][]string, len(p))
+	for role, permissions := range p {
+		permissionNames := make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			permissionNames = append(permissionNames, permission.String())
+		}
+		names[role.String()] = permissionNames
+	}
+	return names
+}
+
+// validate checks that the permissions only include roles and permissions that exist, and do not try to configure
+// the host's permissions
+func (p Permissions) validate() error {
+	for role, permissions := range p {
+		if role < RolePlayer || role >= RoleHost {
+			return ErrInvalidPermissions{
+				Message: fmt.Sprintf("Cannot configure permissions for role %d", role),
+			}
+		}
+		for _, permission := range permissions {
+			if permission < PermissionBroadcast || permission > PermissionSetProperties {
+				return ErrInvalidPermissions{
+					Message: fmt.Sprintf("Unknown permission %d", permission),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParsePermissions converts the names of the permissions granted to each role into permissions, any roles not
+// included keep their default permissions
+func ParsePermissions(names map[string][]string) (Permissions, error) {
+	permissions := DefaultPermissions()
+	for roleName, permissionNames := range names {
+		role, err := ParseRole(roleName)
+		if err != nil {
+			return nil, ErrInvalidPermissions{
+				Message: err.(ErrInvalidRole).Message,
+			}
+		}
+
+		if role == RoleHost {
+			return nil, ErrInvalidPermissions{
+				Message: fmt.Sprintf("Cannot configure permissions for the %s role, the host is permitted to do everything",
+					RoleHost),
+			}
+		}
+
+		rolePermissions := make([]Permission, 0, len(permissionNames))
+		for _, permissionName := range permissionNames {
+			permission, err := ParsePermission(permissionName)
+			if err != nil {
+				return nil, err
+			}
+			rolePermissions = append(rolePermissions, permission)
+		}
+		permissions[role] = rolePermissions
+	}
+	return permissions, nil
+}
+
+// RelayPolicy defines the relay types that clients other than the host may use, in addition to HOST which every
+// client other than a spectator may always use.
+//
+// Deprecated: relay policies are replaced by permissions, a relay policy is converted into the BROADCAST and TARGET
+// permissions of the PLAYER and MODERATOR roles; BROADCAST or EXCEPT grant BROADCAST, while TARGET or MULTICAST grant
+// TARGET
+type RelayPolicy []relay.Relay_RelayType
+
+// ParseRelayPolicy converts a list of relay type names into a relay policy, no names results in a policy that only
+// allows clients to relay messages to the host
+func ParseRelayPolicy(names []string) (RelayPolicy, error) {
+	policy := make(RelayPolicy, 0, len(names))
+	for _, name := range names {
+		relayType, exists := relay.Relay_RelayType_value[name]
+		if !exists {
+			return nil, ErrInvalidRelayPolicy{
+				Message: fmt.Sprintf("Unknown relay type '%s', must be one of %s, %s, %s or %s", name,
+					relay.Relay_BROADCAST, relay.Relay_TARGET, relay.Relay_MULTICAST, relay.Relay_EXCEPT),
+			}
+		}
+		policy = append(policy, relay.Relay_RelayType(relayType))
+	}
+	return policy, nil
+}
+
+// grant adds the permissions needed to use the relay policy's relay types to the PLAYER and MODERATOR roles
+func (p RelayPolicy) grant(permissions Permissions) {
+	for _, relayType := range p {
+		var permission Permission
+		switch relayType {
+		case relay.Relay_BROADCAST, relay.Relay_EXCEPT:
+			permission = PermissionBroadcast
+		case relay.Relay_TARGET, relay.Relay_MULTICAST:
+			permission = PermissionTarget
+		default:
+			continue
+		}
+		for _, role := range []Role{RolePlayer, RoleModerator} {
+			if !permissions.Allows(role, permission) {
+				permissions[role] = append(permissions[role], permission)
+			}
+		}
+	}
+}
+
+// RelayTypes returns the names of the relay types, other than HOST, that clients in the PLAYER role are permitted to
+// use, as reported by the deprecated relay policy
+func (p Permissions) RelayTypes() []string {
+	names := make([]string, 0)
+	if p.Allows(RolePlayer, PermissionBroadcast) {
+		names = append(names, relay.Relay_BROADCAST.String(), relay.Relay_EXCEPT.String())
+	}
+	if p.Allows(RolePlayer, PermissionTarget) {
+		names = append(names, relay.Relay_TARGET.String(), relay.Relay_MULTICAST.String())
+	}
+	return names
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"testing"
+)
+
+func TestPermissionsAllows(t *testing.T) {
+	permissions := DefaultPermissions()
+
+	tests := []struct {
+		name       string
+		role       Role
+		permission Permission
+		allowed    bool
+	}{
+		{name: "host kicks", role: RoleHost, permission: PermissionKick, allowed: true},
+		{name: "host grants host", role: RoleHost, permission: PermissionGrantHost, allowed: true},
+		{name: "moderator broadcasts", role: RoleModerator, permission: PermissionBroadcast, allowed: true},
+		{name: "moderator kicks", role: RoleModerator, permission: PermissionKick, allowed: true},
+		{name: "moderator grants role", role: RoleModerator, permission: PermissionGrantRole},
+		{name: "player lists", role: RolePlayer, permission: PermissionList, allowed: true},
+		{name: "player broadcasts", role: RolePlayer, permission: PermissionBroadcast},
+		{name: "spectator lists", role: RoleSpectator, permission: PermissionList, allowed: true},
+		{name: "spectator targets", role: RoleSpectator, permission: PermissionTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed := permissions.Allows(tt.role, tt.permission); allowed != tt.allowed {
+				t.Errorf("got allowed %t, want %t", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name  string
+		names map[string][]string
+		role  Role
+		want  []Permission
+		err   bool
+	}{
+		{
+			name: "roles not included keep defaults",
+			names: map[string][]string{
+				"PLAYER": {"BROADCAST", "LIST"},
+			},
+			role: RoleModerator,
+			want: DefaultPermissions()[RoleModerator],
+		},
+		{
+			name: "configured role",
+			names: map[string][]string{
+				"PLAYER": {"BROADCAST", "LIST"},
+			},
+			role: RolePlayer,
+			want: []Permission{PermissionBroadcast, PermissionList},
+		},
+		{
+			name: "role with no permissions",
+			names: map[string][]string{
+				"SPECTATOR": {},
+			},
+			role: RoleSpectator,
+			want: []Permission{},
+		},
+		{name: "unknown role", names: map[string][]string{"ADMIN": {"LIST"}}, err: true},
+		{name: "host role", names: map[string][]string{"HOST": {"LIST"}}, err: true},
+		{name: "unknown permission", names: map[string][]string{"PLAYER": {"BAN"}}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, err := ParsePermissions(tt.names)
+			if tt.err {
+				if _, ok := err.(ErrInvalidPermissions); !ok {
+					t.Fatalf("expected an invalid permissions error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := permissions[tt.role]
+			if len(got) != len(tt.want) {
+				t.Fatalf("got permissions %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got permissions %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = room.SetHost(&host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player, err := room.NewClient(newTestSession())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		clientID int32
+		role     Role
+		err      error
+	}{
+		{name: "moderator", clientID: player.Client.ID, role: RoleModerator},
+		{name: "back to player", clientID: player.Client.ID, role: RolePlayer},
+		{name: "host", clientID: player.Client.ID, role: RoleHost, err: ErrInvalidRole{}},
+		{name: "spectator", clientID: player.Client.ID, role: RoleSpectator, err: ErrInvalidRole{}},
+		{name: "unknown role", clientID: player.Client.ID, role: Role(42), err: ErrInvalidRole{}},
+		{name: "unknown client", clientID: -1, role: RoleModerator, err: ErrNoMatchingClient{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := room.SetRole(tt.clientID, tt.role)
+			switch tt.err.(type) {
+			case nil:
+				if err != nil {
+					t.Fatal(err)
+				}
+				role, err := room.GetRole(tt.clientID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if role != tt.role {
+					t.Errorf("got role %s, want %s", role, tt.role)
+				}
+			case ErrInvalidRole:
+				if _, ok := err.(ErrInvalidRole); !ok {
+					t.Errorf("expected an invalid role error, got %v", err)
+				}
+			case ErrNoMatchingClient:
+				if _, ok := err.(ErrNoMatchingClient); !ok {
+					t.Errorf("expected a no matching client error, got %v", err)
+				}
+			}
+		})
+	}
+
+	role, err := room.GetRole(host.Client.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role != RoleHost {
+		t.Errorf("expected the host to have the %s role, got %s", RoleHost, role)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// MaxProperties is the maximum number of properties a room can have
+	MaxProperties = 64
+	// MaxPropertyKeyLength is the maximum length in bytes of a property's key
+	MaxPropertyKeyLength = 64
+	// MaxPropertyValueLength is the maximum length in bytes of a property's value
+	MaxPropertyValueLength = 1024
+)
+
+// Property is a value in a room's shared state, held by the server so that it survives host migration. Each change to
+// a room's properties is given a new version, which can be used to only change a property if it has not changed since
+// it was last read
+type Property struct {
+	Value   string `json:"value"`
+	Version uint64 `json:"version"`
+}
+
+// validateProperty checks that a property's key and value are within the size limits, with a non empty key
+func validateProperty(key string, value string) error {
+	if key == "" || len(key) > MaxPropertyKeyLength || !utf8.ValidString(key) {
+		return ErrInvalidProperty{
+			Message: fmt.Sprintf("Property key '%s' is invalid, keys must be non empty valid UTF-8 of at most %d bytes",
+				key, MaxPropertyKeyLength),
+		}
+	}
+
+	if len(value) > MaxPropertyValueLength || !utf8.ValidString(value) {
+		return ErrInvalidProperty{
+			Message: fmt.Sprintf("Value for property '%s' is invalid, values must be valid UTF-8 of at most %d bytes",
+				key, MaxPropertyValueLength),
+		}
+	}
+
+	return nil
+}
+
+// checkVersion checks that a property is at the version expected, if no version is expected the property can be at
+// any version, while version zero expects the property to not exist
+func checkVersion(key string, property Property, exists bool, expectedVersion *uint64) error {
+	if expectedVersion == nil {
+		return nil
+	}
+
+	if !exists && *expectedVersion == 0 {
+		return nil
+	}
+
+	if exists && property.Version == *expectedVersion {
+		return nil
+	}
+
+	current := uint64(0)
+	if exists {
+		current = property.Version
+	}
+
+	return ErrPropertyConflict{
+		Message: fmt.Sprintf("Property '%s' is at version %d, expected version %d", key, current, *expectedVersion),
+	}
+}
+
+// initialProperties builds the properties a room starts with from their values, each property is given its own
+// version in key order, returning the properties and the version of the latest one
+func initialProperties(values map[string]string) (map[string]Property, uint64) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	properties := make(map[string]Property, len(values))
+	version := uint64(0)
+	for _, key := range keys {
+		version++
+		properties[key] = Property{
+			Value:   values[key],
+			Version: version,
+		}
+	}
+	return properties, version
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetProperty(t *testing.T) {
+	version := func(version uint64) *uint64 {
+		return &version
+	}
+
+	tests := []struct {
+		name            string
+		key             string
+		value           string
+		expectedVersion *uint64
+		version         uint64
+		err             error
+	}{
+		{name: "new property", key: "mode", value: "ranked", version: 2},
+		{name: "existing property", key: "map", value: "forest", version: 2},
+		{name: "new property expecting it not to exist", key: "mode", value: "ranked", expectedVersion: version(0),
+			version: 2},
+		{name: "existing property at expected version", key: "map", value: "forest", expectedVersion: version(1),
+			version: 2},
+		{name: "existing property expecting it not to exist", key: "map", value: "forest", expectedVersion: version(0),
+			err: ErrPropertyConflict{}},
+		{name: "existing property at other version", key: "map", value: "forest", expectedVersion: version(5),
+			err: ErrPropertyConflict{}},
+		{name: "new property at expected version", key: "mode", value: "ranked", expectedVersion: version(1),
+			err: ErrPropertyConflict{}},
+		{name: "empty key", key: "", value: "ranked", err: ErrInvalidProperty{}},
+		{name: "key too long", key: strings.Repeat("k", MaxPropertyKeyLength+1), value: "ranked",
+			err: ErrInvalidProperty{}},
+		{name: "value too long", key: "mode", value: strings.Repeat("v", MaxPropertyValueLength+1),
+			err: ErrInvalidProperty{}},
+		{name: "invalid UTF-8 value", key: "mode", value: "\xff", err: ErrInvalidProperty{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, err := NewMemoryRoom(1, 1, Options{MaxClients: 2, Properties: map[string]string{"map": "desert"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			property, err := room.SetProperty(tt.key, tt.value, tt.expectedVersion)
+			if tt.err != nil {
+				if fmt.Sprintf("%T", err) != fmt.Sprintf("%T", tt.err) {
+					t.Fatalf("got error %T (%v), want %T", err, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if property.Value != tt.value || property.Version != tt.version {
+				t.Fatalf("got property %+v, want value '%s' at version %d", property, tt.value, tt.version)
+			}
+
+			properties, err := room.GetProperties()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if properties[tt.key] != property {
+				t.Errorf("got stored property %+v, want %+v", properties[tt.key], property)
+			}
+		})
+	}
+}
+
+func TestSetPropertyLimit(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < MaxProperties; i++ {
+		_, err = room.SetProperty(fmt.Sprintf("key-%d", i), "value", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = room.SetProperty("one-too-many", "value", nil)
+	if _, ok := err.(ErrInvalidProperty); !ok {
+		t.Fatalf("expected an invalid property error once the room is full of properties, got %v", err)
+	}
+
+	// Existing properties can still be changed
+	_, err = room.SetProperty("key-0", "changed", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteProperty(t *testing.T) {
+	version := func(version uint64) *uint64 {
+		return &version
+	}
+
+	tests := []struct {
+		name            string
+		key             string
+		expectedVersion *uint64
+		err             error
+	}{
+		{name: "existing property", key: "map"},
+		{name: "existing property at expected version", key: "map", expectedVersion: version(1)},
+		{name: "existing property at other version", key: "map", expectedVersion: version(2),
+			err: ErrPropertyConflict{}},
+		{name: "missing property", key: "mode", err: ErrNoMatchingProperty{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, err := NewMemoryRoom(1, 1, Options{MaxClients: 2, Properties: map[string]string{"map": "desert"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deletedVersion, err := room.DeleteProperty(tt.key, tt.expectedVersion)
+			if tt.err != nil {
+				if fmt.Sprintf("%T", err) != fmt.Sprintf("%T", tt.err) {
+					t.Fatalf("got error %T (%v), want %T", err, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deletedVersion != 2 {
+				t.Errorf("got deleted at version %d, want 2", deletedVersion)
+			}
+
+			info, err := room.GetInfo()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, exists := info.Properties[tt.key]; exists {
+				t.Errorf("expected property '%s' to be removed from the room's info", tt.key)
+			}
+		})
+	}
+}
+
+func TestNewMemoryRoomProperties(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{
+		MaxClients: 2,
+		Properties: map[string]string{"mode": "ranked", "map": "desert"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	properties, err := room.GetProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Initial properties are versioned in key order
+	if properties["map"] != (Property{Value: "desert", Version: 1}) ||
+		properties["mode"] != (Property{Value: "ranked", Version: 2}) {
+		t.Fatalf("got properties %+v", properties)
+	}
+
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Properties["map"] != "desert" || info.Properties["mode"] != "ranked" {
+		t.Errorf("expected the room's info to include its properties, got %v", info.Properties)
+	}
+
+	tooMany := make(map[string]string, MaxProperties+1)
+	for i := 0; i <= MaxProperties; i++ {
+		tooMany[fmt.Sprintf("key-%d", i)] = "value"
+	}
+	_, err = NewMemoryRoom(1, 1, Options{MaxClients: 2, Properties: tooMany})
+	if _, ok := err.(ErrInvalidProperty); !ok {
+		t.Errorf("expected an invalid property error for too many initial properties, got %v", err)
+	}
+}
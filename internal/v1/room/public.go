@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+)
+
+const (
+	// MaxNameLength is the maximum length in bytes of a room's name
+	MaxNameLength = 64
+	// MaxTags is the maximum number of tags a room can have
+	MaxTags = 8
+	// MaxTagLength is the maximum length in bytes of each of a room's tags
+	MaxTagLength = 32
+)
+
+const (
+	// DefaultPublicRoomLimit is the number of public rooms listed in each page if no limit is provided
+	DefaultPublicRoomLimit = 20
+	// MaxPublicRoomLimit is the maximum number of public rooms that can be listed in each page
+	MaxPublicRoomLimit = 100
+)
+
+// PublicRoomFilter defines which public rooms should be listed, and which page of the matching rooms to return
+type PublicRoomFilter struct {
+	// Name only matches rooms with a name containing it, ignoring case
+	Name string
+	// Tags only matches rooms with every one of the tags
+	Tags []string
+	// Properties only matches rooms with properties set to each of the values
+	Properties map[string]string
+	// Offset is the number of matching rooms to skip
+	Offset int32
+	// Limit is the maximum number of rooms to return, zero for the default limit
+	Limit int32
+}
+
+// validateListing checks that a room's name and tags are within the size limits
+func validateListing(name string, tags []string) error {
+	if len(name) > MaxNameLength || !utf8.ValidString(name) {
+		return ErrInvalidListing{
+			Message: fmt.Sprintf("The room's name must be valid UTF-8 of at most %d bytes", MaxNameLength),
+		}
+	}
+
+	if len(tags) > MaxTags {
+		return ErrInvalidListing{
+			Message: fmt.Sprintf("The room can have at most %d tags, %d provided", MaxTags, len(tags)),
+		}
+	}
+
+	for _, tag := range tags {
+		if tag == "" || len(tag) > MaxTagLength || !utf8.ValidString(tag) {
+			return ErrInvalidListing{
+				Message: fmt.Sprintf("Tag '%s' is invalid, tags must be non empty valid UTF-8 of at most %d bytes", tag,
+					MaxTagLength),
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListPublicRooms lists the public rooms that can be joined that match the filter, ordered by room ID, returning the
+// page of rooms requested along with the total number of matching rooms. Only public information about each room is
+// included, the room's secret is never included
+func ListPublicRooms(rooms []Room, filter PublicRoomFilter) (*api.PublicRoomList, error) {
+	if filter.Offset < 0 || filter.Limit < 0 {
+		return nil, ErrInvalidFilter{
+			Message: "The offset and limit must be zero or more",
+		}
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultPublicRoomLimit
+	}
+	if limit > MaxPublicRoomLimit {
+		limit = MaxPublicRoomLimit
+	}
+
+	matching := []*api.PublicRoomInfo{}
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Public || info.RoomStatus != StatusRunning.String() || !joinable(info) || !filter.matches(info) {
+			continue
+		}
+
+		matching = append(matching, &api.PublicRoomInfo{
+			ID:                info.ID,
+			Name:              info.Name,
+			Tags:              info.Tags,
+			MaxClients:        info.MaxClients,
+			CurrentClients:    info.CurrentClients,
+			MaxSpectators:     info.MaxSpectators,
+			CurrentSpectators: info.CurrentSpectators,
+			Properties:        info.Properties,
+		})
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].ID < matching[j].ID
+	})
+
+	page := []*api.PublicRoomInfo{}
+	if filter.Offset < int32(len(matching)) {
+		end := filter.Offset + limit
+		if end > int32(len(matching)) {
+			end = int32(len(matching))
+		}
+		page = matching[filter.Offset:end]
+	}
+
+	return &api.PublicRoomList{
+		Rooms:  page,
+		Total:  int32(len(matching)),
+		Offset: filter.Offset,
+	}, nil
+}
+
+// joinable determines if a room has a free slot for either a client or a spectator
+func joinable(info *api.RoomInfo) bool {
+	return info.CurrentClients+info.ReservedClients < info.MaxClients || info.CurrentSpectators < info.MaxSpectators
+}
+
+// matches determines if a room matches the filter
+func (f PublicRoomFilter) matches(info *api.RoomInfo) bool {
+	if f.Name != "" && !strings.Contains(strings.ToLower(info.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+
+	for _, tag := range f.Tags {
+		found := false
+		for _, roomTag := range info.Tags {
+			if roomTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for key, value := range f.Properties {
+		roomValue, exists := info.Properties[key]
+		if !exists || roomValue != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"sort"
+
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+)
+
+// QuickJoiner defines a contract for room managers that can place a client into the best fitting public room with a
+// free slot in a single step, creating a room for the client if there is none
+type QuickJoiner interface {
+	QuickJoin(connected *sessionv1.Session, filter PublicRoomFilter, options *Options) (*sessionv1.Session, Room, bool, error)
+}
+
+// QuickJoin connects a client to the fullest public room matching the filter that still has a free slot, if no room
+// matches a public room is created with the options provided, or if no options are provided an error is returned.
+// Returns the room the client joined, and if the room was created for the client
+func (m *MemoryManager) QuickJoin(connected *sessionv1.Session, filter PublicRoomFilter, options *Options) (*sessionv1.Session, Room, bool, error) {
+	m.quickJoinMutex.Lock()
+	defer m.quickJoinMutex.Unlock()
+
+	rooms, err := m.ListRooms()
+	if err != nil {
+		return connected, nil, false, err
+	}
+
+	return quickJoin(rooms, connected, filter, options, m.CreateRoom)
+}
+
+// quickJoin tries each of the public rooms matching the filter in order of best fit until the client is connected to
+// one, if no room has a free slot a public room is created with the options provided using the create function
+func quickJoin(rooms []Room, connected *sessionv1.Session, filter PublicRoomFilter, options *Options,
+	create func(options Options) (Room, error)) (*sessionv1.Session, Room, bool, error) {
+	candidates, err := bestFit(rooms, filter)
+	if err != nil {
+		return connected, nil, false, err
+	}
+
+	for _, candidate := range candidates {
+		connected, err = candidate.NewClient(connected)
+		if err == nil {
+			return connected, candidate, false, nil
+		}
+		if _, full := err.(ErrRoomFull); !full {
+			return connected, nil, false, err
+		}
+		// Room filled up since it was checked, try the next best fit
+	}
+
+	if options == nil {
+		return connected, nil, false, ErrNoRoomFound{
+			Message: "No public room with a free slot matches",
+		}
+	}
+
+	createOptions := *options
+	createOptions.Public = true
+	room, err := create(createOptions)
+	if err != nil {
+		return connected, nil, false, err
+	}
+
+	connected, err = room.NewClient(connected)
+	if err != nil {
+		return connected, nil, false, err
+	}
+
+	return connected, room, true, nil
+}
+
+// bestFit returns the public rooms matching the filter that have a free client slot and no password, fullest first.
+// Rooms with fewer free slots are fuller, with ties broken by the number of connected clients and then by room ID
+func bestFit(rooms []Room, filter PublicRoomFilter) ([]Room, error) {
+	candidates := []Room{}
+	infos := make(map[Room]*api.RoomInfo)
+	for _, room := range rooms {
+		if _, remote := room.(*RemoteRoom); remote {
+			// Rooms owned by other nodes cannot be joined from this node
+			continue
+		}
+
+		info, err := room.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Public || info.RoomStatus != StatusRunning.String() || freeSlots(info) <= 0 || !filter.matches(info) {
+			continue
+		}
+
+		if info.PasswordProtected {
+			// Password protected rooms can only be joined by players that know the password
+			continue
+		}
+
+		candidates = append(candidates, room)
+		infos[room] = info
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := infos[candidates[i]], infos[candidates[j]]
+		if freeSlots(a) != freeSlots(b) {
+			return freeSlots(a) < freeSlots(b)
+		}
+		if a.CurrentClients != b.CurrentClients {
+			return a.CurrentClients > b.CurrentClients
+		}
+		return a.ID < b.ID
+	})
+
+	return candidates, nil
+}
+
+// freeSlots returns the number of client slots in a room that are not taken or reserved
+func freeSlots(info *api.RoomInfo) int32 {
+	return info.MaxClients - info.CurrentClients - info.ReservedClients
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"time"
+)
+
+// RelayAllowance tracks how many messages a client can still relay under a room's relay rate limit. The allowance
+// refills continuously at the rate limit, up to one second's worth of messages, allowing short bursts
+type RelayAllowance struct {
+	Remaining float64
+	UpdatedAt time.Time
+}
+
+// AllowRelay determines if a client can relay a message at the time provided under the room's relay rate limit,
+// using up some of the client's allowance if so. Rooms without a rate limit always allow relaying
+func (r *MemoryRoom) AllowRelay(clientID int32, now time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.RelayRateLimit <= 0 {
+		return true, nil
+	}
+
+	limit := float64(r.RelayRateLimit)
+
+	allowance, exists := r.RelayAllowances[clientID]
+	if !exists {
+		allowance = &RelayAllowance{
+			Remaining: limit,
+			UpdatedAt: now,
+		}
+		r.RelayAllowances[clientID] = allowance
+	}
+
+	allowance.Remaining += now.Sub(allowance.UpdatedAt).Seconds() * limit
+	if allowance.Remaining > limit {
+		allowance.Remaining = limit
+	}
+	allowance.UpdatedAt = now
+
+	if allowance.Remaining < 1 {
+		return false, nil
+	}
+
+	allowance.Remaining--
+	return true, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAllowRelay(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name  string
+		limit int32
+		// sends are the offsets from the start that the client relays a message at
+		sends []time.Duration
+		want  []bool
+	}{
+		{name: "no limit", limit: 0, sends: []time.Duration{0, 0, 0}, want: []bool{true, true, true}},
+		{name: "burst up to limit", limit: 2, sends: []time.Duration{0, 0, 0}, want: []bool{true, true, false}},
+		{
+			name:  "refills over time",
+			limit: 2,
+			sends: []time.Duration{0, 0, 0, 500 * time.Millisecond, 500 * time.Millisecond},
+			want:  []bool{true, true, false, true, false},
+		},
+		{
+			name:  "refill capped at one second",
+			limit: 1,
+			sends: []time.Duration{0, 10 * time.Second, 10 * time.Second},
+			want:  []bool{true, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, err := NewMemoryRoom(1, 1, Options{MaxClients: 2, RelayRateLimit: tt.limit})
+			if err != nil {
+				t.Fatalf("failed to create room, %v", err)
+			}
+
+			for i, offset := range tt.sends {
+				allowed, err := room.AllowRelay(0, start.Add(offset))
+				if err != nil {
+					t.Fatalf("failed to check relay allowance, %v", err)
+				}
+				if allowed != tt.want[i] {
+					t.Errorf("message %d: got allowed %t, want %t", i, allowed, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAllowRelayConcurrentClients(t *testing.T) {
+	room, err := NewMemoryRoom(1, 1, Options{MaxClients: 10, RelayRateLimit: 5})
+	if err != nil {
+		t.Fatalf("failed to create room, %v", err)
+	}
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	allowed := 0
+	for clientID := int32(0); clientID < 10; clientID++ {
+		wg.Add(1)
+		go func(clientID int32) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				ok, err := room.AllowRelay(clientID, now)
+				if err != nil {
+					t.Errorf("failed to check relay allowance, %v", err)
+					return
+				}
+				if ok {
+					mutex.Lock()
+					allowed++
+					mutex.Unlock()
+				}
+			}
+		}(clientID)
+	}
+	wg.Wait()
+
+	if allowed != 50 {
+		t.Errorf("got %d messages allowed, want 50", allowed)
+	}
+}
@@ -0,0 +1,993 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gomodule/redigo/redis"
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+	clientv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	snapshotv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
+)
+
+const (
+	redisRoomsKey        = "jamjar-relay:rooms"
+	redisRoomKeyFormat   = "jamjar-relay:room:%d"
+	redisInviteKeyFormat = "jamjar-relay:invite:%s"
+	redisTemplatesKey    = "jamjar-relay:templates"
+	redisMaxIDAttempts   = 10
+)
+
+const (
+	redisOwnerField      = "owner"
+	redisSecretHashField = "secret_hash"
+	redisMaxClientsField = "max_clients"
+	redisInviteCodeField = "invite_code"
+	redisPublicField     = "public"
+	redisNameField       = "name"
+	redisTagsField       = "tags"
+)
+
+// NewRedisManager creates a new room manager that shares room metadata between relay server nodes using a Redis
+// compatible store, rooms are created and run using the local room manager, with the node address provided used to
+// redirect clients to this node. Room metadata expires after the TTL provided unless it is refreshed
+func NewRedisManager(local Manager, pool *redis.Pool, nodeAddress string, roomTTL time.Duration) *RedisManager {
+	return &RedisManager{
+		Local:       local,
+		Pool:        pool,
+		NodeAddress: nodeAddress,
+		RoomTTL:     roomTTL,
+	}
+}
+
+// RedisManager manages rooms across multiple relay server nodes, storing room metadata and ownership in a Redis
+// compatible store. Rooms owned by this node are managed by the local room manager, while rooms owned by other nodes
+// are represented as remote rooms that redirect clients to the owning node
+type RedisManager struct {
+	Local       Manager
+	Pool        *redis.Pool
+	NodeAddress string
+	RoomTTL     time.Duration
+
+	// quickJoinMutex makes sure only one client is quick joining at a time
+	quickJoinMutex sync.Mutex
+}
+
+// GetRoom retrieves a room specified by an ID, checking the local room manager before the shared store
+func (m *RedisManager) GetRoom(id int32) (Room, error) {
+	room, err := m.Local.GetRoom(id)
+	if err == nil {
+		return room, nil
+	}
+	if _, ok := err.(ErrNoRoomFound); !ok {
+		return nil, err
+	}
+
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	remote, err := m.getRemoteRoom(conn, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if remote == nil {
+		return nil, ErrNoRoomFound{
+			Message: fmt.Sprintf("No room found with the ID %d", id),
+		}
+	}
+
+	return remote, nil
+}
+
+// DeleteRoom deletes a room specified by an ID, rooms owned by other nodes cannot be deleted by this node
+func (m *RedisManager) DeleteRoom(id int32) error {
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	remote, err := m.getRemoteRoom(conn, id)
+	if err != nil {
+		return err
+	}
+
+	if remote != nil {
+		return ErrRoomOnOtherNode{
+			Message: fmt.Sprintf("Room with ID %d is owned by the node at %s", id, remote.Address),
+			Address: remote.Address,
+		}
+	}
+
+	err = m.unregister(conn, id)
+	if err != nil {
+		return err
+	}
+
+	return m.Local.DeleteRoom(id)
+}
+
+// CreateRoom creates a new room using the local room manager, registering it in the shared store with this node as
+// its owner
+func (m *RedisManager) CreateRoom(options Options) (Room, error) {
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	for attempt := 0; attempt < redisMaxIDAttempts; attempt++ {
+		room, err := m.Local.CreateRoom(options)
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := room.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		claimed, err := m.claim(conn, info)
+		if err != nil {
+			return nil, err
+		}
+
+		if !claimed {
+			// Room ID or invite code already in use by another node, discard and try again
+			err = m.Local.DeleteRoom(info.ID)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return room, nil
+	}
+
+	return nil, fmt.Errorf("failed to find a room ID and invite code not in use after %d attempts", redisMaxIDAttempts)
+}
+
+// RotateInviteCode gives a room owned by this node a new invite code, the code is claimed in the shared store before
+// the local room is changed, so that the code is unique across every node and the room keeps its previous code if the
+// new code cannot be claimed
+func (m *RedisManager) RotateInviteCode(id int32) (string, error) {
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	remote, err := m.getRemoteRoom(conn, id)
+	if err != nil {
+		return "", err
+	}
+
+	if remote != nil {
+		return "", remote.errOtherNode()
+	}
+
+	room, err := m.Local.GetRoom(id)
+	if err != nil {
+		return "", err
+	}
+
+	previous, err := redis.String(conn.Do("HGET", fmt.Sprintf(redisRoomKeyFormat, id), redisInviteCodeField))
+	if err != nil && err != redis.ErrNil {
+		return "", err
+	}
+
+	for attempt := 0; attempt < redisMaxIDAttempts; attempt++ {
+		code := newInviteCode()
+		claimed, err := m.claimInviteCode(conn, id, code)
+		if err != nil {
+			return "", err
+		}
+
+		if !claimed {
+			// Invite code already in use by a room on this or another node, try again
+			continue
+		}
+
+		err = room.SetInviteCode(code)
+		if err != nil {
+			return "", m.releaseInviteCode(conn, id, code, previous, err)
+		}
+
+		if previous != "" {
+			_, err = conn.Do("DEL", fmt.Sprintf(redisInviteKeyFormat, previous))
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return code, nil
+	}
+
+	return "", fmt.Errorf("failed to find an invite code not in use after %d attempts", redisMaxIDAttempts)
+}
+
+// QuickJoin connects a client to the fullest public room owned by this node matching the filter that still has a free
+// slot, if no room matches a public room is created with the options provided and registered in the shared store
+func (m *RedisManager) QuickJoin(connected *sessionv1.Session, filter PublicRoomFilter, options *Options) (*sessionv1.Session, Room, bool, error) {
+	m.quickJoinMutex.Lock()
+	defer m.quickJoinMutex.Unlock()
+
+	rooms, err := m.Local.ListRooms()
+	if err != nil {
+		return connected, nil, false, err
+	}
+
+	return quickJoin(rooms, connected, filter, options, m.CreateRoom)
+}
+
+// Export generates a snapshot of the rooms owned by this node, the local room manager must support snapshots
+func (m *RedisManager) Export() (*snapshotv1.Snapshot, error) {
+	local, ok := m.Local.(Snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("local room manager does not support snapshots")
+	}
+	return local.Export()
+}
+
+// Import restores the rooms in a snapshot using the local room manager, registering each room in the shared store
+// with this node as its owner. No rooms are imported if any of the rooms are already registered by another node
+func (m *RedisManager) Import(snapshot *snapshotv1.Snapshot) error {
+	local, ok := m.Local.(Snapshotter)
+	if !ok {
+		return fmt.Errorf("local room manager does not support snapshots")
+	}
+
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	for _, record := range snapshot.Rooms {
+		remote, err := m.getRemoteRoom(conn, record.ID)
+		if err != nil {
+			return err
+		}
+		if remote != nil {
+			return ErrRoomAlreadyExists{
+				Message: fmt.Sprintf("Room with ID %d already exists on the node at %s", record.ID, remote.Address),
+			}
+		}
+	}
+
+	err := local.Import(snapshot)
+	if err != nil {
+		return err
+	}
+
+	claimed := make([]int32, 0, len(snapshot.Rooms))
+	for _, record := range snapshot.Rooms {
+		room, err := m.Local.GetRoom(record.ID)
+		if err != nil {
+			return m.discardImport(conn, snapshot, claimed, err)
+		}
+
+		info, err := room.GetInfo()
+		if err != nil {
+			return m.discardImport(conn, snapshot, claimed, err)
+		}
+
+		ok, err := m.claim(conn, info)
+		if err != nil {
+			return m.discardImport(conn, snapshot, claimed, err)
+		}
+
+		if !ok {
+			// Room ID or invite code claimed by another node since checking, discard every imported room
+			return m.discardImport(conn, snapshot, claimed, ErrRoomAlreadyExists{
+				Message: fmt.Sprintf("Room with ID %d or its invite code already exists on another node", info.ID),
+			})
+		}
+
+		claimed = append(claimed, info.ID)
+	}
+
+	return nil
+}
+
+// discardImport rolls back an import that could not be completed, giving up the rooms claimed in the shared store and
+// deleting every imported room from the local room manager, the error that caused the rollback is returned unless the
+// rollback itself fails
+func (m *RedisManager) discardImport(conn redis.Conn, snapshot *snapshotv1.Snapshot, claimed []int32, cause error) error {
+	for _, id := range claimed {
+		err := m.unregister(conn, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, record := range snapshot.Rooms {
+		err := m.Local.DeleteRoom(record.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cause
+}
+
+// ListRooms returns a list of all rooms, including rooms owned by other nodes
+func (m *RedisManager) ListRooms() ([]Room, error) {
+	rooms, err := m.Local.ListRooms()
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(map[int32]bool, len(rooms))
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+		local[info.ID] = true
+	}
+
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	ids, err := redis.Strings(conn.Do("SMEMBERS", redisRoomsKey))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idStr := range ids {
+		id64, err := strconv.ParseInt(idStr, 10, 32)
+		if err != nil {
+			glog.Warningf("Removing invalid room ID %q from the shared store: %v", idStr, err)
+			_, err = conn.Do("SREM", redisRoomsKey, idStr)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		id := int32(id64)
+		if local[id] {
+			continue
+		}
+
+		remote, err := m.getRemoteRoom(conn, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if remote == nil {
+			// Room metadata has expired or is incomplete, clean up the dangling ID
+			_, err = conn.Do("SREM", redisRoomsKey, id)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		rooms = append(rooms, remote)
+	}
+
+	return rooms, nil
+}
+
+// Summary generates a rooms summary from the rooms owned by this node
+func (m *RedisManager) Summary() (*api.RoomsSummary, error) {
+	return m.Local.Summary()
+}
+
+// Refresh extends the expiry of all of the rooms owned by this node in the shared store, claiming any rooms or invite
+// codes whose registration has already expired again, this should be called periodically at an interval shorter than
+// the room TTL
+func (m *RedisManager) Refresh() error {
+	rooms, err := m.Local.ListRooms()
+	if err != nil {
+		return err
+	}
+
+	conn := m.Pool.Get()
+	defer conn.Close()
+
+	for _, room := range rooms {
+		info, err := room.GetInfo()
+		if err != nil {
+			return err
+		}
+
+		refreshed, err := redis.Int(conn.Do("PEXPIRE", fmt.Sprintf(redisRoomKeyFormat, info.ID), m.RoomTTL.Milliseconds()))
+		if err != nil {
+			return err
+		}
+
+		if refreshed == 0 {
+			// Room's registration expired, e.g. the store was unreachable for longer than the TTL, so claim it again
+			claimed, err := m.claim(conn, info)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				glog.Errorf("Failed to reclaim room with ID %d, the room ID or invite code is registered by another node", info.ID)
+			}
+			continue
+		}
+
+		if info.InviteCode != "" {
+			refreshed, err = redis.Int(conn.Do("PEXPIRE", fmt.Sprintf(redisInviteKeyFormat, info.InviteCode), m.RoomTTL.Milliseconds()))
+			if err != nil {
+				return err
+			}
+
+			if refreshed == 0 {
+				claimed, err := m.claimInviteCode(conn, info.ID, info.InviteCode)
+				if err != nil {
+					return err
+				}
+				if !claimed {
+					glog.Errorf("Failed to reclaim invite code for room with ID %d, the code is registered by another room", info.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// getRemoteRoom looks up a room in the shared store, returning nil if the room does not exist or is owned by this
+// node. Registrations missing fields, e.g. left behind by an older node, are treated as not existing and given an
+// expiry so that they are eventually removed
+func (m *RedisManager) getRemoteRoom(conn redis.Conn, id int32) (*RemoteRoom, error) {
+	key := fmt.Sprintf(redisRoomKeyFormat, id)
+
+	fields, err := redis.StringMap(conn.Do("HGETALL", key))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 || fields[redisOwnerField] == m.NodeAddress {
+		return nil, nil
+	}
+
+	remote, err := parseRemoteRoom(id, fields)
+	if err != nil {
+		glog.Warningf("Ignoring incomplete registration for room with ID %d: %v", id, err)
+		_, err = conn.Do("PEXPIRE", key, m.RoomTTL.Milliseconds())
+		return nil, err
+	}
+
+	return remote, nil
+}
+
+// parseRemoteRoom reads a room's registration from the fields of its hash in the shared store
+func parseRemoteRoom(id int32, fields map[string]string) (*RemoteRoom, error) {
+	owner := fields[redisOwnerField]
+	if owner == "" {
+		return nil, fmt.Errorf("no owner")
+	}
+
+	secretHash := fields[redisSecretHashField]
+	if secretHash == "" {
+		return nil, fmt.Errorf("no secret hash")
+	}
+
+	maxClients, err := strconv.ParseInt(fields[redisMaxClientsField], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max clients, %w", err)
+	}
+
+	public, err := strconv.ParseBool(fields[redisPublicField])
+	if err != nil {
+		return nil, fmt.Errorf("invalid public flag, %w", err)
+	}
+
+	var tags []string
+	if fields[redisTagsField] != "" {
+		err = json.Unmarshal([]byte(fields[redisTagsField]), &tags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tags, %w", err)
+		}
+	}
+
+	return &RemoteRoom{
+		ID:         id,
+		SecretHash: secretHash,
+		MaxClients: int32(maxClients),
+		InviteCode: fields[redisInviteCodeField],
+		Public:     public,
+		Name:       fields[redisNameField],
+		Tags:       tags,
+		Address:    owner,
+	}, nil
+}
+
+// claim registers a room in the shared store with this node as its owner, returning false if the room ID or the room's
+// invite code is already registered. The room's fields, its expiry and its membership of the set of rooms are written
+// in a single transaction, so other nodes never see a partly registered room
+func (m *RedisManager) claim(conn redis.Conn, info *api.RoomInfo) (bool, error) {
+	key := fmt.Sprintf(redisRoomKeyFormat, info.ID)
+
+	tags, err := json.Marshal(info.Tags)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = conn.Do("WATCH", key)
+	if err != nil {
+		return false, err
+	}
+
+	exists, err := redis.Bool(conn.Do("EXISTS", key))
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		_, err = conn.Do("UNWATCH")
+		return false, err
+	}
+
+	if info.InviteCode != "" {
+		reserved, err := m.reserveInviteCode(conn, info.ID, info.InviteCode)
+		if err != nil {
+			return false, err
+		}
+
+		if !reserved {
+			_, err = conn.Do("UNWATCH")
+			return false, err
+		}
+	}
+
+	err = conn.Send("MULTI")
+	if err != nil {
+		return false, err
+	}
+
+	err = conn.Send("HSET", key,
+		redisOwnerField, m.NodeAddress,
+		redisSecretHashField, secretHash(info.ID, info.Secret),
+		redisMaxClientsField, info.MaxClients,
+		redisInviteCodeField, info.InviteCode,
+		redisPublicField, strconv.FormatBool(info.Public),
+		redisNameField, info.Name,
+		redisTagsField, string(tags))
+	if err != nil {
+		return false, err
+	}
+
+	err = conn.Send("PEXPIRE", key, m.RoomTTL.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+
+	err = conn.Send("SADD", redisRoomsKey, info.ID)
+	if err != nil {
+		return false, err
+	}
+
+	reply, err := conn.Do("EXEC")
+	if err != nil {
+		return false, err
+	}
+
+	if reply == nil {
+		// Room ID was registered by another node after it was checked, give up the invite code reserved for it
+		if info.InviteCode != "" {
+			_, err = conn.Do("DEL", fmt.Sprintf(redisInviteKeyFormat, info.InviteCode))
+			if err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// reserveInviteCode registers an invite code in the shared store for a room without changing the room's own
+// registration, returning false if the code is already registered for another room
+func (m *RedisManager) reserveInviteCode(conn redis.Conn, id int32, code string) (bool, error) {
+	key := fmt.Sprintf(redisInviteKeyFormat, code)
+
+	reply, err := conn.Do("SET", key, id, "NX", "PX", m.RoomTTL.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+
+	if reply != nil {
+		return true, nil
+	}
+
+	// The code may already be registered to this room, e.g. if only the room's registration expired
+	owner, err := redis.String(conn.Do("GET", key))
+	if err != nil && err != redis.ErrNil {
+		return false, err
+	}
+
+	if owner != strconv.FormatInt(int64(id), 10) {
+		return false, nil
+	}
+
+	_, err = conn.Do("PEXPIRE", key, m.RoomTTL.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// claimInviteCode registers an invite code in the shared store for a room, returning false if the code is already
+// registered for another room
+func (m *RedisManager) claimInviteCode(conn redis.Conn, id int32, code string) (bool, error) {
+	reserved, err := m.reserveInviteCode(conn, id, code)
+	if err != nil || !reserved {
+		return false, err
+	}
+
+	err = m.setInviteCodeField(conn, id, code)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// setInviteCodeField updates the invite code in a room's registration, leaving the registration alone if it has
+// expired, as the room's current code is included when the room is claimed again
+func (m *RedisManager) setInviteCodeField(conn redis.Conn, id int32, code string) error {
+	key := fmt.Sprintf(redisRoomKeyFormat, id)
+
+	_, err := conn.Do("WATCH", key)
+	if err != nil {
+		return err
+	}
+
+	exists, err := redis.Bool(conn.Do("EXISTS", key))
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		_, err = conn.Do("UNWATCH")
+		return err
+	}
+
+	err = conn.Send("MULTI")
+	if err != nil {
+		return err
+	}
+
+	err = conn.Send("HSET", key, redisInviteCodeField, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Do("EXEC")
+	return err
+}
+
+// releaseInviteCode gives up an invite code that was claimed for a room but could not be set, restoring the room's
+// previous code in the shared store, the error that caused the release is returned unless the release itself fails
+func (m *RedisManager) releaseInviteCode(conn redis.Conn, id int32, code string, previous string, cause error) error {
+	_, err := conn.Do("DEL", fmt.Sprintf(redisInviteKeyFormat, code))
+	if err != nil {
+		return err
+	}
+
+	err = m.setInviteCodeField(conn, id, previous)
+	if err != nil {
+		return err
+	}
+
+	return cause
+}
+
+func (m *RedisManager) unregister(conn redis.Conn, id int32) error {
+	key := fmt.Sprintf(redisRoomKeyFormat, id)
+
+	code, err := redis.String(conn.Do("HGET", key, redisInviteCodeField))
+	if err != nil && err != redis.ErrNil {
+		return err
+	}
+
+	if code != "" {
+		_, err = conn.Do("DEL", fmt.Sprintf(redisInviteKeyFormat, code))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = conn.Do("DEL", key)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Do("SREM", redisRoomsKey, id)
+	return err
+}
+
+// RemoteRoom represents a room owned by another relay server node, clients cannot join a remote room on this node
+// and are instead redirected to the node that owns the room
+type RemoteRoom struct {
+	ID int32
+	// SecretHash is the hash of the room's secret, the secret itself is never shared between nodes
+	SecretHash string
+	MaxClients int32
+	InviteCode string
+	Public     bool
+	Name       string
+	Tags       []string
+	Address    string
+}
+
+// secretHash hashes a room's secret for sharing between nodes, allowing a node to check a secret provided for a room
+// owned by another node without the room's secret being stored in the shared store
+func secretHash(id int32, secret int32) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d", id, secret)))
+	return hex.EncodeToString(sum[:])
+}
+
+// RoomMatches determines if a room matches the ID and secret provided
+func (r *RemoteRoom) RoomMatches(id int32, secret int32) bool {
+	return r.ID == id && r.SecretHash == secretHash(id, secret)
+}
+
+// NewClient always fails, providing the address of the node the client should connect to instead
+func (r *RemoteRoom) NewClient(connected *sessionv1.Session) (*sessionv1.Session, error) {
+	return connected, r.errOtherNode()
+}
+
+// NewSpectator always fails, providing the address of the node the spectator should connect to instead
+func (r *RemoteRoom) NewSpectator(connected *sessionv1.Session) (*sessionv1.Session, error) {
+	return connected, r.errOtherNode()
+}
+
+// ExistingClient always fails, providing the address of the node the client should reconnect to instead
+func (r *RemoteRoom) ExistingClient(connected *sessionv1.Session, clientID int32, clientSecret int32) (*sessionv1.Session, error) {
+	return connected, r.errOtherNode()
+}
+
+// GetClient always fails, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) GetClient(clientID int32) (*sessionv1.Session, error) {
+	return nil, ErrNoMatchingClient{
+		Message: fmt.Sprintf("No connected client found with ID %d", clientID),
+	}
+}
+
+// RemoveClient always fails, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) RemoveClient(clientID int32) error {
+	return ErrNoMatchingClient{
+		Message: fmt.Sprintf("No connected client found with ID %d", clientID),
+	}
+}
+
+// ForgetClient always fails, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) ForgetClient(clientID int32) error {
+	return ErrNoMatchingClient{
+		Message: fmt.Sprintf("No client found with ID %d", clientID),
+	}
+}
+
+// PruneDisconnected does nothing, as disconnected clients are only pruned by the node that owns the room
+func (r *RemoteRoom) PruneDisconnected(now time.Time) ([]*clientv1.Client, error) {
+	return []*clientv1.Client{}, nil
+}
+
+// GetConnected returns an empty list, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) GetConnected() ([]*sessionv1.Session, error) {
+	return []*sessionv1.Session{}, nil
+}
+
+// GetDisconnected returns an empty list, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) GetDisconnected() ([]*clientv1.Client, error) {
+	return []*clientv1.Client{}, nil
+}
+
+// RecordMessage does nothing, as messages are only relayed by the node that owns the room
+func (r *RemoteRoom) RecordMessage(clientID int32, data []byte) (*uint64, error) {
+	return nil, nil
+}
+
+// ReplayMessages always returns no messages, as messages are only relayed by the node that owns the room
+func (r *RemoteRoom) ReplayMessages(clientID int32, lastSequence uint64) ([]ReplayMessage, bool, error) {
+	return []ReplayMessage{}, false, nil
+}
+
+// IsHost always returns false, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) IsHost(potentialHost *clientv1.Client) (bool, error) {
+	return false, nil
+}
+
+// SetHost always fails, as the host can only be set by the node that owns the room
+func (r *RemoteRoom) SetHost(hostID *int32) (*sessionv1.Session, error) {
+	return nil, r.errOtherNode()
+}
+
+// GetHost returns no host, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) GetHost() (*sessionv1.Session, error) {
+	return nil, nil
+}
+
+// GetInfo generates the room's info from the shared metadata, including the address of the node that owns the room,
+// the room's secret is not shared so is never included
+func (r *RemoteRoom) GetInfo() (*api.RoomInfo, error) {
+	return &api.RoomInfo{
+		ID:         r.ID,
+		MaxClients: r.MaxClients,
+		RoomStatus: StatusRunning.String(),
+		Node:       r.Address,
+		InviteCode: r.InviteCode,
+		Public:     r.Public,
+		Name:       r.Name,
+		Tags:       r.Tags,
+	}, nil
+}
+
+// Expiry returns no expiry, as rooms are only expired by the node that owns them
+func (r *RemoteRoom) Expiry() *time.Time {
+	return nil
+}
+
+// GetRole always returns the player role, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) GetRole(clientID int32) (Role, error) {
+	return RolePlayer, nil
+}
+
+// SetRole always fails, as roles can only be set by the node that owns the room
+func (r *RemoteRoom) SetRole(clientID int32, role Role) error {
+	return r.errOtherNode()
+}
+
+// GetPermissions returns no permissions, as permissions are only enforced by the node that owns the room
+func (r *RemoteRoom) GetPermissions() Permissions {
+	return nil
+}
+
+// GetMetadata always returns no metadata, as no clients are connected to remote rooms on this node
+func (r *RemoteRoom) GetMetadata(clientID int32) (map[string]string, error) {
+	return nil, nil
+}
+
+// SetMetadata always fails, as metadata can only be set by the node that owns the room
+func (r *RemoteRoom) SetMetadata(clientID int32, metadata map[string]string) error {
+	return r.errOtherNode()
+}
+
+// GetProperties always returns no properties, as properties are only held by the node that owns the room
+func (r *RemoteRoom) GetProperties() (map[string]Property, error) {
+	return map[string]Property{}, nil
+}
+
+// SetProperty always fails, as properties can only be set by the node that owns the room
+func (r *RemoteRoom) SetProperty(key string, value string, expectedVersion *uint64) (Property, error) {
+	return Property{}, r.errOtherNode()
+}
+
+// DeleteProperty always fails, as properties can only be deleted by the node that owns the room
+func (r *RemoteRoom) DeleteProperty(key string, expectedVersion *uint64) (uint64, error) {
+	return 0, r.errOtherNode()
+}
+
+// AllowRelay always fails, as relaying is only rate limited by the node that owns the room
+func (r *RemoteRoom) AllowRelay(clientID int32, now time.Time) (bool, error) {
+	return false, r.errOtherNode()
+}
+
+// SetInviteCode always fails, as the invite code can only be changed by the node that owns the room
+func (r *RemoteRoom) SetInviteCode(code string) error {
+	return r.errOtherNode()
+}
+
+// CheckPassword always fails, as the password is only known by the node that owns the room
+func (r *RemoteRoom) CheckPassword(password string) (bool, error) {
+	return false, r.errOtherNode()
+}
+
+// SetStatus does nothing, as the status can only be set by the node that owns the room
+func (r *RemoteRoom) SetStatus(status Status) {}
+
+// GetStatus returns the room's status, remote rooms are always treated as running
+func (r *RemoteRoom) GetStatus() Status {
+	return StatusRunning
+}
+
+func (r *RemoteRoom) errOtherNode() error {
+	return ErrRoomOnOtherNode{
+		Message: fmt.Sprintf("Room with ID %d is owned by the node at %s", r.ID, r.Address),
+		Address: r.Address,
+	}
+}
+
+// NewRedisTemplateStore creates a new template store that shares templates between relay server nodes using a Redis
+// compatible store, so that every node creates the same rooms from a template
+func NewRedisTemplateStore(pool *redis.Pool) *RedisTemplateStore {
+	return &RedisTemplateStore{
+		Pool: pool,
+	}
+}
+
+// RedisTemplateStore stores templates in a Redis compatible store shared by every node, as a hash of JSON encoded
+// templates by name
+type RedisTemplateStore struct {
+	Pool *redis.Pool
+}
+
+// GetTemplate returns the template with the name provided, nil if none found
+func (s *RedisTemplateStore) GetTemplate(name string) (*api.RoomTemplate, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	value, err := redis.Bytes(conn.Do("HGET", redisTemplatesKey, name))
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	template := &api.RoomTemplate{}
+	err = json.Unmarshal(value, template)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ListTemplates returns every template
+func (s *RedisTemplateStore) ListTemplates() ([]*api.RoomTemplate, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	values, err := redis.ByteSlices(conn.Do("HVALS", redisTemplatesKey))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*api.RoomTemplate, 0, len(values))
+	for _, value := range values {
+		template := &api.RoomTemplate{}
+		err = json.Unmarshal(value, template)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, template)
+	}
+	return list, nil
+}
+
+// PutTemplate stores a template, replacing any existing template with the same name
+func (s *RedisTemplateStore) PutTemplate(template *api.RoomTemplate) error {
+	value, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	_, err = conn.Do("HSET", redisTemplatesKey, template.Name, value)
+	return err
+}
+
+// DeleteTemplate removes the template with the name provided, returning if it existed
+func (s *RedisTemplateStore) DeleteTemplate(name string) (bool, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	deleted, err := redis.Int(conn.Do("HDEL", redisTemplatesKey, name))
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
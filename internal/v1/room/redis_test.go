@@ -0,0 +1,433 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	snapshotv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
+)
+
+// fakeRedis is an in memory stand in for a Redis server, supporting only the commands used by the Redis room manager
+// and ignoring expiry. Transactions are supported for a single connection, commands sent after MULTI are queued and
+// run by EXEC, which fails if a watched key has changed
+type fakeRedis struct {
+	mutex   sync.Mutex
+	values  map[string]interface{}
+	multi   bool
+	queued  [][]interface{}
+	watched map[string]string
+}
+
+func newFakeRedisPool() (*redis.Pool, *fakeRedis) {
+	store := &fakeRedis{
+		values: make(map[string]interface{}),
+	}
+	return &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return store, nil
+		},
+	}, store
+}
+
+func (f *fakeRedis) Close() error                  { return nil }
+func (f *fakeRedis) Err() error                    { return nil }
+func (f *fakeRedis) Flush() error                  { return nil }
+func (f *fakeRedis) Receive() (interface{}, error) { return nil, nil }
+
+func (f *fakeRedis) Send(commandName string, args ...interface{}) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if commandName == "MULTI" {
+		f.multi = true
+		f.queued = nil
+		return nil
+	}
+
+	if f.multi {
+		f.queued = append(f.queued, append([]interface{}{commandName}, args...))
+	}
+	return nil
+}
+
+func (f *fakeRedis) hash(key string) map[string]string {
+	hash, ok := f.values[key].(map[string]string)
+	if !ok {
+		hash = make(map[string]string)
+		f.values[key] = hash
+	}
+	return hash
+}
+
+func (f *fakeRedis) set(key string) map[string]bool {
+	set, ok := f.values[key].(map[string]bool)
+	if !ok {
+		set = make(map[string]bool)
+		f.values[key] = set
+	}
+	return set
+}
+
+func (f *fakeRedis) Do(commandName string, args ...interface{}) (interface{}, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch commandName {
+	case "WATCH":
+		if f.watched == nil {
+			f.watched = make(map[string]string)
+		}
+		for _, arg := range args {
+			key := fmt.Sprint(arg)
+			f.watched[key] = fmt.Sprint(f.values[key])
+		}
+		return "OK", nil
+	case "UNWATCH":
+		f.watched = nil
+		return "OK", nil
+	case "DISCARD":
+		f.multi = false
+		f.queued = nil
+		f.watched = nil
+		return "OK", nil
+	case "EXEC":
+		queued, watched := f.queued, f.watched
+		f.multi, f.queued, f.watched = false, nil, nil
+		for key, value := range watched {
+			if fmt.Sprint(f.values[key]) != value {
+				return nil, nil
+			}
+		}
+		replies := []interface{}{}
+		for _, command := range queued {
+			reply, err := f.do(command[0].(string), command[1:]...)
+			if err != nil {
+				return nil, err
+			}
+			replies = append(replies, reply)
+		}
+		return replies, nil
+	}
+
+	return f.do(commandName, args...)
+}
+
+func (f *fakeRedis) do(commandName string, args ...interface{}) (interface{}, error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = fmt.Sprint(arg)
+	}
+
+	switch commandName {
+	case "":
+		return nil, nil
+	case "DEL":
+		_, exists := f.values[strs[0]]
+		delete(f.values, strs[0])
+		if exists {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case "EXISTS", "PEXPIRE":
+		if _, exists := f.values[strs[0]]; exists {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case "GET":
+		value, exists := f.values[strs[0]].(string)
+		if !exists {
+			return nil, nil
+		}
+		return []byte(value), nil
+	case "SET":
+		_, exists := f.values[strs[0]]
+		if exists && len(strs) > 2 && strs[2] == "NX" {
+			return nil, nil
+		}
+		f.values[strs[0]] = strs[1]
+		return "OK", nil
+	case "HSET":
+		hash := f.hash(strs[0])
+		for i := 1; i+1 < len(strs); i += 2 {
+			hash[strs[i]] = strs[i+1]
+		}
+		return int64(1), nil
+	case "HSETNX":
+		hash := f.hash(strs[0])
+		if _, exists := hash[strs[1]]; exists {
+			return int64(0), nil
+		}
+		hash[strs[1]] = strs[2]
+		return int64(1), nil
+	case "HGET":
+		hash, _ := f.values[strs[0]].(map[string]string)
+		value, exists := hash[strs[1]]
+		if !exists {
+			return nil, nil
+		}
+		return []byte(value), nil
+	case "HDEL":
+		hash, _ := f.values[strs[0]].(map[string]string)
+		delete(hash, strs[1])
+		return int64(1), nil
+	case "HGETALL":
+		hash, _ := f.values[strs[0]].(map[string]string)
+		reply := []interface{}{}
+		for field, value := range hash {
+			reply = append(reply, []byte(field), []byte(value))
+		}
+		return reply, nil
+	case "HVALS":
+		hash, _ := f.values[strs[0]].(map[string]string)
+		reply := []interface{}{}
+		for _, value := range hash {
+			reply = append(reply, []byte(value))
+		}
+		return reply, nil
+	case "SADD":
+		f.set(strs[0])[strs[1]] = true
+		return int64(1), nil
+	case "SREM":
+		set, _ := f.values[strs[0]].(map[string]bool)
+		delete(set, strs[1])
+		return int64(1), nil
+	case "SMEMBERS":
+		set, _ := f.values[strs[0]].(map[string]bool)
+		reply := []interface{}{}
+		for member := range set {
+			reply = append(reply, []byte(member))
+		}
+		return reply, nil
+	}
+
+	return nil, fmt.Errorf("unsupported command %s", commandName)
+}
+
+func TestRedisRefreshReclaimsExpiredRooms(t *testing.T) {
+	tests := []struct {
+		name string
+		// expire removes keys from the store as if they had expired, optionally registering them to another node
+		expire func(store *fakeRedis, id int32, code string)
+		owner  string
+	}{
+		{
+			name:   "registration still held",
+			expire: func(store *fakeRedis, id int32, code string) {},
+			owner:  "node-a",
+		},
+		{
+			name: "room and invite code expired",
+			expire: func(store *fakeRedis, id int32, code string) {
+				delete(store.values, fmt.Sprintf(redisRoomKeyFormat, id))
+				delete(store.values, fmt.Sprintf(redisInviteKeyFormat, code))
+			},
+			owner: "node-a",
+		},
+		{
+			name: "invite code expired",
+			expire: func(store *fakeRedis, id int32, code string) {
+				delete(store.values, fmt.Sprintf(redisInviteKeyFormat, code))
+			},
+			owner: "node-a",
+		},
+		{
+			name: "room claimed by another node after expiring",
+			expire: func(store *fakeRedis, id int32, code string) {
+				store.values[fmt.Sprintf(redisRoomKeyFormat, id)] = map[string]string{redisOwnerField: "node-b"}
+			},
+			owner: "node-b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, store := newFakeRedisPool()
+			manager := NewRedisManager(newTestManager(), pool, "node-a", time.Minute)
+
+			room, err := manager.CreateRoom(Options{MaxClients: 2})
+			if err != nil {
+				t.Fatalf("failed to create room: %v", err)
+			}
+			info, err := room.GetInfo()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.expire(store, info.ID, info.InviteCode)
+
+			err = manager.Refresh()
+			if err != nil {
+				t.Fatalf("failed to refresh: %v", err)
+			}
+
+			owner := store.hash(fmt.Sprintf(redisRoomKeyFormat, info.ID))[redisOwnerField]
+			if owner != tt.owner {
+				t.Errorf("got owner %s, want %s", owner, tt.owner)
+			}
+
+			invite, _ := store.values[fmt.Sprintf(redisInviteKeyFormat, info.InviteCode)].(string)
+			if invite != fmt.Sprint(info.ID) {
+				t.Errorf("got invite code registered to %q, want room %d", invite, info.ID)
+			}
+		})
+	}
+}
+
+func TestRedisImportRollsBack(t *testing.T) {
+	pool, store := newFakeRedisPool()
+	manager := NewRedisManager(newTestManager(), pool, "node-a", time.Minute)
+
+	// The second room's invite code is registered to a room on another node
+	store.values[fmt.Sprintf(redisInviteKeyFormat, "TAKEN234")] = "99"
+
+	err := manager.Import(&snapshotv1.Snapshot{
+		Version: SnapshotVersion,
+		Rooms: []*snapshotv1.Room{
+			{ID: 1, Secret: 1, MaxClients: 2, InviteCode: "ABCD2345"},
+			{ID: 2, Secret: 2, MaxClients: 2, InviteCode: "TAKEN234"},
+		},
+	})
+	if _, ok := err.(ErrRoomAlreadyExists); !ok {
+		t.Fatalf("expected ErrRoomAlreadyExists, got %v", err)
+	}
+
+	rooms, err := manager.Local.ListRooms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 0 {
+		t.Errorf("got %d local rooms, want none", len(rooms))
+	}
+
+	for _, key := range []string{
+		fmt.Sprintf(redisRoomKeyFormat, 1),
+		fmt.Sprintf(redisRoomKeyFormat, 2),
+		fmt.Sprintf(redisInviteKeyFormat, "ABCD2345"),
+	} {
+		if _, exists := store.values[key]; exists {
+			t.Errorf("expected %s to be released", key)
+		}
+	}
+	if len(store.set(redisRoomsKey)) != 0 {
+		t.Errorf("expected no rooms to be registered")
+	}
+}
+
+func TestRedisListRoomsSkipsIncompleteRooms(t *testing.T) {
+	pool, store := newFakeRedisPool()
+	remote := NewRedisManager(newTestManager(), pool, "node-b", time.Minute)
+	manager := NewRedisManager(newTestManager(), pool, "node-a", time.Minute)
+
+	room, err := remote.CreateRoom(Options{MaxClients: 2})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A registration left half written by a node that stopped part way through claiming the room
+	store.values[fmt.Sprintf(redisRoomKeyFormat, 7)] = map[string]string{redisOwnerField: "node-c"}
+	store.set(redisRoomsKey)["7"] = true
+	store.set(redisRoomsKey)["not-an-id"] = true
+
+	rooms, err := manager.ListRooms()
+	if err != nil {
+		t.Fatalf("failed to list rooms: %v", err)
+	}
+
+	if len(rooms) != 1 {
+		t.Fatalf("got %d rooms, want 1", len(rooms))
+	}
+	listed, err := rooms[0].GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed.ID != info.ID || listed.Node != "node-b" {
+		t.Errorf("got room %d on %s, want room %d on node-b", listed.ID, listed.Node, info.ID)
+	}
+
+	members := store.set(redisRoomsKey)
+	if members["7"] || members["not-an-id"] {
+		t.Errorf("expected invalid rooms to be removed from the set of rooms, got %v", members)
+	}
+}
+
+func TestRedisRemoteRoomListing(t *testing.T) {
+	pool, store := newFakeRedisPool()
+	remote := NewRedisManager(newTestManager(), pool, "node-b", time.Minute)
+	manager := NewRedisManager(newTestManager(), pool, "node-a", time.Minute)
+
+	room, err := remote.CreateRoom(Options{MaxClients: 4, Public: true, Name: "Lobby", Tags: []string{"casual"}})
+	if err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+	info, err := room.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for field, value := range store.hash(fmt.Sprintf(redisRoomKeyFormat, info.ID)) {
+		if value == fmt.Sprint(info.Secret) {
+			t.Errorf("expected the room's secret not to be stored, found in field %s", field)
+		}
+	}
+
+	rooms, err := manager.ListRooms()
+	if err != nil {
+		t.Fatalf("failed to list rooms: %v", err)
+	}
+
+	list, err := ListPublicRooms(rooms, PublicRoomFilter{Tags: []string{"casual"}})
+	if err != nil {
+		t.Fatalf("failed to list public rooms: %v", err)
+	}
+	if len(list.Rooms) != 1 || list.Rooms[0].ID != info.ID || list.Rooms[0].Name != "Lobby" {
+		t.Fatalf("expected the remote public room to be listed, got %+v", list.Rooms)
+	}
+
+	found, err := manager.GetRoom(info.ID)
+	if err != nil {
+		t.Fatalf("failed to get room: %v", err)
+	}
+	if !found.RoomMatches(info.ID, info.Secret) {
+		t.Errorf("expected the remote room to match its secret")
+	}
+	if found.RoomMatches(info.ID, info.Secret+1) {
+		t.Errorf("expected the remote room not to match the wrong secret")
+	}
+
+	remoteInfo, err := found.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remoteInfo.Secret != 0 {
+		t.Errorf("expected the remote room's secret not to be included in its info")
+	}
+
+	// Public rooms joined by ID without the secret are redirected to the node owning the room
+	_, err = found.CheckPassword("")
+	if _, ok := err.(ErrRoomOnOtherNode); !ok {
+		t.Errorf("expected ErrRoomOnOtherNode, got %v", err)
+	}
+}
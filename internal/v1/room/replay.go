@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+// ReplayMessage is a relayed message sent to a client, stored so that it can be replayed if the client reconnects
+type ReplayMessage struct {
+	Sequence uint64
+	Data     []byte
+}
+
+// NewReplayBuffer creates a new replay buffer that stores up to the number of messages provided, the first message
+// added to the buffer has a sequence number of 1
+func NewReplayBuffer(size int32) *ReplayBuffer {
+	return &ReplayBuffer{
+		Messages:     make([]ReplayMessage, size),
+		NextSequence: 1,
+	}
+}
+
+// ReplayBuffer is a fixed size ring buffer of the most recent relayed messages sent to a client, once full the oldest
+// message is overwritten by each new message
+type ReplayBuffer struct {
+	Messages     []ReplayMessage
+	Start        int
+	Count        int
+	NextSequence uint64
+}
+
+// Add stores a message in the buffer, returning the sequence number assigned to it
+func (b *ReplayBuffer) Add(data []byte) uint64 {
+	sequence := b.NextSequence
+	b.NextSequence++
+
+	message := ReplayMessage{
+		Sequence: sequence,
+		Data:     data,
+	}
+
+	if b.Count < len(b.Messages) {
+		b.Messages[(b.Start+b.Count)%len(b.Messages)] = message
+		b.Count++
+		return sequence
+	}
+
+	b.Messages[b.Start] = message
+	b.Start = (b.Start + 1) % len(b.Messages)
+	return sequence
+}
+
+// Since returns every stored message with a sequence number after the last sequence number provided, also returning
+// if there is a gap between the last sequence number and the messages returned, caused by messages being overwritten
+// or by the sequence being reset
+func (b *ReplayBuffer) Since(lastSequence uint64) ([]ReplayMessage, bool) {
+	firstSequence := b.NextSequence - uint64(b.Count)
+
+	if lastSequence >= b.NextSequence {
+		// Client has seen messages the buffer has no record of, the sequence has been reset so replay everything
+		return b.from(0), true
+	}
+
+	if lastSequence+1 < firstSequence {
+		return b.from(0), true
+	}
+
+	return b.from(int(lastSequence + 1 - firstSequence)), false
+}
+
+func (b *ReplayBuffer) from(offset int) []ReplayMessage {
+	messages := make([]ReplayMessage, 0, b.Count-offset)
+	for i := offset; i < b.Count; i++ {
+		messages = append(messages, b.Messages[(b.Start+i)%len(b.Messages)])
+	}
+	return messages
+}
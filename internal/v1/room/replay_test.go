@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplayBufferSince(t *testing.T) {
+	tests := []struct {
+		name         string
+		size         int32
+		added        int
+		lastSequence uint64
+		want         []uint64
+		gap          bool
+	}{
+		{name: "empty buffer", size: 3, added: 0, lastSequence: 0, want: []uint64{}},
+		{name: "replay everything", size: 3, added: 2, lastSequence: 0, want: []uint64{1, 2}},
+		{name: "replay missed messages", size: 3, added: 3, lastSequence: 1, want: []uint64{2, 3}},
+		{name: "nothing missed", size: 3, added: 3, lastSequence: 3, want: []uint64{}},
+		{name: "wrapped without gap", size: 3, added: 5, lastSequence: 2, want: []uint64{3, 4, 5}},
+		{name: "overwritten messages are a gap", size: 3, added: 5, lastSequence: 1, want: []uint64{3, 4, 5}, gap: true},
+		{name: "reset sequence is a gap", size: 3, added: 2, lastSequence: 10, want: []uint64{1, 2}, gap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewReplayBuffer(tt.size)
+			for i := 0; i < tt.added; i++ {
+				sequence := buffer.Add([]byte{byte(i)})
+				if sequence != uint64(i+1) {
+					t.Fatalf("message %d: got sequence %d, want %d", i, sequence, i+1)
+				}
+			}
+
+			messages, gap := buffer.Since(tt.lastSequence)
+			sequences := make([]uint64, 0, len(messages))
+			for _, message := range messages {
+				sequences = append(sequences, message.Sequence)
+				if message.Data[0] != byte(message.Sequence-1) {
+					t.Errorf("message %d: got data %v", message.Sequence, message.Data)
+				}
+			}
+
+			if !reflect.DeepEqual(sequences, tt.want) {
+				t.Errorf("got sequences %v, want %v", sequences, tt.want)
+			}
+			if gap != tt.gap {
+				t.Errorf("got gap %t, want %t", gap, tt.gap)
+			}
+		})
+	}
+}
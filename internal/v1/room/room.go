@@ -17,30 +17,96 @@ limitations under the License.
 package room
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
 	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
 	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
 )
 
 // Factory defines a function for generating a room based on standard options
-type Factory func(id int32, secret int32, maxClients int32) (Room, error)
+type Factory func(id int32, secret int32, options Options) (Room, error)
+
+// Options defines the configurable options for a room, any timeouts left as zero are disabled
+type Options struct {
+	// MaxClients is the maximum number of clients that can be connected to the room at once
+	MaxClients int32
+	// MaxSpectators is the maximum number of spectators that can be connected to the room at once, separate from
+	// MaxClients, zero to not allow spectators
+	MaxSpectators int32
+	// MaxLifetime is how long the room can exist for, measured from when it was created
+	MaxLifetime time.Duration
+	// IdleTimeout is how long the room can exist for after the last client leaves
+	IdleTimeout time.Duration
+	// NeverJoinedTimeout is how long the room can exist for if no client ever joins it
+	NeverJoinedTimeout time.Duration
+	// ReconnectGracePeriod is how long a disconnected client can reconnect for before it is forgotten
+	ReconnectGracePeriod time.Duration
+	// MaxDisconnectedClients is the maximum number of disconnected clients remembered, with the clients that
+	// disconnected earliest forgotten first, zero for no limit
+	MaxDisconnectedClients int32
+	// ReservationPolicy determines if disconnected clients have their slots reserved while they can reconnect
+	ReservationPolicy ReservationPolicy
+	// ReplayBufferSize is the number of relayed messages stored for each client so they can be replayed if the client
+	// reconnects, zero to disable replaying messages
+	ReplayBufferSize int32
+	// Permissions determines what clients in each role are permitted to do, nil for the default permissions
+	Permissions Permissions
+	// Public determines if the room is listed for players browsing for a room to join, with its name and tags
+	Public bool
+	Name   string
+	Tags   []string
+	// RelayRateLimit is how many messages each client can relay per second, zero for no limit
+	RelayRateLimit int32
+	// Properties are the values the room's properties start with, by key
+	Properties map[string]string
+	// Template is the name of the template the room was created from, empty if it was not created from a template
+	Template string
+	// InviteCode is the short code players can use to join the room, assigned by the room manager so that it is unique
+	// across the manager's rooms
+	InviteCode string
+	// Password is required to join the room using its invite code, empty for no password. Only a hash of the password
+	// is kept by the room
+	Password string
+}
 
 // Room defines the contract for interacting with a room
 type Room interface {
 	RoomMatches(id int32, secret int32) bool
 
 	NewClient(session *session.Session) (*session.Session, error)
+	NewSpectator(session *session.Session) (*session.Session, error)
 	ExistingClient(session *session.Session, clientID int32, clientSecret int32) (*session.Session, error)
 
 	GetClient(clientID int32) (*session.Session, error)
 	RemoveClient(clientID int32) error
+	ForgetClient(clientID int32) error
+	PruneDisconnected(now time.Time) ([]*client.Client, error)
 
 	GetConnected() ([]*session.Session, error)
+	GetDisconnected() ([]*client.Client, error)
+
+	RecordMessage(clientID int32, data []byte) (*uint64, error)
+	ReplayMessages(clientID int32, lastSequence uint64) ([]ReplayMessage, bool, error)
 
 	IsHost(potentialHost *client.Client) (bool, error)
 	SetHost(hostID *int32) (*session.Session, error)
 	GetHost() (*session.Session, error)
 	GetInfo() (*api.RoomInfo, error)
+	GetRole(clientID int32) (Role, error)
+	SetRole(clientID int32, role Role) error
+	GetPermissions() Permissions
+	GetMetadata(clientID int32) (map[string]string, error)
+	SetMetadata(clientID int32, metadata map[string]string) error
+	GetProperties() (map[string]Property, error)
+	SetProperty(key string, value string, expectedVersion *uint64) (Property, error)
+	DeleteProperty(key string, expectedVersion *uint64) (uint64, error)
+	AllowRelay(clientID int32, now time.Time) (bool, error)
+	SetInviteCode(code string) error
+	CheckPassword(password string) (bool, error)
+	Expiry() *time.Time
 
 	SetStatus(Status)
 	GetStatus() Status
@@ -64,9 +130,56 @@ const (
 type Manager interface {
 	GetRoom(id int32) (Room, error)
 	DeleteRoom(id int32) error
-	CreateRoom(maxClients int32) (Room, error)
+	CreateRoom(options Options) (Room, error)
 
 	ListRooms() ([]Room, error)
 
 	Summary() (*api.RoomsSummary, error)
 }
+
+// Snapshotter defines a contract for room managers that can export the full state of their rooms to a snapshot, and
+// restore rooms from a snapshot
+type Snapshotter interface {
+	Export() (*snapshot.Snapshot, error)
+	Import(snapshot *snapshot.Snapshot) error
+}
+
+// ReservationPolicy defines how a room's slots are shared between new clients and disconnected clients that can still
+// reconnect
+type ReservationPolicy int32
+
+func (p ReservationPolicy) String() string {
+	if !p.valid() {
+		return "UNKNOWN"
+	}
+	return [...]string{"FIRST_COME", "RESERVE"}[p]
+}
+
+// valid determines if the reservation policy is one of the known reservation policies
+func (p ReservationPolicy) valid() bool {
+	return p == ReservationPolicyFirstCome || p == ReservationPolicyReserve
+}
+
+const (
+	// ReservationPolicyFirstCome gives slots to whichever client connects first, new clients can take the slots of
+	// disconnected clients, leaving them unable to reconnect until a slot is free
+	ReservationPolicyFirstCome ReservationPolicy = iota
+	// ReservationPolicyReserve reserves a slot for each disconnected client while it can still reconnect, new clients
+	// can only take slots that are not reserved
+	ReservationPolicyReserve
+)
+
+// ParseReservationPolicy converts a reservation policy name into a reservation policy, an empty name is treated as
+// first come
+func ParseReservationPolicy(name string) (ReservationPolicy, error) {
+	switch name {
+	case "", ReservationPolicyFirstCome.String():
+		return ReservationPolicyFirstCome, nil
+	case ReservationPolicyReserve.String():
+		return ReservationPolicyReserve, nil
+	}
+	return ReservationPolicyFirstCome, ErrInvalidReservationPolicy{
+		Message: fmt.Sprintf("Unknown reservation policy '%s', must be one of %s or %s", name,
+			ReservationPolicyFirstCome, ReservationPolicyReserve),
+	}
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	sessionv1 "github.com/jamjarlabs/jamjar-relay-server/internal/v1/session"
+	clientv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	roomspecv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	snapshotv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SnapshotVersion is the version of the snapshot format produced when exporting rooms, only snapshots with this
+// version can be imported
+const SnapshotVersion = 1
+
+// snapshotRoom defines a room that can have its full state exported to and restored from a snapshot
+type snapshotRoom interface {
+	Snapshot() *snapshotv1.Room
+	Restore(snapshot *snapshotv1.Room) error
+}
+
+// discardableRoom defines a room that stores state outside of the room manager, which must be removed if the room is
+// created but then discarded without being added to the manager
+type discardableRoom interface {
+	discard() error
+}
+
+// Export generates a snapshot of the full state of every room in the room manager
+func (m *MemoryManager) Export() (*snapshotv1.Snapshot, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	snapshot := &snapshotv1.Snapshot{
+		Version: SnapshotVersion,
+		Rooms:   make([]*snapshotv1.Room, 0, len(m.Rooms)),
+	}
+
+	for id, room := range m.Rooms {
+		exportable, ok := room.(snapshotRoom)
+		if !ok {
+			return nil, fmt.Errorf("room with ID %d does not support snapshots", id)
+		}
+		snapshot.Rooms = append(snapshot.Rooms, exportable.Snapshot())
+	}
+
+	return snapshot, nil
+}
+
+// Import restores the rooms in a snapshot into the room manager, every client is restored as disconnected so that
+// they can reconnect using their existing client ID and secret. Rooms keep their invite code unless it is already in
+// use, in which case they are given a new one. No rooms are imported if any of the rooms in the
+// snapshot already exist, if importing them would result in more committed clients than the max, or if any of the
+// rooms fail to be restored
+func (m *MemoryManager) Import(snapshot *snapshotv1.Snapshot) error {
+	if snapshot.Version != SnapshotVersion {
+		return ErrUnsupportedSnapshot{
+			Message: fmt.Sprintf("Snapshot version %d is not supported, only version %d is supported", snapshot.Version,
+				SnapshotVersion),
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	summary, err := m.summary()
+	if err != nil {
+		return err
+	}
+
+	committedClients := summary.CommittedClients
+	seen := make(map[int32]bool, len(snapshot.Rooms))
+	for _, record := range snapshot.Rooms {
+		if _, exists := m.Rooms[record.ID]; exists || seen[record.ID] {
+			return ErrRoomAlreadyExists{
+				Message: fmt.Sprintf("Room with ID %d already exists", record.ID),
+			}
+		}
+		seen[record.ID] = true
+		committedClients += int32(math.Ceil(float64(record.MaxClients+record.MaxSpectators)/float64(m.CeilCommittedToNearest)) * float64(m.CeilCommittedToNearest))
+	}
+
+	if summary.MaxClients-committedClients < 0 {
+		return ErrRequestTooManyClients{
+			Message: fmt.Sprintf(
+				"Cannot import these rooms, this would result in more committed clients than the max (%d/%d)",
+				committedClients, summary.MaxClients),
+		}
+	}
+
+	// Restore every room before adding any of them to the manager, so a failure leaves the manager unchanged
+	imported := make(map[int32]Room, len(snapshot.Rooms))
+	for _, record := range snapshot.Rooms {
+		room, err := m.importRoom(record, imported)
+		if err != nil {
+			return discardRooms(imported, err)
+		}
+		imported[record.ID] = room
+	}
+
+	for id, room := range imported {
+		m.Rooms[id] = room
+	}
+
+	return nil
+}
+
+// importRoom creates a room and restores its state from a snapshot, the room is given a new invite code if its code is
+// used by any existing or already imported rooms, the caller must hold the manager's lock
+func (m *MemoryManager) importRoom(record *snapshotv1.Room, imported map[int32]Room) (Room, error) {
+	inviteCode := record.InviteCode
+	inUse, err := importedInviteCodeInUse(m.Rooms, imported, inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	for inviteCode == "" || inUse {
+		inviteCode = newInviteCode()
+		inUse, err = importedInviteCodeInUse(m.Rooms, imported, inviteCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	room, err := m.RoomFactory(record.ID, record.Secret, Options{
+		MaxClients:         record.MaxClients,
+		MaxSpectators:      record.MaxSpectators,
+		Public:             record.Public,
+		Name:               record.Name,
+		Tags:               record.Tags,
+		RelayRateLimit:     record.RelayRateLimit,
+		Template:           record.Template,
+		InviteCode:         inviteCode,
+		MaxLifetime:        durationOf(record.MaxLifetime, time.Millisecond),
+		IdleTimeout:        durationOf(record.IdleTimeout, time.Millisecond),
+		NeverJoinedTimeout: durationOf(record.NeverJoinedTimeout, time.Millisecond),
+
+		ReconnectGracePeriod:   durationOf(record.ReconnectGracePeriod, time.Millisecond),
+		MaxDisconnectedClients: record.MaxDisconnectedClients,
+		ReservationPolicy:      ReservationPolicy(record.ReservationPolicy),
+		ReplayBufferSize:       record.ReplayBufferSize,
+		Permissions:            permissionsFromSnapshot(record.Permissions),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	importable, ok := room.(snapshotRoom)
+	if !ok {
+		err = fmt.Errorf("room with ID %d does not support snapshots", record.ID)
+		return nil, discardRooms(map[int32]Room{record.ID: room}, err)
+	}
+
+	err = importable.Restore(record)
+	if err != nil {
+		return nil, discardRooms(map[int32]Room{record.ID: room}, err)
+	}
+
+	return room, nil
+}
+
+// importedInviteCodeInUse determines if an invite code is used by any existing or already imported rooms
+func importedInviteCodeInUse(rooms map[int32]Room, imported map[int32]Room, code string) (bool, error) {
+	inUse, err := inviteCodeInUse(rooms, code)
+	if err != nil || inUse {
+		return inUse, err
+	}
+	return inviteCodeInUse(imported, code)
+}
+
+// discardRooms removes any state stored outside of the room manager for rooms that were created but will not be added
+// to the manager, the error that caused the rooms to be discarded is returned unless discarding fails
+func discardRooms(rooms map[int32]Room, cause error) error {
+	for _, room := range rooms {
+		discardable, ok := room.(discardableRoom)
+		if !ok {
+			continue
+		}
+		err := discardable.discard()
+		if err != nil {
+			return err
+		}
+	}
+	return cause
+}
+
+// Snapshot generates a snapshot of the room's full state, including both connected and disconnected clients
+func (r *MemoryRoom) Snapshot() *snapshotv1.Room {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	snapshot := &snapshotv1.Room{
+		ID:         r.ID,
+		Secret:     r.Secret,
+		MaxClients: r.MaxClients,
+		HostID:     r.HostID,
+		Status:     snapshotv1.Room_StatusType(r.RoomStatus),
+		Clients:    make([]*snapshotv1.Client, 0, len(r.ConnectedClients)+len(r.DisconnectedClients)),
+
+		NextClientID: r.NextClientID,
+
+		MaxLifetime:        r.MaxLifetime.Milliseconds(),
+		IdleTimeout:        r.IdleTimeout.Milliseconds(),
+		NeverJoinedTimeout: r.NeverJoinedTimeout.Milliseconds(),
+		CreatedAt:          r.CreatedAt.UnixNano() / int64(time.Millisecond),
+		Joined:             r.Joined,
+
+		ReconnectGracePeriod:   r.ReconnectGracePeriod.Milliseconds(),
+		MaxDisconnectedClients: r.MaxDisconnectedClients,
+		ReservationPolicy:      snapshotv1.Room_ReservationPolicyType(r.ReservationPolicy),
+		ReplayBufferSize:       r.ReplayBufferSize,
+		Permissions:            make([]*snapshotv1.RolePermissions, 0, len(r.Permissions)),
+		Properties:             make([]*roomspecv1.Property, 0, len(r.Properties)),
+		PropertiesVersion:      r.PropertiesVersion,
+		Public:                 r.Public,
+		Name:                   r.Name,
+		Tags:                   r.Tags,
+		RelayRateLimit:         r.RelayRateLimit,
+		Template:               r.Template,
+		InviteCode:             r.InviteCode,
+		PasswordHash:           r.PasswordHash,
+	}
+
+	for key, property := range r.Properties {
+		snapshot.Properties = append(snapshot.Properties, &roomspecv1.Property{
+			Key:     key,
+			Value:   property.Value,
+			Version: property.Version,
+		})
+	}
+
+	for role, permissions := range r.Permissions {
+		rolePermissions := &snapshotv1.RolePermissions{
+			Role:        clientv1.SanitisedClient_RoleType(role),
+			Permissions: make([]snapshotv1.RolePermissions_PermissionType, 0, len(permissions)),
+		}
+		for _, permission := range permissions {
+			rolePermissions.Permissions = append(rolePermissions.Permissions, snapshotv1.RolePermissions_PermissionType(permission))
+		}
+		snapshot.Permissions = append(snapshot.Permissions, rolePermissions)
+	}
+
+	for _, connected := range r.ConnectedClients {
+		snapshot.Clients = append(snapshot.Clients, &snapshotv1.Client{
+			ID:        connected.Client.ID,
+			Secret:    connected.Client.Secret,
+			Connected: true,
+			Role:      clientv1.SanitisedClient_RoleType(r.Roles[connected.Client.ID]),
+			Metadata:  r.Metadata[connected.Client.ID],
+		})
+	}
+
+	for _, disconnected := range r.DisconnectedClients {
+		snapshot.Clients = append(snapshot.Clients, &snapshotv1.Client{
+			ID:       disconnected.ID,
+			Secret:   disconnected.Secret,
+			Role:     clientv1.SanitisedClient_RoleType(r.Roles[disconnected.ID]),
+			Metadata: r.Metadata[disconnected.ID],
+		})
+	}
+
+	return snapshot
+}
+
+// Restore restores the room's host, status, clients, properties, password and creation time from a snapshot, sessions
+// cannot be restored so every client is marked as disconnected from when the room is restored, with the room treated as
+// idle from when it is restored if any client has joined it
+func (r *MemoryRoom) Restore(snapshot *snapshotv1.Room) error {
+	if snapshot.ID != r.ID {
+		return fmt.Errorf("cannot restore snapshot of room with ID %d into room with ID %d", snapshot.ID, r.ID)
+	}
+
+	now := time.Now()
+
+	disconnected := make([]*DisconnectedClient, 0, len(snapshot.Clients))
+	roles := make(map[int32]Role)
+	metadata := make(map[int32]map[string]string)
+	for _, client := range snapshot.Clients {
+		disconnected = append(disconnected, &DisconnectedClient{
+			Client: &clientv1.Client{
+				ID:     client.ID,
+				Secret: client.Secret,
+			},
+			DisconnectedAt: now,
+		})
+		if Role(client.Role) != RolePlayer {
+			roles[client.ID] = Role(client.Role)
+		}
+		if len(client.Metadata) > 0 {
+			metadata[client.ID] = client.Metadata
+		}
+	}
+
+	properties := make(map[string]Property, len(snapshot.Properties))
+	for _, property := range snapshot.Properties {
+		properties[property.Key] = Property{
+			Value:   property.Value,
+			Version: property.Version,
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.HostID = snapshot.HostID
+	r.NextClientID = nextClientID(snapshot.NextClientID, disconnected)
+	r.RoomStatus = Status(snapshot.Status)
+	r.ConnectedClients = []*sessionv1.Session{}
+	r.DisconnectedClients = disconnected
+	r.Roles = roles
+	r.Metadata = metadata
+	r.Properties = properties
+	r.PropertiesVersion = snapshot.PropertiesVersion
+	r.PasswordHash = snapshot.PasswordHash
+	r.CreatedAt = time.Unix(0, snapshot.CreatedAt*int64(time.Millisecond))
+	r.Joined = snapshot.Joined
+	r.IdleSince = nil
+	if r.Joined {
+		r.IdleSince = &now
+	}
+
+	return nil
+}
+
+// permissionsFromSnapshot converts the permissions in a snapshot of a room, snapshots without any permissions use the
+// default permissions
+func permissionsFromSnapshot(snapshot []*snapshotv1.RolePermissions) Permissions {
+	if len(snapshot) == 0 {
+		return nil
+	}
+
+	permissions := make(Permissions, len(snapshot))
+	for _, rolePermissions := range snapshot {
+		role := Role(rolePermissions.Role)
+		permissions[role] = make([]Permission, 0, len(rolePermissions.Permissions))
+		for _, permission := range rolePermissions.Permissions {
+			permissions[role] = append(permissions[role], Permission(permission))
+		}
+	}
+	return permissions
+}
+
+// Restore restores the room's host, status and clients from a snapshot, persisting the restored room
+func (r *BoltRoom) Restore(snapshot *snapshotv1.Room) error {
+	err := r.MemoryRoom.Restore(snapshot)
+	if err != nil {
+		return err
+	}
+	return r.persist()
+}
+
+// discard removes the room's persisted state, for a room that was created while importing but is not being added to the
+// room manager
+func (r *BoltRoom) discard() error {
+	return r.DB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRoomsBucket).Delete(boltRoomKey(r.ID))
+	})
+}
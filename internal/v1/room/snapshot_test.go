@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"path/filepath"
+	"testing"
+
+	snapshotv1 "github.com/jamjarlabs/jamjar-relay-server/specs/v1/snapshot"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestImportIsAtomic(t *testing.T) {
+	managers := []struct {
+		name    string
+		manager func(t *testing.T) (*MemoryManager, *bolt.DB)
+	}{
+		{
+			name: "Memory",
+			manager: func(t *testing.T) (*MemoryManager, *bolt.DB) {
+				return newTestManager(), nil
+			},
+		},
+		{
+			name: "Bolt",
+			manager: func(t *testing.T) (*MemoryManager, *bolt.DB) {
+				db := openTestDB(t, filepath.Join(t.TempDir(), "rooms.db"))
+				t.Cleanup(func() { db.Close() })
+				manager, err := NewBoltManager(db, 10000, 1)
+				if err != nil {
+					t.Fatalf("failed to create manager: %v", err)
+				}
+				return manager.MemoryManager, db
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		rooms    []*snapshotv1.Room
+		imported int
+	}{
+		{
+			name: "every room imported",
+			rooms: []*snapshotv1.Room{
+				{ID: 1, Secret: 1, MaxClients: 2, InviteCode: "ABCD2345"},
+				{ID: 2, Secret: 2, MaxClients: 2, InviteCode: "ABCD2345"},
+			},
+			imported: 2,
+		},
+		{
+			name: "invalid room",
+			rooms: []*snapshotv1.Room{
+				{ID: 1, Secret: 1, MaxClients: 2},
+				{ID: 2, Secret: 2, MaxClients: 0},
+			},
+		},
+		{
+			name: "duplicate room",
+			rooms: []*snapshotv1.Room{
+				{ID: 1, Secret: 1, MaxClients: 2},
+				{ID: 1, Secret: 2, MaxClients: 2},
+			},
+		},
+	}
+
+	for _, manager := range managers {
+		for _, tt := range tests {
+			t.Run(manager.name+"/"+tt.name, func(t *testing.T) {
+				m, db := manager.manager(t)
+
+				err := m.Import(&snapshotv1.Snapshot{Version: SnapshotVersion, Rooms: tt.rooms})
+				if tt.imported == 0 && err == nil {
+					t.Fatalf("expected import to fail")
+				}
+				if tt.imported > 0 && err != nil {
+					t.Fatalf("failed to import: %v", err)
+				}
+
+				if len(m.Rooms) != tt.imported {
+					t.Fatalf("got %d rooms, want %d", len(m.Rooms), tt.imported)
+				}
+
+				codes := map[string]bool{}
+				for _, room := range m.Rooms {
+					info, err := room.GetInfo()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if codes[info.InviteCode] {
+						t.Errorf("invite code %s used by more than one room", info.InviteCode)
+					}
+					codes[info.InviteCode] = true
+				}
+
+				if db == nil {
+					return
+				}
+				persisted := 0
+				err = db.View(func(tx *bolt.Tx) error {
+					persisted = tx.Bucket(boltRoomsBucket).Stats().KeyN
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if persisted != tt.imported {
+					t.Fatalf("got %d persisted rooms, want %d", persisted, tt.imported)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+)
+
+// MaxTemplateNameLength is the maximum length of a room template's name
+const MaxTemplateNameLength = 64
+
+var templateNamePattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// OptionsFromSettings converts the settings of a room, as provided when creating a room or registering a template,
+// into room options, it can return an error if the reservation policy, permissions or relay policy are unknown
+func OptionsFromSettings(settings api.RoomSettings) (Options, error) {
+	reservationPolicy, err := ParseReservationPolicy(settings.ReservationPolicy)
+	if err != nil {
+		return Options{}, err
+	}
+
+	permissions, err := ParsePermissions(settings.Permissions)
+	if err != nil {
+		return Options{}, err
+	}
+
+	if len(settings.ClientRelayTypes) > 0 {
+		if len(settings.Permissions) > 0 {
+			return Options{}, ErrInvalidRelayPolicy{
+				Message: "The deprecated client_relay_types cannot be used alongside permissions, use permissions only",
+			}
+		}
+
+		relayPolicy, err := ParseRelayPolicy(settings.ClientRelayTypes)
+		if err != nil {
+			return Options{}, err
+		}
+		relayPolicy.grant(permissions)
+	}
+
+	return Options{
+		MaxClients:         settings.MaxClients,
+		MaxSpectators:      settings.MaxSpectators,
+		Public:             settings.Public,
+		Name:               settings.Name,
+		Tags:               settings.Tags,
+		MaxLifetime:        durationOf(settings.MaxLifetimeSeconds, time.Second),
+		IdleTimeout:        durationOf(settings.IdleTimeoutSeconds, time.Second),
+		NeverJoinedTimeout: durationOf(settings.NeverJoinedTimeoutSeconds, time.Second),
+
+		ReconnectGracePeriod:   durationOf(settings.ReconnectGracePeriodSeconds, time.Second),
+		MaxDisconnectedClients: settings.MaxDisconnectedClients,
+		ReservationPolicy:      reservationPolicy,
+		ReplayBufferSize:       settings.ReplayBufferSize,
+		Permissions:            permissions,
+		RelayRateLimit:         settings.RelayRateLimit,
+		Properties:             settings.Properties,
+	}, nil
+}
+
+// durationOf converts a count of a unit of time into a duration, clamping counts too large to be represented rather
+// than letting them overflow, so a huge timeout becomes the longest possible timeout and a huge negative timeout stays
+// negative and is rejected
+func durationOf(count int64, unit time.Duration) time.Duration {
+	limit := int64(math.MaxInt64 / unit)
+	if count > limit {
+		return time.Duration(limit) * unit
+	}
+	if count < -limit {
+		return time.Duration(-limit) * unit
+	}
+	return time.Duration(count) * unit
+}
+
+// TemplateStore defines a contract for storing room templates, so that templates can be kept in memory, persisted to
+// survive restarts, or shared between relay server nodes
+type TemplateStore interface {
+	// GetTemplate returns the template with the name provided, nil if none found
+	GetTemplate(name string) (*api.RoomTemplate, error)
+	ListTemplates() ([]*api.RoomTemplate, error)
+	PutTemplate(template *api.RoomTemplate) error
+	// DeleteTemplate removes the template with the name provided, returning if it existed
+	DeleteTemplate(name string) (bool, error)
+}
+
+// NewTemplates creates a new set of room templates kept in the store provided
+func NewTemplates(store TemplateStore) *Templates {
+	return &Templates{
+		Store: store,
+	}
+}
+
+// Templates holds named presets of room settings that rooms can be created from, templates can be loaded from config
+// when the server starts and managed at runtime
+type Templates struct {
+	Store TemplateStore
+}
+
+// Get returns a copy of the template with the name provided, if none found an error is returned
+func (t *Templates) Get(name string) (*api.RoomTemplate, error) {
+	template, err := t.Store.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return nil, ErrNoTemplateFound{
+			Message: fmt.Sprintf("No template found with the name '%s'", name),
+		}
+	}
+
+	return template, nil
+}
+
+// List returns a copy of every template, ordered by name
+func (t *Templates) List() ([]*api.RoomTemplate, error) {
+	list, err := t.Store.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list, nil
+}
+
+// Set registers a template, replacing any existing template with the same name. The template's settings must be
+// valid room options, otherwise an error is returned and the template is not registered
+func (t *Templates) Set(template *api.RoomTemplate) error {
+	if len(template.Name) > MaxTemplateNameLength || !templateNamePattern.MatchString(template.Name) {
+		return ErrInvalidTemplate{
+			Message: fmt.Sprintf("Template name '%s' is invalid, names must be 1 to %d letters, digits, '-' or '_'",
+				template.Name, MaxTemplateNameLength),
+		}
+	}
+
+	options, err := OptionsFromSettings(template.RoomSettings)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateOptions(options)
+	if err != nil {
+		return err
+	}
+
+	return t.Store.PutTemplate(template)
+}
+
+// Delete removes the template with the name provided, if none found an error is returned. Rooms already created from
+// the template are not affected
+func (t *Templates) Delete(name string) error {
+	deleted, err := t.Store.DeleteTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrNoTemplateFound{
+			Message: fmt.Sprintf("No template found with the name '%s'", name),
+		}
+	}
+
+	return nil
+}
+
+// Load registers every template in a JSON array of templates, such as a config file, stopping at the first invalid
+// template
+func (t *Templates) Load(reader io.Reader) error {
+	var templates []*api.RoomTemplate
+	err := json.NewDecoder(reader).Decode(&templates)
+	if err != nil {
+		return ErrInvalidTemplate{
+			Message: fmt.Sprintf("Invalid templates provided; %s", err.Error()),
+		}
+	}
+
+	for _, template := range templates {
+		err = t.Set(template)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Options builds the room options for a room created from the template with the name provided, with any settings in
+// the overrides replacing the template's settings. The overrides are JSON room settings, with only the settings
+// present in the JSON overriding the template, nil to use the template's settings unchanged. Properties and
+// permissions in the overrides are merged into the template's, replacing individual properties and roles
+func (t *Templates) Options(name string, overrides []byte) (Options, error) {
+	template, err := t.Get(name)
+	if err != nil {
+		return Options{}, err
+	}
+
+	settings := template.RoomSettings
+	if overrides != nil {
+		err = json.Unmarshal(overrides, &settings)
+		if err != nil {
+			return Options{}, err
+		}
+	}
+
+	options, err := OptionsFromSettings(settings)
+	if err != nil {
+		return Options{}, err
+	}
+
+	options.Template = name
+	return options, nil
+}
+
+// NewMemoryTemplateStore creates a new empty template store that keeps templates in memory
+func NewMemoryTemplateStore() *MemoryTemplateStore {
+	return &MemoryTemplateStore{
+		templates: make(map[string]*api.RoomTemplate),
+	}
+}
+
+// MemoryTemplateStore keeps templates in memory, they are lost when the relay server restarts and are not shared with
+// other nodes
+type MemoryTemplateStore struct {
+	mutex     sync.Mutex
+	templates map[string]*api.RoomTemplate
+}
+
+// GetTemplate returns a copy of the template with the name provided, nil if none found
+func (s *MemoryTemplateStore) GetTemplate(name string) (*api.RoomTemplate, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	template, exists := s.templates[name]
+	if !exists {
+		return nil, nil
+	}
+
+	return copyTemplate(template)
+}
+
+// ListTemplates returns a copy of every template
+func (s *MemoryTemplateStore) ListTemplates() ([]*api.RoomTemplate, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	list := make([]*api.RoomTemplate, 0, len(s.templates))
+	for _, template := range s.templates {
+		copied, err := copyTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, copied)
+	}
+
+	return list, nil
+}
+
+// PutTemplate stores a copy of a template, replacing any existing template with the same name
+func (s *MemoryTemplateStore) PutTemplate(template *api.RoomTemplate) error {
+	copied, err := copyTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.templates[template.Name] = copied
+	return nil
+}
+
+// DeleteTemplate removes the template with the name provided, returning if it existed
+func (s *MemoryTemplateStore) DeleteTemplate(name string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.templates[name]; !exists {
+		return false, nil
+	}
+
+	delete(s.templates, name)
+	return true, nil
+}
+
+// copyTemplate makes a deep copy of a template, so that changes to the copy, such as applying overrides, do not
+// change the original
+func copyTemplate(template *api.RoomTemplate) (*api.RoomTemplate, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &api.RoomTemplate{}
+	err = json.Unmarshal(data, copied)
+	if err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
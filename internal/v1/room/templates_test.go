@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package room
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/api"
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestDB(t *testing.T, path string) *bolt.DB {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
+
+func TestTemplates(t *testing.T) {
+	stores := []struct {
+		name  string
+		store func(t *testing.T) TemplateStore
+	}{
+		{
+			name: "Memory",
+			store: func(t *testing.T) TemplateStore {
+				return NewMemoryTemplateStore()
+			},
+		},
+		{
+			name: "Bolt",
+			store: func(t *testing.T) TemplateStore {
+				db := openTestDB(t, filepath.Join(t.TempDir(), "rooms.db"))
+				t.Cleanup(func() { db.Close() })
+				store, err := NewBoltTemplateStore(db)
+				if err != nil {
+					t.Fatalf("failed to create store: %v", err)
+				}
+				return store
+			},
+		},
+	}
+
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			templates := NewTemplates(s.store(t))
+
+			if _, err := templates.Get("missing"); err == nil {
+				t.Fatalf("expected error getting missing template")
+			} else if _, ok := err.(ErrNoTemplateFound); !ok {
+				t.Fatalf("expected ErrNoTemplateFound, got %T", err)
+			}
+
+			for _, name := range []string{"small", "large"} {
+				err := templates.Set(&api.RoomTemplate{
+					Name:         name,
+					RoomSettings: api.RoomSettings{MaxClients: 4},
+				})
+				if err != nil {
+					t.Fatalf("failed to set template %s: %v", name, err)
+				}
+			}
+
+			err := templates.Set(&api.RoomTemplate{
+				Name:         "small",
+				RoomSettings: api.RoomSettings{MaxClients: 2},
+			})
+			if err != nil {
+				t.Fatalf("failed to replace template: %v", err)
+			}
+
+			template, err := templates.Get("small")
+			if err != nil {
+				t.Fatalf("failed to get template: %v", err)
+			}
+			if template.MaxClients != 2 {
+				t.Errorf("expected replaced max clients 2, got %d", template.MaxClients)
+			}
+
+			list, err := templates.List()
+			if err != nil {
+				t.Fatalf("failed to list templates: %v", err)
+			}
+			if len(list) != 2 || list[0].Name != "large" || list[1].Name != "small" {
+				t.Errorf("expected templates ordered [large small], got %v", list)
+			}
+
+			err = templates.Delete("large")
+			if err != nil {
+				t.Fatalf("failed to delete template: %v", err)
+			}
+			if _, ok := templates.Delete("large").(ErrNoTemplateFound); !ok {
+				t.Errorf("expected ErrNoTemplateFound deleting template twice")
+			}
+		})
+	}
+}
+
+func TestBoltTemplateStoreSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rooms.db")
+
+	db := openTestDB(t, path)
+	store, err := NewBoltTemplateStore(db)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	err = NewTemplates(store).Set(&api.RoomTemplate{
+		Name:         "persisted",
+		RoomSettings: api.RoomSettings{MaxClients: 6},
+	})
+	if err != nil {
+		t.Fatalf("failed to set template: %v", err)
+	}
+	db.Close()
+
+	db = openTestDB(t, path)
+	defer db.Close()
+	store, err = NewBoltTemplateStore(db)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+
+	template, err := NewTemplates(store).Get("persisted")
+	if err != nil {
+		t.Fatalf("expected template to survive restart: %v", err)
+	}
+	if template.MaxClients != 6 {
+		t.Errorf("expected max clients 6, got %d", template.MaxClients)
+	}
+}
+
+func TestOptionsFromSettingsClampsTimeouts(t *testing.T) {
+	longest := time.Duration(math.MaxInt64/int64(time.Second)) * time.Second
+
+	tests := []struct {
+		name     string
+		seconds  int64
+		expected time.Duration
+	}{
+		{name: "no timeout", seconds: 0, expected: 0},
+		{name: "representable timeout", seconds: 90, expected: 90 * time.Second},
+		{name: "largest representable timeout", seconds: math.MaxInt64 / int64(time.Second), expected: longest},
+		{name: "overflowing timeout", seconds: math.MaxInt64, expected: longest},
+		{name: "overflowing negative timeout", seconds: math.MinInt64, expected: -longest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := OptionsFromSettings(api.RoomSettings{
+				MaxClients:                  2,
+				MaxLifetimeSeconds:          tt.seconds,
+				IdleTimeoutSeconds:          tt.seconds,
+				NeverJoinedTimeoutSeconds:   tt.seconds,
+				ReconnectGracePeriodSeconds: tt.seconds,
+			})
+			if err != nil {
+				t.Fatalf("failed to convert settings: %v", err)
+			}
+			for _, timeout := range []time.Duration{options.MaxLifetime, options.IdleTimeout,
+				options.NeverJoinedTimeout, options.ReconnectGracePeriod} {
+				if timeout != tt.expected {
+					t.Errorf("expected %d, got %d", tt.expected, timeout)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsFromSettingsRelayPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		relayTypes  []string
+		permissions map[string][]string
+		relayed     []string
+		err         bool
+	}{
+		{name: "no relay types", relayed: []string{}},
+		{name: "broadcast", relayTypes: []string{"BROADCAST"}, relayed: []string{"BROADCAST", "EXCEPT"}},
+		{name: "multicast", relayTypes: []string{"MULTICAST"}, relayed: []string{"TARGET", "MULTICAST"}},
+		{
+			name:       "every relay type",
+			relayTypes: []string{"BROADCAST", "TARGET", "MULTICAST", "EXCEPT", "HOST"},
+			relayed:    []string{"BROADCAST", "EXCEPT", "TARGET", "MULTICAST"},
+		},
+		{name: "unknown relay type", relayTypes: []string{"EVERYONE"}, err: true},
+		{
+			name:        "alongside permissions",
+			relayTypes:  []string{"BROADCAST"},
+			permissions: map[string][]string{"PLAYER": {"LIST"}},
+			err:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := OptionsFromSettings(api.RoomSettings{
+				MaxClients:       2,
+				ClientRelayTypes: tt.relayTypes,
+				Permissions:      tt.permissions,
+			})
+			if tt.err {
+				if _, ok := err.(ErrInvalidRelayPolicy); !ok {
+					t.Fatalf("expected an invalid relay policy error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to convert settings: %v", err)
+			}
+
+			if relayed := options.Permissions.RelayTypes(); !reflect.DeepEqual(relayed, tt.relayed) {
+				t.Errorf("expected players to relay %v, got %v", tt.relayed, relayed)
+			}
+			for _, permission := range []Permission{PermissionBroadcast, PermissionTarget} {
+				if options.Permissions.Allows(RolePlayer, permission) &&
+					!options.Permissions.Allows(RoleModerator, permission) {
+					t.Errorf("expected moderators to be granted %s alongside players", permission)
+				}
+			}
+		})
+	}
+}
@@ -17,20 +17,57 @@ limitations under the License.
 package session
 
 import (
+	"sync"
+
 	"github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
 )
 
 // Session defines a currently connected client, with connection agnostic ways for writing and closing
 type Session struct {
-	CloseSignal chan struct{}
-	Closed      bool
-	Write       chan []byte
-	Client      *client.Client
-	RoomID      *int32
+	CloseSignal     chan struct{}
+	Closed          bool
+	Write           chan []byte
+	Client          *client.Client
+	RoomID          *int32
+	ProtocolVersion int32
+	Capabilities    []string
+	// Matched wakes the session's goroutine when the server has matched the session into a room, such as when
+	// matchmaking, so that the goroutine handling the client's requests is the one that joins the room
+	Matched chan struct{}
+	// PasswordFailures is how many wrong invite codes or passwords the client has given on this connection, only used
+	// by the session's own goroutine
+	PasswordFailures int32
+
+	closeMutex sync.Mutex
 }
 
-// Close closes a session, disconnecting a client from the relay
+// Close closes a session, disconnecting a client from the relay, closing an already closed session does nothing. A
+// session can be closed by its own goroutine and by the reaper at the same time
 func (s *Session) Close() {
+	s.closeMutex.Lock()
+	defer s.closeMutex.Unlock()
+	if s.Closed {
+		return
+	}
 	s.Closed = true
 	close(s.CloseSignal)
 }
+
+// HasCapability determines if a capability was agreed with the client when the session's protocol was negotiated
+func (s *Session) HasCapability(capability string) bool {
+	for _, agreed := range s.Capabilities {
+		if agreed == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyMatched wakes the session's goroutine to join the room the server has matched it into, if the session is
+// already due to wake, or has no goroutine to wake, this does nothing
+func (s *Session) NotifyMatched() {
+	select {
+	case s.Matched <- struct{}{}:
+	default:
+	}
+}
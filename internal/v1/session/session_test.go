@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The JamJar Relay Server Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentClose(t *testing.T) {
+	connected := &Session{
+		CloseSignal: make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connected.Close()
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-connected.CloseSignal:
+	default:
+		t.Errorf("expected session to be closed")
+	}
+}
+
+func TestNotifyMatched(t *testing.T) {
+	tests := []struct {
+		name    string
+		matched chan struct{}
+		wakes   bool
+	}{
+		{name: "session with a goroutine to wake", matched: make(chan struct{}, 1), wakes: true},
+		{name: "session without a goroutine to wake", matched: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connected := &Session{Matched: tt.matched}
+			// Notifying more than once must not block while the session is already due to wake
+			connected.NotifyMatched()
+			connected.NotifyMatched()
+
+			select {
+			case <-connected.Matched:
+				if !tt.wakes {
+					t.Errorf("expected session not to be woken")
+				}
+			default:
+				if tt.wakes {
+					t.Errorf("expected session to be woken")
+				}
+			}
+		})
+	}
+}
@@ -16,9 +16,65 @@ limitations under the License.
 
 package api
 
-// RoomCreationRequest defines the data needed to create a new room
+import "time"
+
+// RoomCreationRequest defines the data needed to create a new room, either directly or from a template. Any settings
+// included in a request that references a template override the template's settings
 type RoomCreationRequest struct {
+	// Template is the name of the template to create the room from, omitted to create the room from the settings in
+	// the request alone
+	Template string `json:"template,omitempty"`
+	// Password is required to join the room using its invite code, omitted for no password. Only a hash of the
+	// password is kept by the server
+	Password string `json:"password,omitempty"`
+	RoomSettings
+}
+
+// RoomTemplate defines a named preset of room settings that rooms can be created from
+type RoomTemplate struct {
+	Name string `json:"name"`
+	RoomSettings
+}
+
+// RoomSettings defines the configurable settings of a room
+type RoomSettings struct {
 	MaxClients int32 `json:"max_clients"`
+	// MaxSpectators is how many receive-only spectators can be connected at once, separate from MaxClients, zero or
+	// omitted to not allow spectators
+	MaxSpectators int32 `json:"max_spectators,omitempty"`
+	// Public determines if the room is listed for players browsing for a room to join, with its name and tags, the
+	// room's secret is never listed
+	Public bool     `json:"public,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	// MaxLifetimeSeconds is how long the room can exist for, zero or omitted for no limit
+	MaxLifetimeSeconds int64 `json:"max_lifetime_seconds,omitempty"`
+	// IdleTimeoutSeconds is how long the room can exist for after the last client leaves, zero or omitted for no limit
+	IdleTimeoutSeconds int64 `json:"idle_timeout_seconds,omitempty"`
+	// NeverJoinedTimeoutSeconds is how long the room can exist for if no client ever joins, zero or omitted for no
+	// limit
+	NeverJoinedTimeoutSeconds int64 `json:"never_joined_timeout_seconds,omitempty"`
+	// ReconnectGracePeriodSeconds is how long a disconnected client can reconnect for, zero or omitted for no limit
+	ReconnectGracePeriodSeconds int64 `json:"reconnect_grace_period_seconds,omitempty"`
+	// MaxDisconnectedClients is how many disconnected clients are remembered, zero or omitted for no limit
+	MaxDisconnectedClients int32 `json:"max_disconnected_clients,omitempty"`
+	// ReservationPolicy is either FIRST_COME (default) or RESERVE, to reserve slots for disconnected clients
+	ReservationPolicy string `json:"reservation_policy,omitempty"`
+	// ReplayBufferSize is how many relayed messages are stored for each client to replay on reconnect, zero or omitted
+	// to disable
+	ReplayBufferSize int32 `json:"replay_buffer_size,omitempty"`
+	// Permissions are the permissions granted to each role other than HOST, by role name (PLAYER, MODERATOR or
+	// SPECTATOR), any of BROADCAST, TARGET, KICK, GRANT_HOST, GRANT_ROLE, LIST or SET_PROPERTIES. Roles omitted keep
+	// their default permissions
+	Permissions map[string][]string `json:"permissions,omitempty"`
+	// ClientRelayTypes are the relay types that clients other than the host may use in addition to HOST, any of
+	// BROADCAST, TARGET, MULTICAST or EXCEPT, omitted to only allow relaying to the host. Deprecated, use Permissions;
+	// BROADCAST or EXCEPT grant PLAYER and MODERATOR the BROADCAST permission, TARGET or MULTICAST grant TARGET
+	ClientRelayTypes []string `json:"client_relay_types,omitempty"`
+	// RelayRateLimit is how many messages each client can relay per second, zero or omitted for no limit
+	RelayRateLimit int32 `json:"relay_rate_limit,omitempty"`
+	// Properties are the values the room's properties start with, by key
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 // RoomInfo defines useful information about a room that can be easily serialised
@@ -28,6 +84,53 @@ type RoomInfo struct {
 	MaxClients     int32  `json:"max_clients"`
 	CurrentClients int32  `json:"current_clients"`
 	RoomStatus     string `json:"room_status"`
+	Node           string `json:"node,omitempty"`
+	// ExpiresAt is when the room will be closed automatically, omitted if the room has no applicable timeouts
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// MaxSpectators and CurrentSpectators are the spectator slots, spectators are not included in CurrentClients
+	MaxSpectators     int32 `json:"max_spectators"`
+	CurrentSpectators int32 `json:"current_spectators"`
+	// ReservedClients is the number of slots reserved for disconnected clients that can still reconnect
+	ReservedClients   int32  `json:"reserved_clients"`
+	ReservationPolicy string `json:"reservation_policy"`
+	// Permissions are the permissions granted to each role other than HOST, by role name
+	Permissions map[string][]string `json:"permissions"`
+	// ClientRelayTypes are the relay types that clients in the PLAYER role may use in addition to HOST. Deprecated, use
+	// Permissions
+	ClientRelayTypes []string `json:"client_relay_types"`
+	// Properties are the values of the room's properties, by key
+	Properties map[string]string `json:"properties"`
+	Public     bool              `json:"public"`
+	Name       string            `json:"name,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	// RelayRateLimit is how many messages each client can relay per second, zero for no limit
+	RelayRateLimit int32 `json:"relay_rate_limit"`
+	// Template is the name of the template the room was created from, omitted if it was not created from a template
+	Template string `json:"template,omitempty"`
+	// InviteCode is the short code players can use to join the room instead of its ID and secret
+	InviteCode string `json:"invite_code,omitempty"`
+	// PasswordProtected determines if a password is required to join the room using its invite code
+	PasswordProtected bool `json:"password_protected"`
+}
+
+// PublicRoomInfo defines the information about a public room that can be shown to players browsing for a room to join,
+// it never includes the room's secret
+type PublicRoomInfo struct {
+	ID                int32             `json:"id"`
+	Name              string            `json:"name"`
+	Tags              []string          `json:"tags"`
+	MaxClients        int32             `json:"max_clients"`
+	CurrentClients    int32             `json:"current_clients"`
+	MaxSpectators     int32             `json:"max_spectators"`
+	CurrentSpectators int32             `json:"current_spectators"`
+	Properties        map[string]string `json:"properties"`
+}
+
+// PublicRoomList defines a page of public rooms, with the total number of public rooms matching the filter used
+type PublicRoomList struct {
+	Rooms  []*PublicRoomInfo `json:"rooms"`
+	Total  int32             `json:"total"`
+	Offset int32             `json:"offset"`
 }
 
 // RoomsSummary defines a grouped summary of multiple rooms, useful for seeing the overall state of the relay server
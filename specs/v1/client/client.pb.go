@@ -35,12 +35,65 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SanitisedClient_RoleType int32
+
+const (
+	SanitisedClient_PLAYER    SanitisedClient_RoleType = 0
+	SanitisedClient_MODERATOR SanitisedClient_RoleType = 1
+	SanitisedClient_SPECTATOR SanitisedClient_RoleType = 2
+	SanitisedClient_HOST      SanitisedClient_RoleType = 3
+)
+
+// Enum value maps for SanitisedClient_RoleType.
+var (
+	SanitisedClient_RoleType_name = map[int32]string{
+		0: "PLAYER",
+		1: "MODERATOR",
+		2: "SPECTATOR",
+		3: "HOST",
+	}
+	SanitisedClient_RoleType_value = map[string]int32{
+		"PLAYER":    0,
+		"MODERATOR": 1,
+		"SPECTATOR": 2,
+		"HOST":      3,
+	}
+)
+
+func (x SanitisedClient_RoleType) Enum() *SanitisedClient_RoleType {
+	p := new(SanitisedClient_RoleType)
+	*p = x
+	return p
+}
+
+func (x SanitisedClient_RoleType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SanitisedClient_RoleType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_client_client_proto_enumTypes[0].Descriptor()
+}
+
+func (SanitisedClient_RoleType) Type() protoreflect.EnumType {
+	return &file_v1_client_client_proto_enumTypes[0]
+}
+
+func (x SanitisedClient_RoleType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SanitisedClient_RoleType.Descriptor instead.
+func (SanitisedClient_RoleType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_client_client_proto_rawDescGZIP(), []int{2, 0}
+}
+
 type ClientList struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	List []*SanitisedClient `protobuf:"bytes,1,rep,name=List,proto3" json:"List,omitempty"`
+	List       []*SanitisedClient `protobuf:"bytes,1,rep,name=List,proto3" json:"List,omitempty"`
+	Spectators []*SanitisedClient `protobuf:"bytes,2,rep,name=Spectators,proto3" json:"Spectators,omitempty"`
 }
 
 func (x *ClientList) Reset() {
@@ -82,6 +135,13 @@ func (x *ClientList) GetList() []*SanitisedClient {
 	return nil
 }
 
+func (x *ClientList) GetSpectators() []*SanitisedClient {
+	if x != nil {
+		return x.Spectators
+	}
+	return nil
+}
+
 type Client struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -142,8 +202,10 @@ type SanitisedClient struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID   int32 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Host bool  `protobuf:"varint,2,opt,name=Host,proto3" json:"Host,omitempty"`
+	ID       int32                    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Host     bool                     `protobuf:"varint,2,opt,name=Host,proto3" json:"Host,omitempty"`
+	Role     SanitisedClient_RoleType `protobuf:"varint,3,opt,name=Role,proto3,enum=v1_client.SanitisedClient_RoleType" json:"Role,omitempty"`
+	Metadata map[string]string        `protobuf:"bytes,4,rep,name=Metadata,proto3" json:"Metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *SanitisedClient) Reset() {
@@ -192,26 +254,60 @@ func (x *SanitisedClient) GetHost() bool {
 	return false
 }
 
+func (x *SanitisedClient) GetRole() SanitisedClient_RoleType {
+	if x != nil {
+		return x.Role
+	}
+	return SanitisedClient_PLAYER
+}
+
+func (x *SanitisedClient) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 var File_v1_client_client_proto protoreflect.FileDescriptor
 
 var file_v1_client_client_proto_rawDesc = []byte{
 	0x0a, 0x16, 0x76, 0x31, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x6c, 0x69, 0x65,
 	0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x76, 0x31, 0x5f, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x0a, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73,
+	0x65, 0x6e, 0x74, 0x22, 0x78, 0x0a, 0x0a, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73,
 	0x74, 0x12, 0x2e, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
 	0x1a, 0x2e, 0x76, 0x31, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x61, 0x6e, 0x69,
 	0x74, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x4c, 0x69, 0x73,
-	0x74, 0x22, 0x30, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49,
-	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x22, 0x35, 0x0a, 0x0f, 0x53, 0x61, 0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64,
-	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x02, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x6f, 0x73, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x48, 0x6f, 0x73, 0x74, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x6c,
-	0x61, 0x62, 0x73, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79,
-	0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x73, 0x2f, 0x76, 0x31,
-	0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x12, 0x3a, 0x0a, 0x0a, 0x53, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x31, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x2e, 0x53, 0x61, 0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x52, 0x0a, 0x53, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x30, 0x0a,
+	0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22,
+	0xb1, 0x02, 0x0a, 0x0f, 0x53, 0x61, 0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x02, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x04, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x76, 0x31, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x2e, 0x53, 0x61, 0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x65,
+	0x12, 0x44, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x28, 0x2e, 0x76, 0x31, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x53,
+	0x61, 0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x3e, 0x0a, 0x08, 0x52, 0x6f, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x0a, 0x0a, 0x06, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4d,
+	0x4f, 0x44, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x50,
+	0x45, 0x43, 0x54, 0x41, 0x54, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x4f, 0x53,
+	0x54, 0x10, 0x03, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x6a, 0x61, 0x6d,
+	0x6a, 0x61, 0x72, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2f, 0x73, 0x70, 0x65, 0x63, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -226,19 +322,25 @@ func file_v1_client_client_proto_rawDescGZIP() []byte {
 	return file_v1_client_client_proto_rawDescData
 }
 
-var file_v1_client_client_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_v1_client_client_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_v1_client_client_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_v1_client_client_proto_goTypes = []interface{}{
-	(*ClientList)(nil),      // 0: v1_client.ClientList
-	(*Client)(nil),          // 1: v1_client.Client
-	(*SanitisedClient)(nil), // 2: v1_client.SanitisedClient
+	(SanitisedClient_RoleType)(0), // 0: v1_client.SanitisedClient.RoleType
+	(*ClientList)(nil),            // 1: v1_client.ClientList
+	(*Client)(nil),                // 2: v1_client.Client
+	(*SanitisedClient)(nil),       // 3: v1_client.SanitisedClient
+	nil,                           // 4: v1_client.SanitisedClient.MetadataEntry
 }
 var file_v1_client_client_proto_depIdxs = []int32{
-	2, // 0: v1_client.ClientList.List:type_name -> v1_client.SanitisedClient
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: v1_client.ClientList.List:type_name -> v1_client.SanitisedClient
+	3, // 1: v1_client.ClientList.Spectators:type_name -> v1_client.SanitisedClient
+	0, // 2: v1_client.SanitisedClient.Role:type_name -> v1_client.SanitisedClient.RoleType
+	4, // 3: v1_client.SanitisedClient.Metadata:type_name -> v1_client.SanitisedClient.MetadataEntry
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_v1_client_client_proto_init() }
@@ -289,13 +391,14 @@ func file_v1_client_client_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_client_client_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_v1_client_client_proto_goTypes,
 		DependencyIndexes: file_v1_client_client_proto_depIdxs,
+		EnumInfos:         file_v1_client_client_proto_enumTypes,
 		MessageInfos:      file_v1_client_client_proto_msgTypes,
 	}.Build()
 	File_v1_client_client_proto = out.File
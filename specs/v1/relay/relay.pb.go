@@ -22,6 +22,7 @@
 package relay
 
 import (
+	transport "github.com/jamjarlabs/jamjar-relay-server/specs/v1/transport"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -41,6 +42,8 @@ const (
 	Relay_BROADCAST Relay_RelayType = 0
 	Relay_TARGET    Relay_RelayType = 1
 	Relay_HOST      Relay_RelayType = 2
+	Relay_MULTICAST Relay_RelayType = 3
+	Relay_EXCEPT    Relay_RelayType = 4
 )
 
 // Enum value maps for Relay_RelayType.
@@ -49,11 +52,15 @@ var (
 		0: "BROADCAST",
 		1: "TARGET",
 		2: "HOST",
+		3: "MULTICAST",
+		4: "EXCEPT",
 	}
 	Relay_RelayType_value = map[string]int32{
 		"BROADCAST": 0,
 		"TARGET":    1,
 		"HOST":      2,
+		"MULTICAST": 3,
+		"EXCEPT":    4,
 	}
 )
 
@@ -89,9 +96,10 @@ type Relay struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type   Relay_RelayType `protobuf:"varint,1,opt,name=Type,proto3,enum=v1_relay.Relay_RelayType" json:"Type,omitempty"`
-	Target *int32          `protobuf:"varint,2,opt,name=Target,proto3,oneof" json:"Target,omitempty"`
-	Data   []byte          `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
+	Type    Relay_RelayType `protobuf:"varint,1,opt,name=Type,proto3,enum=v1_relay.Relay_RelayType" json:"Type,omitempty"`
+	Target  *int32          `protobuf:"varint,2,opt,name=Target,proto3,oneof" json:"Target,omitempty"`
+	Data    []byte          `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
+	Targets []int32         `protobuf:"varint,4,rep,packed,name=Targets,proto3" json:"Targets,omitempty"`
 }
 
 func (x *Relay) Reset() {
@@ -147,26 +155,340 @@ func (x *Relay) GetData() []byte {
 	return nil
 }
 
+func (x *Relay) GetTargets() []int32 {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type RelayedMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sender int32           `protobuf:"varint,1,opt,name=Sender,proto3" json:"Sender,omitempty"`
+	Type   Relay_RelayType `protobuf:"varint,2,opt,name=Type,proto3,enum=v1_relay.Relay_RelayType" json:"Type,omitempty"`
+	SentAt int64           `protobuf:"varint,3,opt,name=SentAt,proto3" json:"SentAt,omitempty"`
+	Relay  *Relay          `protobuf:"bytes,4,opt,name=Relay,proto3" json:"Relay,omitempty"`
+}
+
+func (x *RelayedMessage) Reset() {
+	*x = RelayedMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_relay_relay_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelayedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayedMessage) ProtoMessage() {}
+
+func (x *RelayedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_relay_relay_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayedMessage.ProtoReflect.Descriptor instead.
+func (*RelayedMessage) Descriptor() ([]byte, []int) {
+	return file_v1_relay_relay_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RelayedMessage) GetSender() int32 {
+	if x != nil {
+		return x.Sender
+	}
+	return 0
+}
+
+func (x *RelayedMessage) GetType() Relay_RelayType {
+	if x != nil {
+		return x.Type
+	}
+	return Relay_BROADCAST
+}
+
+func (x *RelayedMessage) GetSentAt() int64 {
+	if x != nil {
+		return x.SentAt
+	}
+	return 0
+}
+
+func (x *RelayedMessage) GetRelay() *Relay {
+	if x != nil {
+		return x.Relay
+	}
+	return nil
+}
+
+type RelayFailures struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Failures []*RelayFailure `protobuf:"bytes,1,rep,name=Failures,proto3" json:"Failures,omitempty"`
+}
+
+func (x *RelayFailures) Reset() {
+	*x = RelayFailures{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_relay_relay_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelayFailures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayFailures) ProtoMessage() {}
+
+func (x *RelayFailures) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_relay_relay_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayFailures.ProtoReflect.Descriptor instead.
+func (*RelayFailures) Descriptor() ([]byte, []int) {
+	return file_v1_relay_relay_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RelayFailures) GetFailures() []*RelayFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+type RelayFailure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target int32            `protobuf:"varint,1,opt,name=Target,proto3" json:"Target,omitempty"`
+	Error  *transport.Error `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+}
+
+func (x *RelayFailure) Reset() {
+	*x = RelayFailure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_relay_relay_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelayFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayFailure) ProtoMessage() {}
+
+func (x *RelayFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_relay_relay_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayFailure.ProtoReflect.Descriptor instead.
+func (*RelayFailure) Descriptor() ([]byte, []int) {
+	return file_v1_relay_relay_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RelayFailure) GetTarget() int32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *RelayFailure) GetError() *transport.Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type ReplayGap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LastSequence  uint64 `protobuf:"varint,1,opt,name=LastSequence,proto3" json:"LastSequence,omitempty"`
+	FirstSequence uint64 `protobuf:"varint,2,opt,name=FirstSequence,proto3" json:"FirstSequence,omitempty"`
+}
+
+func (x *ReplayGap) Reset() {
+	*x = ReplayGap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_relay_relay_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayGap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayGap) ProtoMessage() {}
+
+func (x *ReplayGap) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_relay_relay_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayGap.ProtoReflect.Descriptor instead.
+func (*ReplayGap) Descriptor() ([]byte, []int) {
+	return file_v1_relay_relay_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReplayGap) GetLastSequence() uint64 {
+	if x != nil {
+		return x.LastSequence
+	}
+	return 0
+}
+
+func (x *ReplayGap) GetFirstSequence() uint64 {
+	if x != nil {
+		return x.FirstSequence
+	}
+	return 0
+}
+
+type Ack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sequence uint64 `protobuf:"varint,1,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_relay_relay_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_relay_relay_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_v1_relay_relay_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Ack) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 var File_v1_relay_relay_proto protoreflect.FileDescriptor
 
 var file_v1_relay_relay_proto_rawDesc = []byte{
 	0x0a, 0x14, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79,
-	0x22, 0xa4, 0x01, 0x0a, 0x05, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x2d, 0x0a, 0x04, 0x54, 0x79,
-	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x76, 0x31, 0x5f, 0x72, 0x65,
-	0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x06, 0x54, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x54, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0x30, 0x0a, 0x09, 0x52, 0x65,
-	0x6c, 0x61, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x52, 0x4f, 0x41, 0x44,
-	0x43, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54,
-	0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x02, 0x42, 0x09, 0x0a, 0x07,
-	0x5f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x6c, 0x61, 0x62, 0x73,
-	0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2d, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x72, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65,
-	0x6c, 0x61, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x1a, 0x1c, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd9,
+	0x01, 0x0a, 0x05, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x2d, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x6c, 0x61,
+	0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x73, 0x22, 0x4b, 0x0a, 0x09, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x0d, 0x0a, 0x09, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0a,
+	0x0a, 0x06, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x4f,
+	0x53, 0x54, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x43, 0x41, 0x53,
+	0x54, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x58, 0x43, 0x45, 0x50, 0x54, 0x10, 0x04, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x96, 0x01, 0x0a, 0x0e, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x52,
+	0x65, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x65, 0x6e, 0x74, 0x41, 0x74, 0x12, 0x25, 0x0a, 0x05,
+	0x52, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x76, 0x31,
+	0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x52, 0x05, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x22, 0x43, 0x0a, 0x0d, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x46, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x31, 0x5f, 0x72, 0x65, 0x6c, 0x61,
+	0x79, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x08,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x61,
+	0x79, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x12, 0x29, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x76, 0x31, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x55, 0x0a, 0x09, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x47, 0x61, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x4c, 0x61, 0x73, 0x74,
+	0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x24, 0x0a, 0x0d,
+	0x46, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0d, 0x46, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x22, 0x21, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x53, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x6a,
+	0x61, 0x6d, 0x6a, 0x61, 0x72, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x6c, 0x61,
+	0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -182,18 +504,28 @@ func file_v1_relay_relay_proto_rawDescGZIP() []byte {
 }
 
 var file_v1_relay_relay_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_v1_relay_relay_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_v1_relay_relay_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_v1_relay_relay_proto_goTypes = []interface{}{
-	(Relay_RelayType)(0), // 0: v1_relay.Relay.RelayType
-	(*Relay)(nil),        // 1: v1_relay.Relay
+	(Relay_RelayType)(0),    // 0: v1_relay.Relay.RelayType
+	(*Relay)(nil),           // 1: v1_relay.Relay
+	(*RelayedMessage)(nil),  // 2: v1_relay.RelayedMessage
+	(*RelayFailures)(nil),   // 3: v1_relay.RelayFailures
+	(*RelayFailure)(nil),    // 4: v1_relay.RelayFailure
+	(*ReplayGap)(nil),       // 5: v1_relay.ReplayGap
+	(*Ack)(nil),             // 6: v1_relay.Ack
+	(*transport.Error)(nil), // 7: v1_transport.Error
 }
 var file_v1_relay_relay_proto_depIdxs = []int32{
 	0, // 0: v1_relay.Relay.Type:type_name -> v1_relay.Relay.RelayType
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0, // 1: v1_relay.RelayedMessage.Type:type_name -> v1_relay.Relay.RelayType
+	1, // 2: v1_relay.RelayedMessage.Relay:type_name -> v1_relay.Relay
+	4, // 3: v1_relay.RelayFailures.Failures:type_name -> v1_relay.RelayFailure
+	7, // 4: v1_relay.RelayFailure.Error:type_name -> v1_transport.Error
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_v1_relay_relay_proto_init() }
@@ -214,6 +546,66 @@ func file_v1_relay_relay_proto_init() {
 				return nil
 			}
 		}
+		file_v1_relay_relay_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelayedMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_relay_relay_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelayFailures); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_relay_relay_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelayFailure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_relay_relay_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayGap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_relay_relay_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_v1_relay_relay_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	type x struct{}
@@ -222,7 +614,7 @@ func file_v1_relay_relay_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_relay_relay_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   1,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
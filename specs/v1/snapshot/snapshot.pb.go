@@ -0,0 +1,886 @@
+//
+//Copyright 2021 The JamJar Relay Server Authors.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.15.6
+// source: v1/snapshot/snapshot.proto
+
+package snapshot
+
+import (
+	client "github.com/jamjarlabs/jamjar-relay-server/specs/v1/client"
+	room "github.com/jamjarlabs/jamjar-relay-server/specs/v1/room"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Room_StatusType int32
+
+const (
+	Room_RUNNING Room_StatusType = 0
+	Room_CLOSING Room_StatusType = 1
+)
+
+// Enum value maps for Room_StatusType.
+var (
+	Room_StatusType_name = map[int32]string{
+		0: "RUNNING",
+		1: "CLOSING",
+	}
+	Room_StatusType_value = map[string]int32{
+		"RUNNING": 0,
+		"CLOSING": 1,
+	}
+)
+
+func (x Room_StatusType) Enum() *Room_StatusType {
+	p := new(Room_StatusType)
+	*p = x
+	return p
+}
+
+func (x Room_StatusType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Room_StatusType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_snapshot_snapshot_proto_enumTypes[0].Descriptor()
+}
+
+func (Room_StatusType) Type() protoreflect.EnumType {
+	return &file_v1_snapshot_snapshot_proto_enumTypes[0]
+}
+
+func (x Room_StatusType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Room_StatusType.Descriptor instead.
+func (Room_StatusType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type Room_ReservationPolicyType int32
+
+const (
+	Room_FIRST_COME Room_ReservationPolicyType = 0
+	Room_RESERVE    Room_ReservationPolicyType = 1
+)
+
+// Enum value maps for Room_ReservationPolicyType.
+var (
+	Room_ReservationPolicyType_name = map[int32]string{
+		0: "FIRST_COME",
+		1: "RESERVE",
+	}
+	Room_ReservationPolicyType_value = map[string]int32{
+		"FIRST_COME": 0,
+		"RESERVE":    1,
+	}
+)
+
+func (x Room_ReservationPolicyType) Enum() *Room_ReservationPolicyType {
+	p := new(Room_ReservationPolicyType)
+	*p = x
+	return p
+}
+
+func (x Room_ReservationPolicyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Room_ReservationPolicyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_snapshot_snapshot_proto_enumTypes[1].Descriptor()
+}
+
+func (Room_ReservationPolicyType) Type() protoreflect.EnumType {
+	return &file_v1_snapshot_snapshot_proto_enumTypes[1]
+}
+
+func (x Room_ReservationPolicyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Room_ReservationPolicyType.Descriptor instead.
+func (Room_ReservationPolicyType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{1, 1}
+}
+
+type RolePermissions_PermissionType int32
+
+const (
+	RolePermissions_BROADCAST      RolePermissions_PermissionType = 0
+	RolePermissions_TARGET         RolePermissions_PermissionType = 1
+	RolePermissions_KICK           RolePermissions_PermissionType = 2
+	RolePermissions_GRANT_HOST     RolePermissions_PermissionType = 3
+	RolePermissions_GRANT_ROLE     RolePermissions_PermissionType = 4
+	RolePermissions_LIST           RolePermissions_PermissionType = 5
+	RolePermissions_SET_PROPERTIES RolePermissions_PermissionType = 6
+)
+
+// Enum value maps for RolePermissions_PermissionType.
+var (
+	RolePermissions_PermissionType_name = map[int32]string{
+		0: "BROADCAST",
+		1: "TARGET",
+		2: "KICK",
+		3: "GRANT_HOST",
+		4: "GRANT_ROLE",
+		5: "LIST",
+		6: "SET_PROPERTIES",
+	}
+	RolePermissions_PermissionType_value = map[string]int32{
+		"BROADCAST":      0,
+		"TARGET":         1,
+		"KICK":           2,
+		"GRANT_HOST":     3,
+		"GRANT_ROLE":     4,
+		"LIST":           5,
+		"SET_PROPERTIES": 6,
+	}
+)
+
+func (x RolePermissions_PermissionType) Enum() *RolePermissions_PermissionType {
+	p := new(RolePermissions_PermissionType)
+	*p = x
+	return p
+}
+
+func (x RolePermissions_PermissionType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RolePermissions_PermissionType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_snapshot_snapshot_proto_enumTypes[2].Descriptor()
+}
+
+func (RolePermissions_PermissionType) Type() protoreflect.EnumType {
+	return &file_v1_snapshot_snapshot_proto_enumTypes[2]
+}
+
+func (x RolePermissions_PermissionType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RolePermissions_PermissionType.Descriptor instead.
+func (RolePermissions_PermissionType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{3, 0}
+}
+
+type Snapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version int32   `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	Rooms   []*Room `protobuf:"bytes,2,rep,name=Rooms,proto3" json:"Rooms,omitempty"`
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_snapshot_snapshot_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_snapshot_snapshot_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Snapshot) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Snapshot) GetRooms() []*Room {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+type Room struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID         int32           `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Secret     int32           `protobuf:"varint,2,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	MaxClients int32           `protobuf:"varint,3,opt,name=MaxClients,proto3" json:"MaxClients,omitempty"`
+	HostID     *int32          `protobuf:"varint,4,opt,name=HostID,proto3,oneof" json:"HostID,omitempty"`
+	Status     Room_StatusType `protobuf:"varint,5,opt,name=Status,proto3,enum=v1_snapshot.Room_StatusType" json:"Status,omitempty"`
+	Clients    []*Client       `protobuf:"bytes,6,rep,name=Clients,proto3" json:"Clients,omitempty"`
+	// MaxLifetime, IdleTimeout and NeverJoinedTimeout are in milliseconds, zero if disabled
+	MaxLifetime        int64 `protobuf:"varint,7,opt,name=MaxLifetime,proto3" json:"MaxLifetime,omitempty"`
+	IdleTimeout        int64 `protobuf:"varint,8,opt,name=IdleTimeout,proto3" json:"IdleTimeout,omitempty"`
+	NeverJoinedTimeout int64 `protobuf:"varint,9,opt,name=NeverJoinedTimeout,proto3" json:"NeverJoinedTimeout,omitempty"`
+	// CreatedAt is a unix timestamp in milliseconds
+	CreatedAt int64 `protobuf:"varint,10,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	Joined    bool  `protobuf:"varint,11,opt,name=Joined,proto3" json:"Joined,omitempty"`
+	// ReconnectGracePeriod is in milliseconds, zero if disabled
+	ReconnectGracePeriod   int64                      `protobuf:"varint,12,opt,name=ReconnectGracePeriod,proto3" json:"ReconnectGracePeriod,omitempty"`
+	MaxDisconnectedClients int32                      `protobuf:"varint,13,opt,name=MaxDisconnectedClients,proto3" json:"MaxDisconnectedClients,omitempty"`
+	ReservationPolicy      Room_ReservationPolicyType `protobuf:"varint,14,opt,name=ReservationPolicy,proto3,enum=v1_snapshot.Room_ReservationPolicyType" json:"ReservationPolicy,omitempty"`
+	ReplayBufferSize       int32                      `protobuf:"varint,15,opt,name=ReplayBufferSize,proto3" json:"ReplayBufferSize,omitempty"`
+	MaxSpectators          int32                      `protobuf:"varint,17,opt,name=MaxSpectators,proto3" json:"MaxSpectators,omitempty"`
+	Properties             []*room.Property           `protobuf:"bytes,18,rep,name=Properties,proto3" json:"Properties,omitempty"`
+	PropertiesVersion      uint64                     `protobuf:"varint,19,opt,name=PropertiesVersion,proto3" json:"PropertiesVersion,omitempty"`
+	Public                 bool                       `protobuf:"varint,20,opt,name=Public,proto3" json:"Public,omitempty"`
+	Name                   string                     `protobuf:"bytes,21,opt,name=Name,proto3" json:"Name,omitempty"`
+	Tags                   []string                   `protobuf:"bytes,22,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	RelayRateLimit         int32                      `protobuf:"varint,23,opt,name=RelayRateLimit,proto3" json:"RelayRateLimit,omitempty"`
+	Template               string                     `protobuf:"bytes,24,opt,name=Template,proto3" json:"Template,omitempty"`
+	InviteCode             string                     `protobuf:"bytes,25,opt,name=InviteCode,proto3" json:"InviteCode,omitempty"`
+	PasswordHash           string                     `protobuf:"bytes,26,opt,name=PasswordHash,proto3" json:"PasswordHash,omitempty"`
+	Permissions            []*RolePermissions         `protobuf:"bytes,27,rep,name=Permissions,proto3" json:"Permissions,omitempty"`
+	// NextClientID is the ID given to the next client to join, zero in snapshots taken before it was included
+	NextClientID int32 `protobuf:"varint,28,opt,name=NextClientID,proto3" json:"NextClientID,omitempty"`
+}
+
+func (x *Room) Reset() {
+	*x = Room{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_snapshot_snapshot_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Room) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Room) ProtoMessage() {}
+
+func (x *Room) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_snapshot_snapshot_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Room.ProtoReflect.Descriptor instead.
+func (*Room) Descriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Room) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Room) GetSecret() int32 {
+	if x != nil {
+		return x.Secret
+	}
+	return 0
+}
+
+func (x *Room) GetMaxClients() int32 {
+	if x != nil {
+		return x.MaxClients
+	}
+	return 0
+}
+
+func (x *Room) GetHostID() int32 {
+	if x != nil && x.HostID != nil {
+		return *x.HostID
+	}
+	return 0
+}
+
+func (x *Room) GetStatus() Room_StatusType {
+	if x != nil {
+		return x.Status
+	}
+	return Room_RUNNING
+}
+
+func (x *Room) GetClients() []*Client {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *Room) GetMaxLifetime() int64 {
+	if x != nil {
+		return x.MaxLifetime
+	}
+	return 0
+}
+
+func (x *Room) GetIdleTimeout() int64 {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return 0
+}
+
+func (x *Room) GetNeverJoinedTimeout() int64 {
+	if x != nil {
+		return x.NeverJoinedTimeout
+	}
+	return 0
+}
+
+func (x *Room) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Room) GetJoined() bool {
+	if x != nil {
+		return x.Joined
+	}
+	return false
+}
+
+func (x *Room) GetReconnectGracePeriod() int64 {
+	if x != nil {
+		return x.ReconnectGracePeriod
+	}
+	return 0
+}
+
+func (x *Room) GetMaxDisconnectedClients() int32 {
+	if x != nil {
+		return x.MaxDisconnectedClients
+	}
+	return 0
+}
+
+func (x *Room) GetReservationPolicy() Room_ReservationPolicyType {
+	if x != nil {
+		return x.ReservationPolicy
+	}
+	return Room_FIRST_COME
+}
+
+func (x *Room) GetReplayBufferSize() int32 {
+	if x != nil {
+		return x.ReplayBufferSize
+	}
+	return 0
+}
+
+func (x *Room) GetMaxSpectators() int32 {
+	if x != nil {
+		return x.MaxSpectators
+	}
+	return 0
+}
+
+func (x *Room) GetProperties() []*room.Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *Room) GetPropertiesVersion() uint64 {
+	if x != nil {
+		return x.PropertiesVersion
+	}
+	return 0
+}
+
+func (x *Room) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *Room) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Room) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Room) GetRelayRateLimit() int32 {
+	if x != nil {
+		return x.RelayRateLimit
+	}
+	return 0
+}
+
+func (x *Room) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *Room) GetInviteCode() string {
+	if x != nil {
+		return x.InviteCode
+	}
+	return ""
+}
+
+func (x *Room) GetPasswordHash() string {
+	if x != nil {
+		return x.PasswordHash
+	}
+	return ""
+}
+
+func (x *Room) GetPermissions() []*RolePermissions {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *Room) GetNextClientID() int32 {
+	if x != nil {
+		return x.NextClientID
+	}
+	return 0
+}
+
+type Client struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID        int32                           `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Secret    int32                           `protobuf:"varint,2,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	Connected bool                            `protobuf:"varint,3,opt,name=Connected,proto3" json:"Connected,omitempty"`
+	Role      client.SanitisedClient_RoleType `protobuf:"varint,4,opt,name=Role,proto3,enum=v1_client.SanitisedClient_RoleType" json:"Role,omitempty"`
+	Metadata  map[string]string               `protobuf:"bytes,5,rep,name=Metadata,proto3" json:"Metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Client) Reset() {
+	*x = Client{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_snapshot_snapshot_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Client) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client) ProtoMessage() {}
+
+func (x *Client) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_snapshot_snapshot_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client.ProtoReflect.Descriptor instead.
+func (*Client) Descriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Client) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Client) GetSecret() int32 {
+	if x != nil {
+		return x.Secret
+	}
+	return 0
+}
+
+func (x *Client) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *Client) GetRole() client.SanitisedClient_RoleType {
+	if x != nil {
+		return x.Role
+	}
+	return client.SanitisedClient_PLAYER
+}
+
+func (x *Client) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type RolePermissions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Role        client.SanitisedClient_RoleType  `protobuf:"varint,1,opt,name=Role,proto3,enum=v1_client.SanitisedClient_RoleType" json:"Role,omitempty"`
+	Permissions []RolePermissions_PermissionType `protobuf:"varint,2,rep,packed,name=Permissions,proto3,enum=v1_snapshot.RolePermissions_PermissionType" json:"Permissions,omitempty"`
+}
+
+func (x *RolePermissions) Reset() {
+	*x = RolePermissions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_snapshot_snapshot_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RolePermissions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RolePermissions) ProtoMessage() {}
+
+func (x *RolePermissions) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_snapshot_snapshot_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RolePermissions.ProtoReflect.Descriptor instead.
+func (*RolePermissions) Descriptor() ([]byte, []int) {
+	return file_v1_snapshot_snapshot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RolePermissions) GetRole() client.SanitisedClient_RoleType {
+	if x != nil {
+		return x.Role
+	}
+	return client.SanitisedClient_PLAYER
+}
+
+func (x *RolePermissions) GetPermissions() []RolePermissions_PermissionType {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+var File_v1_snapshot_snapshot_proto protoreflect.FileDescriptor
+
+var file_v1_snapshot_snapshot_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x76, 0x31, 0x2f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2f, 0x73, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x76, 0x31,
+	0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x1a, 0x16, 0x76, 0x31, 0x2f, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x2f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x12, 0x76, 0x31, 0x2f, 0x72, 0x6f, 0x6f, 0x6d, 0x2f, 0x72, 0x6f, 0x6f, 0x6d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4d, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x05, 0x52,
+	0x6f, 0x6f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x31, 0x5f,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x52, 0x05, 0x52,
+	0x6f, 0x6f, 0x6d, 0x73, 0x22, 0x8b, 0x09, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x6d, 0x12, 0x0e, 0x0a,
+	0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a,
+	0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x4d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x4d, 0x61, 0x78, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x06, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x44, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x44, 0x88,
+	0x01, 0x01, 0x12, 0x34, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x76, 0x31, 0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x31, 0x5f, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x07,
+	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x4d, 0x61, 0x78, 0x4c, 0x69,
+	0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x4d, 0x61,
+	0x78, 0x4c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x49, 0x64, 0x6c,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x49, 0x64, 0x6c, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x4e,
+	0x65, 0x76, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x4e, 0x65, 0x76, 0x65, 0x72, 0x4a, 0x6f,
+	0x69, 0x6e, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x4a, 0x6f, 0x69,
+	0x6e, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x4a, 0x6f, 0x69, 0x6e, 0x65,
+	0x64, 0x12, 0x32, 0x0a, 0x14, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x47, 0x72,
+	0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x14, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x36, 0x0a, 0x16, 0x4d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x16, 0x4d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x55, 0x0a,
+	0x11, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x76, 0x31, 0x5f, 0x73, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x52, 0x6f, 0x6f, 0x6d, 0x2e, 0x52, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x11, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x42, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x4d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x63, 0x74, 0x61, 0x74, 0x6f, 0x72,
+	0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x4d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x63,
+	0x74, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72,
+	0x74, 0x69, 0x65, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x31, 0x5f,
+	0x72, 0x6f, 0x6f, 0x6d, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a, 0x50,
+	0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x50, 0x72, 0x6f,
+	0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x13,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12,
+	0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x65, 0x6c, 0x61, 0x79,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49,
+	0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x50,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x18, 0x1a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x3e, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x1b,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x31, 0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x22, 0x0a, 0x0c, 0x4e, 0x65, 0x78, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18,
+	0x1c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x4e, 0x65, 0x78, 0x74, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x49, 0x44, 0x22, 0x26, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x43, 0x4c, 0x4f, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x22, 0x34, 0x0a, 0x15, 0x52,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x49, 0x52, 0x53, 0x54, 0x5f, 0x43, 0x4f,
+	0x4d, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x10,
+	0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x44, 0x4a, 0x04, 0x08, 0x10,
+	0x10, 0x11, 0x22, 0x83, 0x02, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a,
+	0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x12, 0x37, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x23, 0x2e, 0x76, 0x31, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x61,
+	0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x6f,
+	0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x3d, 0x0a, 0x08,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x76, 0x31, 0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8e, 0x02, 0x0a, 0x0f, 0x52, 0x6f, 0x6c,
+	0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x37, 0x0a, 0x04,
+	0x52, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x76, 0x31, 0x5f,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x61, 0x6e, 0x69, 0x74, 0x69, 0x73, 0x65, 0x64,
+	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x4d, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x2b, 0x2e, 0x76, 0x31, 0x5f,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x50, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x73, 0x0a, 0x0e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43,
+	0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x10,
+	0x01, 0x12, 0x08, 0x0a, 0x04, 0x4b, 0x49, 0x43, 0x4b, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x47,
+	0x52, 0x41, 0x4e, 0x54, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a, 0x47,
+	0x52, 0x41, 0x4e, 0x54, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x4c,
+	0x49, 0x53, 0x54, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x45, 0x54, 0x5f, 0x50, 0x52, 0x4f,
+	0x50, 0x45, 0x52, 0x54, 0x49, 0x45, 0x53, 0x10, 0x06, 0x42, 0x3d, 0x5a, 0x3b, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x6c, 0x61,
+	0x62, 0x73, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2d,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x73, 0x2f, 0x76, 0x31, 0x2f,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_v1_snapshot_snapshot_proto_rawDescOnce sync.Once
+	file_v1_snapshot_snapshot_proto_rawDescData = file_v1_snapshot_snapshot_proto_rawDesc
+)
+
+func file_v1_snapshot_snapshot_proto_rawDescGZIP() []byte {
+	file_v1_snapshot_snapshot_proto_rawDescOnce.Do(func() {
+		file_v1_snapshot_snapshot_proto_rawDescData = protoimpl.X.CompressGZIP(file_v1_snapshot_snapshot_proto_rawDescData)
+	})
+	return file_v1_snapshot_snapshot_proto_rawDescData
+}
+
+var file_v1_snapshot_snapshot_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_v1_snapshot_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_v1_snapshot_snapshot_proto_goTypes = []interface{}{
+	(Room_StatusType)(0),                 // 0: v1_snapshot.Room.StatusType
+	(Room_ReservationPolicyType)(0),      // 1: v1_snapshot.Room.ReservationPolicyType
+	(RolePermissions_PermissionType)(0),  // 2: v1_snapshot.RolePermissions.PermissionType
+	(*Snapshot)(nil),                     // 3: v1_snapshot.Snapshot
+	(*Room)(nil),                         // 4: v1_snapshot.Room
+	(*Client)(nil),                       // 5: v1_snapshot.Client
+	(*RolePermissions)(nil),              // 6: v1_snapshot.RolePermissions
+	nil,                                  // 7: v1_snapshot.Client.MetadataEntry
+	(*room.Property)(nil),                // 8: v1_room.Property
+	(client.SanitisedClient_RoleType)(0), // 9: v1_client.SanitisedClient.RoleType
+}
+var file_v1_snapshot_snapshot_proto_depIdxs = []int32{
+	4,  // 0: v1_snapshot.Snapshot.Rooms:type_name -> v1_snapshot.Room
+	0,  // 1: v1_snapshot.Room.Status:type_name -> v1_snapshot.Room.StatusType
+	5,  // 2: v1_snapshot.Room.Clients:type_name -> v1_snapshot.Client
+	1,  // 3: v1_snapshot.Room.ReservationPolicy:type_name -> v1_snapshot.Room.ReservationPolicyType
+	8,  // 4: v1_snapshot.Room.Properties:type_name -> v1_room.Property
+	6,  // 5: v1_snapshot.Room.Permissions:type_name -> v1_snapshot.RolePermissions
+	9,  // 6: v1_snapshot.Client.Role:type_name -> v1_client.SanitisedClient.RoleType
+	7,  // 7: v1_snapshot.Client.Metadata:type_name -> v1_snapshot.Client.MetadataEntry
+	9,  // 8: v1_snapshot.RolePermissions.Role:type_name -> v1_client.SanitisedClient.RoleType
+	2,  // 9: v1_snapshot.RolePermissions.Permissions:type_name -> v1_snapshot.RolePermissions.PermissionType
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_v1_snapshot_snapshot_proto_init() }
+func file_v1_snapshot_snapshot_proto_init() {
+	if File_v1_snapshot_snapshot_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_v1_snapshot_snapshot_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Snapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_snapshot_snapshot_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Room); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_snapshot_snapshot_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Client); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_snapshot_snapshot_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RolePermissions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_v1_snapshot_snapshot_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_v1_snapshot_snapshot_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_v1_snapshot_snapshot_proto_goTypes,
+		DependencyIndexes: file_v1_snapshot_snapshot_proto_depIdxs,
+		EnumInfos:         file_v1_snapshot_snapshot_proto_enumTypes,
+		MessageInfos:      file_v1_snapshot_snapshot_proto_msgTypes,
+	}.Build()
+	File_v1_snapshot_snapshot_proto = out.File
+	file_v1_snapshot_snapshot_proto_rawDesc = nil
+	file_v1_snapshot_snapshot_proto_goTypes = nil
+	file_v1_snapshot_snapshot_proto_depIdxs = nil
+}
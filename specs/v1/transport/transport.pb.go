@@ -54,6 +54,31 @@ const (
 	Payload_RESPONSE_ERROR               Payload_FlagType = 13
 	Payload_RESPONSE_CLIENT_CONNECT      Payload_FlagType = 14
 	Payload_RESPONSE_CLIENT_DISCONNECT   Payload_FlagType = 15
+	Payload_REQUEST_HANDSHAKE            Payload_FlagType = 16
+	Payload_RESPONSE_HANDSHAKE           Payload_FlagType = 17
+	Payload_RESPONSE_REDIRECT            Payload_FlagType = 18
+	Payload_RESPONSE_CLIENT_LEAVE        Payload_FlagType = 19
+	Payload_RESPONSE_REPLAY_GAP          Payload_FlagType = 20
+	Payload_REQUEST_ACK                  Payload_FlagType = 21
+	Payload_RESPONSE_ACK                 Payload_FlagType = 22
+	Payload_RESPONSE_RELAY_FAILURES      Payload_FlagType = 23
+	Payload_RESPONSE_RELAYED_MESSAGE     Payload_FlagType = 24
+	Payload_REQUEST_GRANT_ROLE           Payload_FlagType = 25
+	Payload_RESPONSE_ROLE_CHANGE         Payload_FlagType = 26
+	Payload_REQUEST_UPDATE_METADATA      Payload_FlagType = 27
+	Payload_RESPONSE_METADATA_CHANGE     Payload_FlagType = 28
+	Payload_REQUEST_GET_PROPERTIES       Payload_FlagType = 29
+	Payload_RESPONSE_PROPERTIES          Payload_FlagType = 30
+	Payload_REQUEST_SET_PROPERTY         Payload_FlagType = 31
+	Payload_REQUEST_DELETE_PROPERTY      Payload_FlagType = 32
+	Payload_RESPONSE_PROPERTY_CHANGE     Payload_FlagType = 33
+	Payload_REQUEST_BROWSE_ROOMS         Payload_FlagType = 34
+	Payload_RESPONSE_PUBLIC_ROOMS        Payload_FlagType = 35
+	Payload_REQUEST_MATCHMAKE            Payload_FlagType = 36
+	Payload_REQUEST_CANCEL_MATCHMAKE     Payload_FlagType = 37
+	Payload_RESPONSE_MATCHMAKE_STATUS    Payload_FlagType = 38
+	Payload_REQUEST_QUICK_JOIN           Payload_FlagType = 39
+	Payload_RESPONSE_QUICK_JOIN          Payload_FlagType = 40
 )
 
 // Enum value maps for Payload_FlagType.
@@ -75,6 +100,31 @@ var (
 		13: "RESPONSE_ERROR",
 		14: "RESPONSE_CLIENT_CONNECT",
 		15: "RESPONSE_CLIENT_DISCONNECT",
+		16: "REQUEST_HANDSHAKE",
+		17: "RESPONSE_HANDSHAKE",
+		18: "RESPONSE_REDIRECT",
+		19: "RESPONSE_CLIENT_LEAVE",
+		20: "RESPONSE_REPLAY_GAP",
+		21: "REQUEST_ACK",
+		22: "RESPONSE_ACK",
+		23: "RESPONSE_RELAY_FAILURES",
+		24: "RESPONSE_RELAYED_MESSAGE",
+		25: "REQUEST_GRANT_ROLE",
+		26: "RESPONSE_ROLE_CHANGE",
+		27: "REQUEST_UPDATE_METADATA",
+		28: "RESPONSE_METADATA_CHANGE",
+		29: "REQUEST_GET_PROPERTIES",
+		30: "RESPONSE_PROPERTIES",
+		31: "REQUEST_SET_PROPERTY",
+		32: "REQUEST_DELETE_PROPERTY",
+		33: "RESPONSE_PROPERTY_CHANGE",
+		34: "REQUEST_BROWSE_ROOMS",
+		35: "RESPONSE_PUBLIC_ROOMS",
+		36: "REQUEST_MATCHMAKE",
+		37: "REQUEST_CANCEL_MATCHMAKE",
+		38: "RESPONSE_MATCHMAKE_STATUS",
+		39: "REQUEST_QUICK_JOIN",
+		40: "RESPONSE_QUICK_JOIN",
 	}
 	Payload_FlagType_value = map[string]int32{
 		"REQUEST_RELAY_MESSAGE":        0,
@@ -93,6 +143,31 @@ var (
 		"RESPONSE_ERROR":               13,
 		"RESPONSE_CLIENT_CONNECT":      14,
 		"RESPONSE_CLIENT_DISCONNECT":   15,
+		"REQUEST_HANDSHAKE":            16,
+		"RESPONSE_HANDSHAKE":           17,
+		"RESPONSE_REDIRECT":            18,
+		"RESPONSE_CLIENT_LEAVE":        19,
+		"RESPONSE_REPLAY_GAP":          20,
+		"REQUEST_ACK":                  21,
+		"RESPONSE_ACK":                 22,
+		"RESPONSE_RELAY_FAILURES":      23,
+		"RESPONSE_RELAYED_MESSAGE":     24,
+		"REQUEST_GRANT_ROLE":           25,
+		"RESPONSE_ROLE_CHANGE":         26,
+		"REQUEST_UPDATE_METADATA":      27,
+		"RESPONSE_METADATA_CHANGE":     28,
+		"REQUEST_GET_PROPERTIES":       29,
+		"RESPONSE_PROPERTIES":          30,
+		"REQUEST_SET_PROPERTY":         31,
+		"REQUEST_DELETE_PROPERTY":      32,
+		"RESPONSE_PROPERTY_CHANGE":     33,
+		"REQUEST_BROWSE_ROOMS":         34,
+		"RESPONSE_PUBLIC_ROOMS":        35,
+		"REQUEST_MATCHMAKE":            36,
+		"REQUEST_CANCEL_MATCHMAKE":     37,
+		"RESPONSE_MATCHMAKE_STATUS":    38,
+		"REQUEST_QUICK_JOIN":           39,
+		"RESPONSE_QUICK_JOIN":          40,
 	}
 )
 
@@ -123,13 +198,116 @@ func (Payload_FlagType) EnumDescriptor() ([]byte, []int) {
 	return file_v1_transport_transport_proto_rawDescGZIP(), []int{0, 0}
 }
 
+type Error_ReasonType int32
+
+const (
+	Error_UNKNOWN             Error_ReasonType = 0
+	Error_INTERNAL            Error_ReasonType = 1
+	Error_INVALID_REQUEST     Error_ReasonType = 2
+	Error_UNSUPPORTED_VERSION Error_ReasonType = 3
+	Error_ALREADY_IN_ROOM     Error_ReasonType = 4
+	Error_NOT_IN_ROOM         Error_ReasonType = 5
+	Error_ROOM_NOT_FOUND      Error_ReasonType = 6
+	Error_ROOM_FULL           Error_ReasonType = 7
+	Error_INVALID_SECRET      Error_ReasonType = 8
+	Error_CLIENT_NOT_FOUND    Error_ReasonType = 9
+	Error_TARGET_NOT_FOUND    Error_ReasonType = 10
+	Error_INVALID_TARGET      Error_ReasonType = 11
+	// NOT_HOST is no longer sent, requests a client's role does not permit are refused with NOT_PERMITTED, as
+	// actions such as kicking and granting host can be permitted for roles other than the host
+	//
+	// Deprecated: Do not use.
+	Error_NOT_HOST           Error_ReasonType = 12
+	Error_NOT_PERMITTED      Error_ReasonType = 13
+	Error_VERSION_CONFLICT   Error_ReasonType = 14
+	Error_PROPERTY_NOT_FOUND Error_ReasonType = 15
+	Error_SERVER_FULL        Error_ReasonType = 16
+	Error_RATE_LIMITED       Error_ReasonType = 17
+	Error_WRONG_PASSWORD     Error_ReasonType = 18
+)
+
+// Enum value maps for Error_ReasonType.
+var (
+	Error_ReasonType_name = map[int32]string{
+		0:  "UNKNOWN",
+		1:  "INTERNAL",
+		2:  "INVALID_REQUEST",
+		3:  "UNSUPPORTED_VERSION",
+		4:  "ALREADY_IN_ROOM",
+		5:  "NOT_IN_ROOM",
+		6:  "ROOM_NOT_FOUND",
+		7:  "ROOM_FULL",
+		8:  "INVALID_SECRET",
+		9:  "CLIENT_NOT_FOUND",
+		10: "TARGET_NOT_FOUND",
+		11: "INVALID_TARGET",
+		12: "NOT_HOST",
+		13: "NOT_PERMITTED",
+		14: "VERSION_CONFLICT",
+		15: "PROPERTY_NOT_FOUND",
+		16: "SERVER_FULL",
+		17: "RATE_LIMITED",
+		18: "WRONG_PASSWORD",
+	}
+	Error_ReasonType_value = map[string]int32{
+		"UNKNOWN":             0,
+		"INTERNAL":            1,
+		"INVALID_REQUEST":     2,
+		"UNSUPPORTED_VERSION": 3,
+		"ALREADY_IN_ROOM":     4,
+		"NOT_IN_ROOM":         5,
+		"ROOM_NOT_FOUND":      6,
+		"ROOM_FULL":           7,
+		"INVALID_SECRET":      8,
+		"CLIENT_NOT_FOUND":    9,
+		"TARGET_NOT_FOUND":    10,
+		"INVALID_TARGET":      11,
+		"NOT_HOST":            12,
+		"NOT_PERMITTED":       13,
+		"VERSION_CONFLICT":    14,
+		"PROPERTY_NOT_FOUND":  15,
+		"SERVER_FULL":         16,
+		"RATE_LIMITED":        17,
+		"WRONG_PASSWORD":      18,
+	}
+)
+
+func (x Error_ReasonType) Enum() *Error_ReasonType {
+	p := new(Error_ReasonType)
+	*p = x
+	return p
+}
+
+func (x Error_ReasonType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Error_ReasonType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_transport_transport_proto_enumTypes[1].Descriptor()
+}
+
+func (Error_ReasonType) Type() protoreflect.EnumType {
+	return &file_v1_transport_transport_proto_enumTypes[1]
+}
+
+func (x Error_ReasonType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Error_ReasonType.Descriptor instead.
+func (Error_ReasonType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_transport_transport_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type Payload struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Flag Payload_FlagType `protobuf:"varint,1,opt,name=Flag,proto3,enum=v1_transport.Payload_FlagType" json:"Flag,omitempty"`
-	Data []byte           `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	Flag      Payload_FlagType `protobuf:"varint,1,opt,name=Flag,proto3,enum=v1_transport.Payload_FlagType" json:"Flag,omitempty"`
+	Data      []byte           `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	RequestID *uint32          `protobuf:"varint,3,opt,name=RequestID,proto3,oneof" json:"RequestID,omitempty"`
+	Sequence  *uint64          `protobuf:"varint,4,opt,name=Sequence,proto3,oneof" json:"Sequence,omitempty"`
 }
 
 func (x *Payload) Reset() {
@@ -178,19 +356,144 @@ func (x *Payload) GetData() []byte {
 	return nil
 }
 
+func (x *Payload) GetRequestID() uint32 {
+	if x != nil && x.RequestID != nil {
+		return *x.RequestID
+	}
+	return 0
+}
+
+func (x *Payload) GetSequence() uint64 {
+	if x != nil && x.Sequence != nil {
+		return *x.Sequence
+	}
+	return 0
+}
+
+type HandshakeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProtocolVersion int32    `protobuf:"varint,1,opt,name=ProtocolVersion,proto3" json:"ProtocolVersion,omitempty"`
+	Capabilities    []string `protobuf:"bytes,2,rep,name=Capabilities,proto3" json:"Capabilities,omitempty"`
+}
+
+func (x *HandshakeRequest) Reset() {
+	*x = HandshakeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_transport_transport_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandshakeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeRequest) ProtoMessage() {}
+
+func (x *HandshakeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_transport_transport_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeRequest.ProtoReflect.Descriptor instead.
+func (*HandshakeRequest) Descriptor() ([]byte, []int) {
+	return file_v1_transport_transport_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HandshakeRequest) GetProtocolVersion() int32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *HandshakeRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+type HandshakeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProtocolVersion int32    `protobuf:"varint,1,opt,name=ProtocolVersion,proto3" json:"ProtocolVersion,omitempty"`
+	Capabilities    []string `protobuf:"bytes,2,rep,name=Capabilities,proto3" json:"Capabilities,omitempty"`
+}
+
+func (x *HandshakeResponse) Reset() {
+	*x = HandshakeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_transport_transport_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandshakeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeResponse) ProtoMessage() {}
+
+func (x *HandshakeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_transport_transport_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeResponse.ProtoReflect.Descriptor instead.
+func (*HandshakeResponse) Descriptor() ([]byte, []int) {
+	return file_v1_transport_transport_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HandshakeResponse) GetProtocolVersion() int32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *HandshakeResponse) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
 type Error struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Code    int32  `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"`
-	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Code    int32            `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"`
+	Message string           `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Reason  Error_ReasonType `protobuf:"varint,3,opt,name=Reason,proto3,enum=v1_transport.Error_ReasonType" json:"Reason,omitempty"`
 }
 
 func (x *Error) Reset() {
 	*x = Error{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_v1_transport_transport_proto_msgTypes[1]
+		mi := &file_v1_transport_transport_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -203,7 +506,7 @@ func (x *Error) String() string {
 func (*Error) ProtoMessage() {}
 
 func (x *Error) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_transport_transport_proto_msgTypes[1]
+	mi := &file_v1_transport_transport_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -216,7 +519,7 @@ func (x *Error) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Error.ProtoReflect.Descriptor instead.
 func (*Error) Descriptor() ([]byte, []int) {
-	return file_v1_transport_transport_proto_rawDescGZIP(), []int{1}
+	return file_v1_transport_transport_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Error) GetCode() int32 {
@@ -233,51 +536,144 @@ func (x *Error) GetMessage() string {
 	return ""
 }
 
+func (x *Error) GetReason() Error_ReasonType {
+	if x != nil {
+		return x.Reason
+	}
+	return Error_UNKNOWN
+}
+
 var File_v1_transport_transport_proto protoreflect.FileDescriptor
 
 var file_v1_transport_transport_proto_rawDesc = []byte{
 	0x0a, 0x1c, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x74,
 	0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
-	0x76, 0x31, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0xe7, 0x03, 0x0a,
+	0x76, 0x31, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0xcf, 0x09, 0x0a,
 	0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x32, 0x0a, 0x04, 0x46, 0x6c, 0x61, 0x67,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x76, 0x31, 0x5f, 0x74, 0x72, 0x61, 0x6e,
 	0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2e, 0x46, 0x6c,
 	0x61, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x12, 0x0a, 0x04,
 	0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61,
-	0x22, 0x93, 0x03, 0x0a, 0x08, 0x46, 0x6c, 0x61, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a,
-	0x15, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x52, 0x45, 0x4c, 0x41, 0x59, 0x5f, 0x4d,
-	0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x51, 0x55,
-	0x45, 0x53, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x01, 0x12, 0x15, 0x0a,
-	0x11, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x4e, 0x4e, 0x45,
-	0x43, 0x54, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f,
-	0x4c, 0x49, 0x53, 0x54, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53,
-	0x54, 0x5f, 0x4b, 0x49, 0x43, 0x4b, 0x10, 0x04, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x51, 0x55,
-	0x45, 0x53, 0x54, 0x5f, 0x47, 0x52, 0x41, 0x4e, 0x54, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x05,
-	0x12, 0x1a, 0x0a, 0x16, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x52, 0x45, 0x4c,
-	0x41, 0x59, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10,
-	0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54,
-	0x10, 0x07, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x41,
-	0x53, 0x53, 0x49, 0x47, 0x4e, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x08, 0x12, 0x1f, 0x0a, 0x1b,
-	0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x42, 0x45, 0x47, 0x49, 0x4e, 0x5f, 0x48,
-	0x4f, 0x53, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52, 0x41, 0x54, 0x45, 0x10, 0x09, 0x12, 0x20, 0x0a,
-	0x1c, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48,
-	0x5f, 0x48, 0x4f, 0x53, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52, 0x41, 0x54, 0x45, 0x10, 0x0a, 0x12,
-	0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x4c, 0x49, 0x53, 0x54,
-	0x10, 0x0b, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x4b,
-	0x49, 0x43, 0x4b, 0x10, 0x0c, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53,
-	0x45, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x0d, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x53,
-	0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x43, 0x4f, 0x4e,
-	0x4e, 0x45, 0x43, 0x54, 0x10, 0x0e, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e,
-	0x53, 0x45, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e,
-	0x4e, 0x45, 0x43, 0x54, 0x10, 0x0f, 0x22, 0x35, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12,
-	0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x43,
-	0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x3e, 0x5a,
-	0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6d, 0x6a,
-	0x61, 0x72, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x2d, 0x72, 0x65,
-	0x6c, 0x61, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x73, 0x70, 0x65, 0x63, 0x73,
-	0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x21, 0x0a, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44,
+	0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x88, 0x01, 0x01, 0x22, 0x9c, 0x08, 0x0a, 0x08, 0x46, 0x6c, 0x61, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x52, 0x45, 0x4c,
+	0x41, 0x59, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f,
+	0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10,
+	0x01, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x52, 0x45, 0x43,
+	0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x51, 0x55,
+	0x45, 0x53, 0x54, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45,
+	0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x4b, 0x49, 0x43, 0x4b, 0x10, 0x04, 0x12, 0x16, 0x0a, 0x12,
+	0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x47, 0x52, 0x41, 0x4e, 0x54, 0x5f, 0x48, 0x4f,
+	0x53, 0x54, 0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45,
+	0x5f, 0x52, 0x45, 0x4c, 0x41, 0x59, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x06,
+	0x12, 0x14, 0x0a, 0x10, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x4f, 0x4e,
+	0x4e, 0x45, 0x43, 0x54, 0x10, 0x07, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e,
+	0x53, 0x45, 0x5f, 0x41, 0x53, 0x53, 0x49, 0x47, 0x4e, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10, 0x08,
+	0x12, 0x1f, 0x0a, 0x1b, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x42, 0x45, 0x47,
+	0x49, 0x4e, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52, 0x41, 0x54, 0x45, 0x10,
+	0x09, 0x12, 0x20, 0x0a, 0x1c, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x46, 0x49,
+	0x4e, 0x49, 0x53, 0x48, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52, 0x41, 0x54,
+	0x45, 0x10, 0x0a, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f,
+	0x4c, 0x49, 0x53, 0x54, 0x10, 0x0b, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e,
+	0x53, 0x45, 0x5f, 0x4b, 0x49, 0x43, 0x4b, 0x10, 0x0c, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x53,
+	0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x0d, 0x12, 0x1b, 0x0a,
+	0x17, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54,
+	0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x0e, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45,
+	0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x44, 0x49,
+	0x53, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x0f, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45,
+	0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x48, 0x41, 0x4e, 0x44, 0x53, 0x48, 0x41, 0x4b, 0x45, 0x10,
+	0x10, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x48, 0x41,
+	0x4e, 0x44, 0x53, 0x48, 0x41, 0x4b, 0x45, 0x10, 0x11, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x53,
+	0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x52, 0x45, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x10, 0x12,
+	0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x4c, 0x49,
+	0x45, 0x4e, 0x54, 0x5f, 0x4c, 0x45, 0x41, 0x56, 0x45, 0x10, 0x13, 0x12, 0x17, 0x0a, 0x13, 0x52,
+	0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x47,
+	0x41, 0x50, 0x10, 0x14, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f,
+	0x41, 0x43, 0x4b, 0x10, 0x15, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53,
+	0x45, 0x5f, 0x41, 0x43, 0x4b, 0x10, 0x16, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x53, 0x50, 0x4f,
+	0x4e, 0x53, 0x45, 0x5f, 0x52, 0x45, 0x4c, 0x41, 0x59, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52,
+	0x45, 0x53, 0x10, 0x17, 0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45,
+	0x5f, 0x52, 0x45, 0x4c, 0x41, 0x59, 0x45, 0x44, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45,
+	0x10, 0x18, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x47, 0x52,
+	0x41, 0x4e, 0x54, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x10, 0x19, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45,
+	0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e,
+	0x47, 0x45, 0x10, 0x1a, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f,
+	0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x4d, 0x45, 0x54, 0x41, 0x44, 0x41, 0x54, 0x41, 0x10,
+	0x1b, 0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x4d, 0x45,
+	0x54, 0x41, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x1c, 0x12,
+	0x1a, 0x0a, 0x16, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x47, 0x45, 0x54, 0x5f, 0x50,
+	0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x49, 0x45, 0x53, 0x10, 0x1d, 0x12, 0x17, 0x0a, 0x13, 0x52,
+	0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x49,
+	0x45, 0x53, 0x10, 0x1e, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f,
+	0x53, 0x45, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x59, 0x10, 0x1f, 0x12, 0x1b,
+	0x0a, 0x17, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45,
+	0x5f, 0x50, 0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x59, 0x10, 0x20, 0x12, 0x1c, 0x0a, 0x18, 0x52,
+	0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x59,
+	0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x21, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x51,
+	0x55, 0x45, 0x53, 0x54, 0x5f, 0x42, 0x52, 0x4f, 0x57, 0x53, 0x45, 0x5f, 0x52, 0x4f, 0x4f, 0x4d,
+	0x53, 0x10, 0x22, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f,
+	0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x5f, 0x52, 0x4f, 0x4f, 0x4d, 0x53, 0x10, 0x23, 0x12, 0x15,
+	0x0a, 0x11, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x4d,
+	0x41, 0x4b, 0x45, 0x10, 0x24, 0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54,
+	0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x5f, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x4d, 0x41, 0x4b,
+	0x45, 0x10, 0x25, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f,
+	0x4d, 0x41, 0x54, 0x43, 0x48, 0x4d, 0x41, 0x4b, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x10, 0x26, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x51, 0x55,
+	0x49, 0x43, 0x4b, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x10, 0x27, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x45,
+	0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x51, 0x55, 0x49, 0x43, 0x4b, 0x5f, 0x4a, 0x4f, 0x49,
+	0x4e, 0x10, 0x28, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x44, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x60,
+	0x0a, 0x10, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0c, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x22, 0x61, 0x0a, 0x11, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x22, 0x0a, 0x0c, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x22, 0xec, 0x03, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x76, 0x31,
+	0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x22, 0xfc, 0x02, 0x0a, 0x0a, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
+	0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x13, 0x0a,
+	0x0f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54,
+	0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x55, 0x4e, 0x53, 0x55, 0x50, 0x50, 0x4f, 0x52, 0x54, 0x45,
+	0x44, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x41,
+	0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x49, 0x4e, 0x5f, 0x52, 0x4f, 0x4f, 0x4d, 0x10, 0x04,
+	0x12, 0x0f, 0x0a, 0x0b, 0x4e, 0x4f, 0x54, 0x5f, 0x49, 0x4e, 0x5f, 0x52, 0x4f, 0x4f, 0x4d, 0x10,
+	0x05, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x4f, 0x4f, 0x4d, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f,
+	0x55, 0x4e, 0x44, 0x10, 0x06, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x4f, 0x4f, 0x4d, 0x5f, 0x46, 0x55,
+	0x4c, 0x4c, 0x10, 0x07, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f,
+	0x53, 0x45, 0x43, 0x52, 0x45, 0x54, 0x10, 0x08, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4c, 0x49, 0x45,
+	0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x09, 0x12, 0x14,
+	0x0a, 0x10, 0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55,
+	0x4e, 0x44, 0x10, 0x0a, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f,
+	0x54, 0x41, 0x52, 0x47, 0x45, 0x54, 0x10, 0x0b, 0x12, 0x10, 0x0a, 0x08, 0x4e, 0x4f, 0x54, 0x5f,
+	0x48, 0x4f, 0x53, 0x54, 0x10, 0x0c, 0x1a, 0x02, 0x08, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x4f,
+	0x54, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x44, 0x10, 0x0d, 0x12, 0x14, 0x0a,
+	0x10, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x4c, 0x49, 0x43,
+	0x54, 0x10, 0x0e, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x52, 0x4f, 0x50, 0x45, 0x52, 0x54, 0x59, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x0f, 0x12, 0x0f, 0x0a, 0x0b, 0x53,
+	0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x46, 0x55, 0x4c, 0x4c, 0x10, 0x10, 0x12, 0x10, 0x0a, 0x0c,
+	0x52, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x45, 0x44, 0x10, 0x11, 0x12, 0x12,
+	0x0a, 0x0e, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x57, 0x4f, 0x52, 0x44,
+	0x10, 0x12, 0x42, 0x3e, 0x5a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x6a, 0x61, 0x6d, 0x6a, 0x61, 0x72, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x6a, 0x61, 0x6d, 0x6a,
+	0x61, 0x72, 0x2d, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f,
+	0x73, 0x70, 0x65, 0x63, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
+	0x72, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -292,20 +688,24 @@ func file_v1_transport_transport_proto_rawDescGZIP() []byte {
 	return file_v1_transport_transport_proto_rawDescData
 }
 
-var file_v1_transport_transport_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_v1_transport_transport_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_v1_transport_transport_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_v1_transport_transport_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_v1_transport_transport_proto_goTypes = []interface{}{
-	(Payload_FlagType)(0), // 0: v1_transport.Payload.FlagType
-	(*Payload)(nil),       // 1: v1_transport.Payload
-	(*Error)(nil),         // 2: v1_transport.Error
+	(Payload_FlagType)(0),     // 0: v1_transport.Payload.FlagType
+	(Error_ReasonType)(0),     // 1: v1_transport.Error.ReasonType
+	(*Payload)(nil),           // 2: v1_transport.Payload
+	(*HandshakeRequest)(nil),  // 3: v1_transport.HandshakeRequest
+	(*HandshakeResponse)(nil), // 4: v1_transport.HandshakeResponse
+	(*Error)(nil),             // 5: v1_transport.Error
 }
 var file_v1_transport_transport_proto_depIdxs = []int32{
 	0, // 0: v1_transport.Payload.Flag:type_name -> v1_transport.Payload.FlagType
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1, // 1: v1_transport.Error.Reason:type_name -> v1_transport.Error.ReasonType
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_v1_transport_transport_proto_init() }
@@ -327,6 +727,30 @@ func file_v1_transport_transport_proto_init() {
 			}
 		}
 		file_v1_transport_transport_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandshakeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_transport_transport_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandshakeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_transport_transport_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Error); i {
 			case 0:
 				return &v.state
@@ -339,13 +763,14 @@ func file_v1_transport_transport_proto_init() {
 			}
 		}
 	}
+	file_v1_transport_transport_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_transport_transport_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},